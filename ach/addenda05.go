@@ -0,0 +1,128 @@
+package ach
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/moov-io/ach"
+)
+
+// addenda05InfoLength is the length of the payment related information field
+// of an Addenda05 record.
+const addenda05InfoLength = 80
+
+// mergeAddenda05 combines the Addenda05 records of an entry into a single
+// record whose payment related information is the concatenation of all
+// records in sequence number order. Every record except the last is padded
+// to the full field length, because the fragments are split at fixed
+// positions and trailing spaces are significant. The merged record takes its
+// sequence numbers from the first record. It returns nil if there are no records.
+func mergeAddenda05(records []*ach.Addenda05) []*ach.Addenda05 {
+	sorted := make([]*ach.Addenda05, 0, len(records))
+	for _, addenda := range records {
+		if addenda != nil {
+			sorted = append(sorted, addenda)
+		}
+	}
+	if len(sorted) == 0 {
+		return nil
+	}
+	slices.SortStableFunc(sorted, func(a, b *ach.Addenda05) int {
+		return a.SequenceNumber - b.SequenceNumber
+	})
+
+	var sb strings.Builder
+	for i, addenda := range sorted {
+		info := addenda.PaymentRelatedInformation
+		sb.WriteString(info)
+		if i < len(sorted)-1 {
+			if pad := addenda05InfoLength - utf8.RuneCountInString(info); pad > 0 {
+				sb.WriteString(strings.Repeat(" ", pad))
+			}
+		}
+	}
+
+	merged := *sorted[0]
+	merged.PaymentRelatedInformation = sb.String()
+	return []*ach.Addenda05{&merged}
+}
+
+// splitAddenda05 replaces the Addenda05 records of entry with records built
+// from a merged addenda row. The payment_related_information value is split
+// into 80-character fragments numbered from the row's sequence_number. For
+// CTX and ATX entries, the addenda record count held in the individual name is
+// updated as well.
+func splitAddenda05(entry *ach.EntryDetail, secCode string, record []string, headerIndex map[string]int) {
+	idx, ok := headerIndex["payment_related_information"]
+	if !ok || idx >= len(record) {
+		return
+	}
+
+	existing := make([]*ach.Addenda05, 0, len(entry.Addenda05))
+	for _, addenda := range entry.Addenda05 {
+		if addenda != nil {
+			existing = append(existing, addenda)
+		}
+	}
+	slices.SortStableFunc(existing, func(a, b *ach.Addenda05) int {
+		return a.SequenceNumber - b.SequenceNumber
+	})
+
+	sequenceNumber, entryDetailSequenceNumber := 1, 0
+	if len(existing) > 0 {
+		sequenceNumber = existing[0].SequenceNumber
+		entryDetailSequenceNumber = existing[0].EntryDetailSequenceNumber
+	}
+	if v, err := strconv.Atoi(columnValue(record, headerIndex, "sequence_number")); err == nil {
+		sequenceNumber = v
+	}
+	if v, err := strconv.Atoi(columnValue(record, headerIndex, "entry_detail_sequence_number")); err == nil {
+		entryDetailSequenceNumber = v
+	}
+
+	fragments := splitPaymentInformation(record[idx])
+	split := make([]*ach.Addenda05, len(fragments))
+	for i, fragment := range fragments {
+		addenda := ach.NewAddenda05()
+		if i < len(existing) {
+			addenda = existing[i]
+		}
+		addenda.PaymentRelatedInformation = fragment
+		addenda.SequenceNumber = sequenceNumber + i
+		addenda.EntryDetailSequenceNumber = entryDetailSequenceNumber
+		split[i] = addenda
+	}
+	entry.Addenda05 = split
+	entry.AddendaRecordIndicator = 1
+
+	if (secCode == ach.CTX || secCode == ach.ATX) && len(split) != len(existing) {
+		count := len(split)
+		if entry.Addenda98 != nil {
+			count++
+		}
+		if entry.Addenda99 != nil {
+			count++
+		}
+		entry.SetCATXAddendaRecords(count)
+		entry.AddendaRecordIndicator = 1
+	}
+}
+
+// splitPaymentInformation splits info into fragments of at most 80 characters,
+// trimming each fragment as moov-io/ach does when reading a record. It always
+// returns at least one fragment.
+func splitPaymentInformation(info string) []string {
+	runes := []rune(info)
+	if len(runes) == 0 {
+		return []string{""}
+	}
+
+	var fragments []string
+	for start := 0; start < len(runes); start += addenda05InfoLength {
+		end := min(start+addenda05InfoLength, len(runes))
+		fragments = append(fragments, strings.TrimSpace(string(runes[start:end])))
+	}
+	return fragments
+}
@@ -0,0 +1,166 @@
+package ach
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/moov-io/ach"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createCTXFile builds a CTX file with one entry whose remittance data spans
+// the given Addenda05 fragments.
+func createCTXFile(t *testing.T, fragments []string) *ach.File {
+	t.Helper()
+
+	file := ach.NewFile()
+	file.Header.ImmediateDestination = "231380104"
+	file.Header.ImmediateOrigin = "121042882"
+	file.Header.FileCreationDate = "190624"
+	file.Header.FileCreationTime = "0000"
+	file.Header.FileIDModifier = "A"
+
+	bh := ach.NewBatchHeader()
+	bh.ServiceClassCode = ach.CreditsOnly
+	bh.CompanyName = "Test Company"
+	bh.CompanyIdentification = "121042882"
+	bh.StandardEntryClassCode = ach.CTX
+	bh.CompanyEntryDescription = "REMITTANCE"
+	bh.EffectiveEntryDate = "190625"
+	bh.ODFIIdentification = "12104288"
+
+	entry := ach.NewEntryDetail()
+	entry.TransactionCode = ach.CheckingCredit
+	entry.SetRDFI("231380104")
+	entry.DFIAccountNumber = "12345678"
+	entry.Amount = 100000
+	entry.IdentificationNumber = "45689033"
+	entry.SetCATXAddendaRecords(len(fragments))
+	entry.SetCATXReceivingCompany("Receiver Company")
+	entry.SetTraceNumber("12104288", 1)
+	entry.AddendaRecordIndicator = 1
+
+	for i, fragment := range fragments {
+		addenda := ach.NewAddenda05()
+		addenda.PaymentRelatedInformation = fragment
+		addenda.SequenceNumber = i + 1
+		addenda.EntryDetailSequenceNumber = 1
+		entry.AddAddenda05(addenda)
+	}
+
+	batch, err := ach.NewBatch(bh)
+	require.NoError(t, err)
+	batch.AddEntry(entry)
+	require.NoError(t, batch.Create())
+
+	file.AddBatch(batch)
+	require.NoError(t, file.Create())
+	return file
+}
+
+func TestFromFileWithOptions_MergeAddenda05(t *testing.T) {
+	merged := "ISA*00*          *00*          *ZZ*SENDER         *ZZ*RECEIVER       *190624*" +
+		"1200*U*00401*000000001*0*P*>~GS*RA*SENDER*RECEIVER*20190624*1200*1*X*004010~ST*" +
+		"820*0001~BPR*C*1000*C*ACH*CTX~SE*3*0001~"
+	fragments := []string{merged[:80], merged[80:160], merged[160:]}
+
+	t.Run("merges fragments into one row in sequence order", func(t *testing.T) {
+		file := createCTXFile(t, fragments)
+
+		ts := FromFileWithOptions(file, FromFileOptions{MergeAddenda05: true})
+		require.NotNil(t, ts)
+
+		require.Len(t, ts.Addenda.Records, 1)
+		headerIndex := make(map[string]int)
+		for i, h := range ts.Addenda.Headers {
+			headerIndex[h] = i
+		}
+		record := ts.Addenda.Records[0]
+		assert.Equal(t, merged, columnValue(record, headerIndex, "payment_related_information"))
+		assert.Equal(t, "1", columnValue(record, headerIndex, "sequence_number"))
+	})
+
+	t.Run("keeps one row per record without the option", func(t *testing.T) {
+		file := createCTXFile(t, fragments)
+
+		ts := FromFile(file)
+		require.NotNil(t, ts)
+
+		assert.Len(t, ts.Addenda.Records, 3)
+	})
+
+	t.Run("splits the merged value back on ToFile", func(t *testing.T) {
+		file := createCTXFile(t, fragments)
+
+		ts := FromFileWithOptions(file, FromFileOptions{MergeAddenda05: true})
+		require.NotNil(t, ts)
+
+		newFile, err := ts.ToFile()
+		require.NoError(t, err)
+
+		entry := newFile.Batches[0].GetEntries()[0]
+		require.Len(t, entry.Addenda05, 3)
+		for i, addenda := range entry.Addenda05 {
+			assert.Equal(t, fragments[i], addenda.PaymentRelatedInformation)
+			assert.Equal(t, i+1, addenda.SequenceNumber)
+			assert.Equal(t, 1, addenda.EntryDetailSequenceNumber)
+		}
+		assert.Equal(t, "0003", entry.CATXAddendaRecordsField())
+	})
+
+	t.Run("re-splits a modified merged value", func(t *testing.T) {
+		file := createCTXFile(t, fragments)
+
+		ts := FromFileWithOptions(file, FromFileOptions{MergeAddenda05: true})
+		require.NotNil(t, ts)
+
+		headerIndex := make(map[string]int)
+		for i, h := range ts.Addenda.Headers {
+			headerIndex[h] = i
+		}
+		shorter := strings.Repeat("A", 80) + "TAIL"
+		ts.Addenda.Records[0][headerIndex["payment_related_information"]] = shorter
+
+		newFile, err := ts.ToFile()
+		require.NoError(t, err)
+
+		entry := newFile.Batches[0].GetEntries()[0]
+		require.Len(t, entry.Addenda05, 2)
+		assert.Equal(t, strings.Repeat("A", 80), entry.Addenda05[0].PaymentRelatedInformation)
+		assert.Equal(t, "TAIL", entry.Addenda05[1].PaymentRelatedInformation)
+		assert.Equal(t, 2, entry.Addenda05[1].SequenceNumber)
+		assert.Equal(t, "0002", entry.CATXAddendaRecordsField())
+	})
+}
+
+func TestMergeAddenda05(t *testing.T) {
+	t.Run("orders fragments by sequence number and pads short ones", func(t *testing.T) {
+		second := ach.NewAddenda05()
+		second.PaymentRelatedInformation = "SECOND"
+		second.SequenceNumber = 2
+		first := ach.NewAddenda05()
+		first.PaymentRelatedInformation = "FIRST"
+		first.SequenceNumber = 1
+
+		merged := mergeAddenda05([]*ach.Addenda05{second, nil, first})
+
+		require.Len(t, merged, 1)
+		assert.Equal(t, "FIRST"+strings.Repeat(" ", 75)+"SECOND", merged[0].PaymentRelatedInformation)
+		assert.Equal(t, 1, merged[0].SequenceNumber)
+		assert.Equal(t, "FIRST", first.PaymentRelatedInformation, "input records must not be modified")
+	})
+
+	t.Run("returns nil without records", func(t *testing.T) {
+		assert.Nil(t, mergeAddenda05(nil))
+	})
+}
+
+func TestSplitPaymentInformation(t *testing.T) {
+	assert.Equal(t, []string{""}, splitPaymentInformation(""))
+	assert.Equal(t, []string{"short"}, splitPaymentInformation("short"))
+	assert.Equal(t,
+		[]string{strings.Repeat("x", 80), "y"},
+		splitPaymentInformation(strings.Repeat("x", 80)+"y"),
+	)
+}
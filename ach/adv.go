@@ -0,0 +1,297 @@
+package ach
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/moov-io/ach"
+	"github.com/nao1215/fileparser"
+)
+
+// isADVBatch reports whether batch is an ADV (Automated Accounting Advice)
+// batch, which holds ADV entries and an ADV batch control instead of the
+// standard ones.
+func isADVBatch(batch ach.Batcher) bool {
+	header := batch.GetHeader()
+	return header != nil && header.StandardEntryClassCode == ach.ADV
+}
+
+// convertADVBatches extracts ADV batch information into TableData.
+// batch_index is the index of the batch in file.Batches, as in the batches
+// table, which does not list ADV batches.
+func convertADVBatches(file *ach.File) *fileparser.TableData {
+	headers := []string{
+		"batch_index",
+		"service_class_code",
+		"company_name",
+		"company_discretionary_data",
+		"company_identification",
+		"standard_entry_class_code",
+		"company_entry_description",
+		"company_descriptive_date",
+		"effective_entry_date",
+		"originator_status_code",
+		"odfi_identification",
+		"batch_number",
+		// ADV control fields
+		"entry_addenda_count",
+		"entry_hash",
+		"total_debit",
+		"total_credit",
+		"ach_operator_data",
+	}
+
+	columnTypes := []fileparser.ColumnType{
+		fileparser.TypeInteger, // batch_index
+		fileparser.TypeInteger, // service_class_code
+		fileparser.TypeText,    // company_name
+		fileparser.TypeText,    // company_discretionary_data
+		fileparser.TypeText,    // company_identification
+		fileparser.TypeText,    // standard_entry_class_code
+		fileparser.TypeText,    // company_entry_description
+		fileparser.TypeText,    // company_descriptive_date
+		fileparser.TypeText,    // effective_entry_date
+		fileparser.TypeInteger, // originator_status_code
+		fileparser.TypeText,    // odfi_identification
+		fileparser.TypeInteger, // batch_number
+		fileparser.TypeInteger, // entry_addenda_count
+		fileparser.TypeInteger, // entry_hash
+		fileparser.TypeInteger, // total_debit
+		fileparser.TypeInteger, // total_credit
+		fileparser.TypeText,    // ach_operator_data
+	}
+
+	var records [][]string
+	for i, batch := range file.Batches {
+		if !isADVBatch(batch) {
+			continue
+		}
+		bh := batch.GetHeader()
+		bc := batch.GetADVControl()
+		if bc == nil {
+			bc = ach.NewADVBatchControl()
+		}
+
+		record := []string{
+			strconv.Itoa(i),
+			strconv.Itoa(bh.ServiceClassCode),
+			strings.TrimSpace(bh.CompanyName),
+			strings.TrimSpace(bh.CompanyDiscretionaryData),
+			strings.TrimSpace(bh.CompanyIdentification),
+			bh.StandardEntryClassCode,
+			strings.TrimSpace(bh.CompanyEntryDescription),
+			strings.TrimSpace(bh.CompanyDescriptiveDate),
+			bh.EffectiveEntryDate,
+			strconv.Itoa(bh.OriginatorStatusCode),
+			bh.ODFIIdentification,
+			strconv.Itoa(bh.BatchNumber),
+			strconv.Itoa(bc.EntryAddendaCount),
+			strconv.Itoa(bc.EntryHash),
+			strconv.Itoa(bc.TotalDebitEntryDollarAmount),
+			strconv.Itoa(bc.TotalCreditEntryDollarAmount),
+			strings.TrimSpace(bc.ACHOperatorData),
+		}
+		records = append(records, record)
+	}
+
+	if len(records) == 0 {
+		records = [][]string{}
+	}
+
+	return &fileparser.TableData{
+		Headers:     headers,
+		Records:     records,
+		ColumnTypes: columnTypes,
+	}
+}
+
+// convertADVEntries extracts ADV entry detail records into TableData.
+func convertADVEntries(file *ach.File) *fileparser.TableData {
+	headers := []string{
+		"batch_index",
+		"entry_index",
+		"transaction_code",
+		"rdfi_identification",
+		"check_digit",
+		"dfi_account_number",
+		"amount",
+		"advice_routing_number",
+		"file_identification",
+		"ach_operator_data",
+		"individual_name",
+		"discretionary_data",
+		"addenda_record_indicator",
+		"ach_operator_routing_number",
+		"julian_day",
+		"sequence_number",
+		"category",
+	}
+
+	columnTypes := []fileparser.ColumnType{
+		fileparser.TypeInteger, // batch_index
+		fileparser.TypeInteger, // entry_index
+		fileparser.TypeInteger, // transaction_code
+		fileparser.TypeText,    // rdfi_identification
+		fileparser.TypeText,    // check_digit
+		fileparser.TypeText,    // dfi_account_number
+		fileparser.TypeInteger, // amount (in cents)
+		fileparser.TypeText,    // advice_routing_number
+		fileparser.TypeText,    // file_identification
+		fileparser.TypeText,    // ach_operator_data
+		fileparser.TypeText,    // individual_name
+		fileparser.TypeText,    // discretionary_data
+		fileparser.TypeInteger, // addenda_record_indicator
+		fileparser.TypeText,    // ach_operator_routing_number
+		fileparser.TypeInteger, // julian_day
+		fileparser.TypeInteger, // sequence_number
+		fileparser.TypeText,    // category
+	}
+
+	var records [][]string
+	for batchIdx, batch := range file.Batches {
+		for entryIdx, entry := range batch.GetADVEntries() {
+			record := []string{
+				strconv.Itoa(batchIdx),
+				strconv.Itoa(entryIdx),
+				strconv.Itoa(entry.TransactionCode),
+				entry.RDFIIdentification,
+				entry.CheckDigit,
+				strings.TrimSpace(entry.DFIAccountNumber),
+				strconv.Itoa(entry.Amount),
+				entry.AdviceRoutingNumber,
+				strings.TrimSpace(entry.FileIdentification),
+				strings.TrimSpace(entry.ACHOperatorData),
+				strings.TrimSpace(entry.IndividualName),
+				strings.TrimSpace(entry.DiscretionaryData),
+				strconv.Itoa(entry.AddendaRecordIndicator),
+				entry.ACHOperatorRoutingNumber,
+				strconv.Itoa(entry.JulianDay),
+				strconv.Itoa(entry.SequenceNumber),
+				entry.Category,
+			}
+			records = append(records, record)
+		}
+	}
+
+	if len(records) == 0 {
+		records = [][]string{}
+	}
+
+	return &fileparser.TableData{
+		Headers:     headers,
+		Records:     records,
+		ColumnTypes: columnTypes,
+	}
+}
+
+// applyADVBatchModifications updates ADV batch header fields and the ACH
+// operator data of the ADV batch control from TableData. The control totals
+// are read-only.
+func (ts *TableSet) applyADVBatchModifications(file *ach.File) error {
+	headerIndex := make(map[string]int)
+	for i, h := range ts.ADVBatches.Headers {
+		headerIndex[h] = i
+	}
+
+	for _, record := range ts.ADVBatches.Records {
+		batchIdx, err := strconv.Atoi(record[headerIndex["batch_index"]])
+		if err != nil {
+			return fmt.Errorf("invalid batch_index: %w", err)
+		}
+		if batchIdx < 0 || batchIdx >= len(file.Batches) || !isADVBatch(file.Batches[batchIdx]) {
+			return fmt.Errorf("batch_index %d is not an ADV batch", batchIdx)
+		}
+
+		batch := file.Batches[batchIdx]
+		setBatchHeaderFields(batch.GetHeader(), record, headerIndex)
+		if idx, ok := headerIndex["ach_operator_data"]; ok && idx < len(record) && batch.GetADVControl() != nil {
+			batch.GetADVControl().ACHOperatorData = record[idx]
+		}
+	}
+
+	return nil
+}
+
+// applyADVEntryModifications updates ADV entries in the ACH file from
+// TableData. sequence_number is read-only.
+func (ts *TableSet) applyADVEntryModifications(file *ach.File) error {
+	headerIndex := make(map[string]int)
+	for i, h := range ts.ADVEntries.Headers {
+		headerIndex[h] = i
+	}
+
+	for _, record := range ts.ADVEntries.Records {
+		batchIdx, err := strconv.Atoi(record[headerIndex["batch_index"]])
+		if err != nil {
+			return fmt.Errorf("invalid batch_index: %w", err)
+		}
+		entryIdx, err := strconv.Atoi(record[headerIndex["entry_index"]])
+		if err != nil {
+			return fmt.Errorf("invalid entry_index: %w", err)
+		}
+
+		if batchIdx < 0 || batchIdx >= len(file.Batches) {
+			return fmt.Errorf("batch_index %d out of range", batchIdx)
+		}
+		entries := file.Batches[batchIdx].GetADVEntries()
+		if entryIdx < 0 || entryIdx >= len(entries) {
+			return fmt.Errorf("entry_index %d out of range for ADV batch %d", entryIdx, batchIdx)
+		}
+
+		entry := entries[entryIdx]
+
+		if idx, ok := headerIndex["transaction_code"]; ok {
+			if v, err := strconv.Atoi(record[idx]); err == nil {
+				entry.TransactionCode = v
+			}
+		}
+		if idx, ok := headerIndex["rdfi_identification"]; ok {
+			entry.RDFIIdentification = record[idx]
+		}
+		if idx, ok := headerIndex["check_digit"]; ok {
+			entry.CheckDigit = record[idx]
+		}
+		if idx, ok := headerIndex["dfi_account_number"]; ok {
+			entry.DFIAccountNumber = record[idx]
+		}
+		if idx, ok := headerIndex["amount"]; ok {
+			if v, err := strconv.Atoi(record[idx]); err == nil {
+				entry.Amount = v
+			}
+		}
+		if idx, ok := headerIndex["advice_routing_number"]; ok {
+			entry.AdviceRoutingNumber = record[idx]
+		}
+		if idx, ok := headerIndex["file_identification"]; ok {
+			entry.FileIdentification = record[idx]
+		}
+		if idx, ok := headerIndex["ach_operator_data"]; ok {
+			entry.ACHOperatorData = record[idx]
+		}
+		if idx, ok := headerIndex["individual_name"]; ok {
+			entry.IndividualName = record[idx]
+		}
+		if idx, ok := headerIndex["discretionary_data"]; ok {
+			entry.DiscretionaryData = record[idx]
+		}
+		if idx, ok := headerIndex["addenda_record_indicator"]; ok {
+			if v, err := strconv.Atoi(record[idx]); err == nil {
+				entry.AddendaRecordIndicator = v
+			}
+		}
+		if idx, ok := headerIndex["ach_operator_routing_number"]; ok {
+			entry.ACHOperatorRoutingNumber = record[idx]
+		}
+		if idx, ok := headerIndex["julian_day"]; ok {
+			if v, err := strconv.Atoi(record[idx]); err == nil {
+				entry.JulianDay = v
+			}
+		}
+		if idx, ok := headerIndex["category"]; ok && record[idx] != "" {
+			entry.Category = record[idx]
+		}
+	}
+
+	return nil
+}
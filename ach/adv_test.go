@@ -0,0 +1,93 @@
+package ach
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/moov-io/ach"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFromFile_WithADVBatch tests converting ADV batches and entries
+func TestFromFile_WithADVBatch(t *testing.T) {
+	file, err := ach.ReadFile(findTestFile(t, "adv.ach"))
+	require.NoError(t, err)
+
+	ts := FromFile(file)
+	require.NotNil(t, ts)
+
+	assert.Empty(t, ts.Batches.Records)
+	assert.Empty(t, ts.Entries.Records)
+
+	require.NotNil(t, ts.ADVBatches)
+	require.Len(t, ts.ADVBatches.Records, 1)
+	batch := rowMap(ts.ADVBatches.Headers, ts.ADVBatches.Records[0])
+	assert.Equal(t, "ADV", batch["standard_entry_class_code"])
+	assert.Equal(t, "2", batch["entry_addenda_count"])
+	assert.Equal(t, "50000", batch["total_credit"])
+	assert.Equal(t, "Company Name, Inc", batch["ach_operator_data"])
+
+	require.NotNil(t, ts.ADVEntries)
+	require.Len(t, ts.ADVEntries.Records, 2)
+	entry := rowMap(ts.ADVEntries.Headers, ts.ADVEntries.Records[1])
+	assert.Equal(t, "82", entry["transaction_code"])
+	assert.Equal(t, "744-5678-99", entry["dfi_account_number"])
+	assert.Equal(t, "250000", entry["amount"])
+	assert.Equal(t, "121042882", entry["advice_routing_number"])
+	assert.Equal(t, "Name", entry["individual_name"])
+	assert.Equal(t, "2", entry["sequence_number"])
+}
+
+// TestFromFile_WithoutADVBatch tests that ADV tables are only created for ADV files
+func TestFromFile_WithoutADVBatch(t *testing.T) {
+	file, err := ach.ReadFile(findTestACHFile(t))
+	require.NoError(t, err)
+
+	ts := FromFile(file)
+	assert.Nil(t, ts.ADVBatches)
+	assert.Nil(t, ts.ADVEntries)
+}
+
+// TestADVRoundTrip tests that an unmodified ADV file is written back unchanged
+func TestADVRoundTrip(t *testing.T) {
+	path := findTestFile(t, "adv.ach")
+	original, err := os.ReadFile(path)
+	require.NoError(t, err)
+	file, err := ach.ReadFile(path)
+	require.NoError(t, err)
+
+	newFile, err := FromFile(file).ToFile()
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, ach.NewWriter(&buf).Write(newFile))
+	assert.Equal(t, string(original), buf.String())
+}
+
+// TestModifyADV tests modifying ADV batch and entry fields
+func TestModifyADV(t *testing.T) {
+	file, err := ach.ReadFile(findTestFile(t, "adv.ach"))
+	require.NoError(t, err)
+
+	ts := FromFile(file)
+	setCell(t, ts.ADVBatches, 0, "company_name", "New Company")
+	setCell(t, ts.ADVBatches, 0, "ach_operator_data", "Operator Data")
+	setCell(t, ts.ADVEntries, 1, "individual_name", "New Name")
+	setCell(t, ts.ADVEntries, 1, "julian_day", "140")
+
+	newFile, err := ts.ToFile()
+	require.NoError(t, err)
+
+	batch := newFile.Batches[0]
+	assert.Equal(t, "New Company", batch.GetHeader().CompanyName)
+	assert.Equal(t, "Operator Data", batch.GetADVControl().ACHOperatorData)
+	entry := batch.GetADVEntries()[1]
+	assert.Equal(t, "New Name", entry.IndividualName)
+	assert.Equal(t, 140, entry.JulianDay)
+
+	// The original file is untouched
+	assert.Equal(t, "Name", strings.TrimSpace(file.Batches[0].GetADVEntries()[1].IndividualName))
+}
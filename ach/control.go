@@ -0,0 +1,290 @@
+package ach
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/moov-io/ach"
+	"github.com/nao1215/fileparser"
+)
+
+// controlTotals holds the values of a batch or file control record that are
+// derived from the entries and addenda.
+type controlTotals struct {
+	entryAddendaCount int
+	entryHash         int
+	totalDebit        int
+	totalCredit       int
+}
+
+// add accumulates one entry with addendaCount addenda records into the totals.
+func (c *controlTotals) add(rdfiIdentification string, transactionCode, amount, addendaCount int) {
+	c.entryAddendaCount += 1 + addendaCount
+	c.entryHash = (c.entryHash + routingHash(rdfiIdentification)) % entryHashModulus
+	if signedAmount(transactionCode, 1) < 0 {
+		c.totalDebit += amount
+	} else {
+		c.totalCredit += amount
+	}
+}
+
+// merge adds the totals of a batch to file-level totals.
+func (c *controlTotals) merge(other controlTotals) {
+	c.entryAddendaCount += other.entryAddendaCount
+	c.entryHash = (c.entryHash + other.entryHash) % entryHashModulus
+	c.totalDebit += other.totalDebit
+	c.totalCredit += other.totalCredit
+}
+
+// compare returns one error per field where the stored control value differs
+// from the computed one. prefix names the record being checked.
+func (c controlTotals) compare(prefix string, entryAddendaCount, entryHash, totalDebit, totalCredit int) []error {
+	var errs []error
+	check := func(field string, stored, computed int) {
+		if stored != computed {
+			errs = append(errs, fmt.Errorf("%s: %s is %d, but the entries produce %d", prefix, field, stored, computed))
+		}
+	}
+	check("entry/addenda count", entryAddendaCount, c.entryAddendaCount)
+	check("entry hash", entryHash, c.entryHash)
+	check("total debit amount", totalDebit, c.totalDebit)
+	check("total credit amount", totalCredit, c.totalCredit)
+	return errs
+}
+
+// routingHash returns the part of an RDFI routing number that contributes to
+// the entry hash: its first 8 digits. Non-numeric values contribute zero, as
+// in moov-io/ach.
+func routingHash(rdfiIdentification string) int {
+	if len(rdfiIdentification) > 8 {
+		rdfiIdentification = rdfiIdentification[:8]
+	}
+	n, err := strconv.Atoi(rdfiIdentification)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// ControlCheck verifies that the control records stored in the ACH file the
+// TableSet was created from match its entries and addenda. For every batch
+// and for the file control, it compares the entry/addenda count, the entry
+// hash, and the debit and credit totals with the values the entries produce,
+// and reports every discrepancy in the returned error. The file control
+// additionally has its batch count checked.
+//
+// Unlike ToFile, which regenerates the control records, ControlCheck only
+// reads the original file, so it detects corrupt or tampered input whose
+// trailers do not match the body. Modifications made to the tables are not
+// considered. ADV batches are not checked.
+//
+// Example:
+//
+//	if err := ts.ControlCheck(); err != nil {
+//	    log.Printf("control records do not match: %v", err)
+//	}
+func (ts *TableSet) ControlCheck() error {
+	if ts == nil || ts.originalFile == nil {
+		return errors.New("no original ACH file available")
+	}
+	file := ts.originalFile
+
+	var (
+		errs       []error
+		fileTotals controlTotals
+		batchCount int
+	)
+	for _, batch := range file.Batches {
+		header := batch.GetHeader()
+		if header == nil || header.StandardEntryClassCode == ach.ADV {
+			continue
+		}
+		batchCount++
+
+		var totals controlTotals
+		for _, entry := range batch.GetEntries() {
+			totals.add(entry.RDFIIdentification, entry.TransactionCode, entry.Amount, addendaCount(entry))
+		}
+		fileTotals.merge(totals)
+
+		prefix := fmt.Sprintf("batch %d", header.BatchNumber)
+		control := batch.GetControl()
+		if control == nil {
+			errs = append(errs, fmt.Errorf("%s: missing batch control", prefix))
+			continue
+		}
+		errs = append(errs, totals.compare(prefix,
+			control.EntryAddendaCount, control.EntryHash,
+			control.TotalDebitEntryDollarAmount, control.TotalCreditEntryDollarAmount)...)
+	}
+
+	for _, iatBatch := range file.IATBatches {
+		batchCount++
+
+		var totals controlTotals
+		for _, entry := range iatBatch.GetEntries() {
+			totals.add(entry.RDFIIdentification, entry.TransactionCode, entry.Amount, iatAddendaCount(entry))
+		}
+		fileTotals.merge(totals)
+
+		batchNumber := 0
+		if header := iatBatch.GetHeader(); header != nil {
+			batchNumber = header.BatchNumber
+		}
+		prefix := fmt.Sprintf("IAT batch %d", batchNumber)
+		control := iatBatch.GetControl()
+		if control == nil {
+			errs = append(errs, fmt.Errorf("%s: missing batch control", prefix))
+			continue
+		}
+		errs = append(errs, totals.compare(prefix,
+			control.EntryAddendaCount, control.EntryHash,
+			control.TotalDebitEntryDollarAmount, control.TotalCreditEntryDollarAmount)...)
+	}
+
+	if batchCount > 0 {
+		control := file.Control
+		if control.BatchCount != batchCount {
+			errs = append(errs, fmt.Errorf("file control: batch count is %d, but the file has %d batches",
+				control.BatchCount, batchCount))
+		}
+		errs = append(errs, fileTotals.compare("file control",
+			control.EntryAddendaCount, control.EntryHash,
+			control.TotalDebitEntryDollarAmountInFile, control.TotalCreditEntryDollarAmountInFile)...)
+	}
+
+	return errors.Join(errs...)
+}
+
+// RecomputeTotals previews the control totals ToFile would need for the
+// current tables. It applies the table modifications to a copy of the
+// original file, rebuilds every batch with moov-io/ach's Create, and returns
+// one row per batch with the recomputed entry_addenda_count, entry_hash,
+// total_debit, and total_credit. The original file and the tables are not
+// changed.
+//
+// batch_type is "standard", "adv", or "iat", and batch_index is the index of
+// the batch in the batches, adv_batches, or iat_batches table respectively.
+// An error is returned when the modifications cannot be applied or a batch
+// cannot be rebuilt, e.g. because an entry is invalid.
+//
+// Example:
+//
+//	totals, err := ts.RecomputeTotals()
+//	if err != nil {
+//	    return err
+//	}
+//	for _, row := range totals.Records {
+//	    fmt.Println(row)
+//	}
+func (ts *TableSet) RecomputeTotals() (*fileparser.TableData, error) {
+	file, err := ts.applyModifications()
+	if err != nil {
+		return nil, err
+	}
+
+	records := [][]string{}
+	for i, batch := range file.Batches {
+		if err := batch.Create(); err != nil {
+			return nil, fmt.Errorf("failed to rebuild batch %d: %w", i, err)
+		}
+		if isADVBatch(batch) {
+			control := batch.GetADVControl()
+			records = append(records, totalsRecord("adv", i, control.BatchNumber, control.EntryAddendaCount,
+				control.EntryHash, control.TotalDebitEntryDollarAmount, control.TotalCreditEntryDollarAmount))
+			continue
+		}
+		control := batch.GetControl()
+		records = append(records, totalsRecord("standard", i, control.BatchNumber, control.EntryAddendaCount,
+			control.EntryHash, control.TotalDebitEntryDollarAmount, control.TotalCreditEntryDollarAmount))
+	}
+	for i := range file.IATBatches {
+		iatBatch := &file.IATBatches[i]
+		if err := iatBatch.Create(); err != nil {
+			return nil, fmt.Errorf("failed to rebuild IAT batch %d: %w", i, err)
+		}
+		control := iatBatch.GetControl()
+		records = append(records, totalsRecord("iat", i, control.BatchNumber, control.EntryAddendaCount,
+			control.EntryHash, control.TotalDebitEntryDollarAmount, control.TotalCreditEntryDollarAmount))
+	}
+
+	return &fileparser.TableData{
+		Headers: []string{
+			"batch_type",
+			"batch_index",
+			"batch_number",
+			"entry_addenda_count",
+			"entry_hash",
+			"total_debit",
+			"total_credit",
+		},
+		Records: records,
+		ColumnTypes: []fileparser.ColumnType{
+			fileparser.TypeText,    // batch_type
+			fileparser.TypeInteger, // batch_index
+			fileparser.TypeInteger, // batch_number
+			fileparser.TypeInteger, // entry_addenda_count
+			fileparser.TypeInteger, // entry_hash
+			fileparser.TypeInteger, // total_debit (in cents)
+			fileparser.TypeInteger, // total_credit (in cents)
+		},
+	}, nil
+}
+
+// totalsRecord returns a RecomputeTotals row.
+func totalsRecord(batchType string, batchIdx, batchNumber, entryAddendaCount, entryHash, totalDebit, totalCredit int) []string {
+	return []string{
+		batchType,
+		strconv.Itoa(batchIdx),
+		strconv.Itoa(batchNumber),
+		strconv.Itoa(entryAddendaCount),
+		strconv.Itoa(entryHash),
+		strconv.Itoa(totalDebit),
+		strconv.Itoa(totalCredit),
+	}
+}
+
+// addendaCount returns the number of addenda records attached to entry.
+func addendaCount(entry *ach.EntryDetail) int {
+	n := 0
+	for _, present := range []bool{
+		entry.Addenda02 != nil,
+		entry.Addenda98 != nil,
+		entry.Addenda98Refused != nil,
+		entry.Addenda99 != nil,
+		entry.Addenda99Dishonored != nil,
+		entry.Addenda99Contested != nil,
+	} {
+		if present {
+			n++
+		}
+	}
+	for _, addenda := range entry.Addenda05 {
+		if addenda != nil {
+			n++
+		}
+	}
+	return n
+}
+
+// iatAddendaCount returns the number of addenda records attached to an IAT entry.
+func iatAddendaCount(entry *ach.IATEntryDetail) int {
+	n := len(entry.Addenda17) + len(entry.Addenda18)
+	for _, present := range []bool{
+		entry.Addenda10 != nil,
+		entry.Addenda11 != nil,
+		entry.Addenda12 != nil,
+		entry.Addenda13 != nil,
+		entry.Addenda14 != nil,
+		entry.Addenda15 != nil,
+		entry.Addenda16 != nil,
+		entry.Addenda98 != nil,
+		entry.Addenda99 != nil,
+	} {
+		if present {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,101 @@
+package ach
+
+import (
+	"testing"
+
+	"github.com/moov-io/ach"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestControlCheck(t *testing.T) {
+	t.Run("accepts files whose controls match", func(t *testing.T) {
+		for _, name := range []string{"ppd-debit.ach", "cor-example.ach", "iat-credit.ach", "pos-debit.ach", "return-WEB.ach"} {
+			file, err := ach.ReadFile(findTestFile(t, name))
+			require.NoError(t, err, name)
+
+			assert.NoError(t, FromFile(file).ControlCheck(), name)
+		}
+	})
+
+	t.Run("flags a wrong batch control total", func(t *testing.T) {
+		file := createCTXFile(t, []string{"REMITTANCE"})
+		file.Batches[0].GetControl().TotalCreditEntryDollarAmount = 999
+
+		err := FromFile(file).ControlCheck()
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "batch 1: total credit amount is 999, but the entries produce 100000")
+		assert.NotContains(t, err.Error(), "file control")
+	})
+
+	t.Run("flags a file control that does not match the batches", func(t *testing.T) {
+		file := createCTXFile(t, []string{"REMITTANCE"})
+		file.Control.EntryAddendaCount = 5
+		file.Control.BatchCount = 2
+
+		err := FromFile(file).ControlCheck()
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "file control: entry/addenda count is 5, but the entries produce 2")
+		assert.Contains(t, err.Error(), "file control: batch count is 2, but the file has 1 batches")
+	})
+
+	t.Run("flags an entry changed after the controls were built", func(t *testing.T) {
+		file := createCTXFile(t, []string{"REMITTANCE"})
+		file.Batches[0].GetEntries()[0].Amount = 100001
+
+		err := FromFile(file).ControlCheck()
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "batch 1: total credit amount is 100000, but the entries produce 100001")
+		assert.Contains(t, err.Error(), "file control: total credit amount is 100000, but the entries produce 100001")
+	})
+
+	t.Run("returns an error without an original file", func(t *testing.T) {
+		var ts *TableSet
+		assert.Error(t, ts.ControlCheck())
+	})
+}
+
+func TestRecomputeTotals(t *testing.T) {
+	t.Run("reflects modified amounts without changing the original file", func(t *testing.T) {
+		file, err := ach.ReadFile(findTestACHFile(t))
+		require.NoError(t, err)
+
+		ts := FromFile(file)
+		setCell(t, ts.Entries, 0, "amount", "12345")
+
+		totals, err := ts.RecomputeTotals()
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"batch_type", "batch_index", "batch_number", "entry_addenda_count", "entry_hash", "total_debit", "total_credit",
+		}, totals.Headers)
+		assert.Equal(t, [][]string{{"standard", "0", "1", "1", "23138010", "12345", "0"}}, totals.Records)
+
+		assert.Equal(t, 100000000, file.Batches[0].GetControl().TotalDebitEntryDollarAmount)
+		assert.Equal(t, 100000000, file.Batches[0].GetEntries()[0].Amount)
+	})
+
+	t.Run("includes IAT and ADV batches", func(t *testing.T) {
+		iatFile, err := ach.ReadFile(findTestFile(t, "iat-credit.ach"))
+		require.NoError(t, err)
+		totals, err := FromFile(iatFile).RecomputeTotals()
+		require.NoError(t, err)
+		require.NotEmpty(t, totals.Records)
+		assert.Equal(t, "iat", totals.Records[0][0])
+
+		advFile, err := ach.ReadFile(findTestFile(t, "adv.ach"))
+		require.NoError(t, err)
+		totals, err = FromFile(advFile).RecomputeTotals()
+		require.NoError(t, err)
+		require.Len(t, totals.Records, 1)
+		assert.Equal(t, []string{"adv", "0", "1", "2"}, totals.Records[0][:4])
+	})
+
+	t.Run("returns an error without an original file", func(t *testing.T) {
+		var ts *TableSet
+		_, err := ts.RecomputeTotals()
+		assert.Error(t, err)
+	})
+}
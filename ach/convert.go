@@ -7,7 +7,8 @@
 //
 // TableData structures expose sensitive banking information including account numbers,
 // routing numbers, names, and transaction amounts. Avoid logging or exporting
-// TableData contents verbatim in production environments.
+// TableData contents verbatim in production environments, or create the
+// TableSet with FromFileOptions.MaskAccounts to mask account numbers.
 //
 // # Supported Addenda Types
 //
@@ -34,10 +35,11 @@
 //
 // # Limitations
 //
-// Only UPDATE operations on existing rows are supported for round-trip editing.
-// INSERT/DELETE operations in SQL are not reflected in the output ACH file.
-// This is because ACH file structure requires careful coordination between
-// related records (entry counts, hash totals, addenda indicators).
+// Round-trip editing supports UPDATE of existing rows in every table, and
+// INSERT and DELETE of rows in the entries table (see TableSet). Other INSERT
+// and DELETE operations in SQL are not reflected in the output ACH file, because
+// ACH file structure requires careful coordination between related records
+// (entry counts, hash totals, addenda indicators).
 //
 // This package uses github.com/tiendc/go-deepcopy for deep copying ACH files.
 // If moov-io/ach adds new fields (especially interfaces or unexported fields),
@@ -61,9 +63,12 @@
 package ach
 
 import (
+	"bytes"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"io"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -82,12 +87,27 @@ const (
 // TableSet contains multiple TableData representing different aspects of an ACH file.
 // This structure preserves the hierarchical nature of ACH files while enabling
 // flat table-based queries.
+//
+// A row appended to Entries becomes a new entry of its batch when ToFile is
+// called. Its entry_index must be the batch's current entry count (0-based),
+// so the first new entry of a batch with 3 entries uses entry_index 3, the
+// next one 4, and so on. A valid new entry needs batch_index, entry_index,
+// transaction_code, rdfi_identification, check_digit, dfi_account_number,
+// amount, and individual_name. An empty trace_number is generated from the
+// batch's ODFI and the entry's position, and an empty category defaults to
+// "Forward".
+//
+// Deleting a row from Entries removes that entry, with its addenda, from
+// the file. A batch must keep at least one entry. Entries left out of a
+// TableSet created with FromFileFiltered are kept, not deleted. The batch control totals
+// are recalculated for batches that gain or lose entries.
 type TableSet struct {
 	// FileHeader contains file-level header information (1 row per file)
 	FileHeader *fileparser.TableData
 	// Batches contains batch header information
 	Batches *fileparser.TableData
-	// Entries contains entry detail records (the main transaction data)
+	// Entries contains entry detail records (the main transaction data).
+	// Its PrimaryKey is trace_number.
 	Entries *fileparser.TableData
 	// Addenda contains addenda records associated with entries
 	Addenda *fileparser.TableData
@@ -99,8 +119,43 @@ type TableSet struct {
 	// IATAddenda contains IAT addenda records (types 10-18, 98, 99)
 	IATAddenda *fileparser.TableData
 
+	// ADVBatches contains ADV (Automated Accounting Advice) batch headers with
+	// their ADV control totals
+	ADVBatches *fileparser.TableData
+	// ADVEntries contains ADV entry detail records
+	ADVEntries *fileparser.TableData
+
 	// originalFile stores the original ACH file for reconstruction
 	originalFile *ach.File
+	// options holds the options the TableSet was created with
+	options FromFileOptions
+	// maskedValues holds the original values of cells masked by
+	// FromFileOptions.MaskAccounts
+	maskedValues map[maskedCell]string
+}
+
+// FromFileOptions configures FromFileWithOptions.
+type FromFileOptions struct {
+	// MergeAddenda05 produces one addenda row per entry for its Addenda05
+	// records instead of one row per record. The payment_related_information
+	// values are concatenated in sequence number order, so CTX and CCD+
+	// remittance data that spans several records reads as one value.
+	// ToFile splits the merged value back into 80-character Addenda05 records.
+	MergeAddenda05 bool
+
+	// MaskAccounts replaces all but the last 4 characters of account-bearing
+	// values with '*': dfi_account_number in the entries, IAT entries, and
+	// ADV entries tables, and original_trace, original_entry_trace_number,
+	// and corrected_data in the addenda and IAT addenda tables. The original
+	// values are kept inside the TableSet, so ToFile writes them back unless
+	// the masked value was modified.
+	MaskAccounts bool
+
+	// Categories limits the entries, IAT entries, and ADV entries tables to
+	// entries whose Category is one of the given values, e.g.
+	// ach.CategoryReturn, and the addenda tables to the addenda of those
+	// entries. Empty includes every entry. See FromFileFiltered.
+	Categories []string
 }
 
 // FromFile converts an ACH file to a set of TableData structures.
@@ -117,23 +172,36 @@ type TableSet struct {
 //   - iat_entries: IAT entry details
 //   - iat_addenda: IAT addenda records (types 10-18, 98, 99)
 //
+// Tables created for ADV (Automated Accounting Advice) batches, which are not
+// listed in the batches table:
+//   - adv_batches: ADV batch headers with ADV control totals
+//   - adv_entries: ADV entry details
+//
 // Note: The TableSet stores a reference to the original file (not a copy).
 // If you modify the passed-in *ach.File after calling FromFile, the changes
 // will be reflected when calling ToFile(). ToFile() creates a deep copy
 // before applying TableData modifications.
 func FromFile(file *ach.File) *TableSet {
+	return FromFileWithOptions(file, FromFileOptions{})
+}
+
+// FromFileWithOptions converts an ACH file to a set of TableData structures
+// like FromFile, using the given options. The options are kept in the
+// TableSet and also apply when converting back with ToFile.
+func FromFileWithOptions(file *ach.File, opts FromFileOptions) *TableSet {
 	if file == nil {
 		return nil
 	}
 
 	ts := &TableSet{
 		originalFile: file,
+		options:      opts,
 	}
 
 	ts.FileHeader = convertFileHeader(file)
 	ts.Batches = convertBatches(file)
 	ts.Entries = convertEntries(file)
-	ts.Addenda = convertAddenda(file)
+	ts.Addenda = convertAddenda(file, opts)
 
 	// Handle IAT batches if present
 	if len(file.IATBatches) > 0 {
@@ -142,6 +210,20 @@ func FromFile(file *ach.File) *TableSet {
 		ts.IATAddenda = convertIATAddenda(file)
 	}
 
+	// Handle ADV batches if present
+	if slices.ContainsFunc(file.Batches, isADVBatch) {
+		ts.ADVBatches = convertADVBatches(file)
+		ts.ADVEntries = convertADVEntries(file)
+	}
+
+	if len(opts.Categories) > 0 {
+		ts.filterCategories()
+	}
+
+	if opts.MaskAccounts {
+		ts.maskAccounts()
+	}
+
 	return ts
 }
 
@@ -230,6 +312,9 @@ func convertBatches(file *ach.File) *fileparser.TableData {
 
 	records := make([][]string, 0, len(file.Batches))
 	for i, batch := range file.Batches {
+		if isADVBatch(batch) {
+			continue // listed in the ADV batches table
+		}
 		bh := batch.GetHeader()
 		bc := batch.GetControl()
 
@@ -331,13 +416,17 @@ func convertEntries(file *ach.File) *fileparser.TableData {
 		Headers:     headers,
 		Records:     records,
 		ColumnTypes: columnTypes,
+		PrimaryKey:  []string{"trace_number"},
 	}
 }
 
 // convertAddenda extracts addenda records into TableData.
 // Handles multiple addenda types: Addenda02, Addenda05, Addenda98, Addenda98Refused,
 // Addenda99, Addenda99Dishonored, Addenda99Contested.
-func convertAddenda(file *ach.File) *fileparser.TableData {
+// moov-io/ach holds Addenda05 records in a slice and every 98 and 99 variant
+// as a single pointer, so an entry has at most one record of each 98/99 kind.
+// With opts.MergeAddenda05, the Addenda05 records of an entry become one row.
+func convertAddenda(file *ach.File, opts FromFileOptions) *fileparser.TableData {
 	headers := []string{
 		"batch_index",
 		"entry_index",
@@ -427,14 +516,16 @@ func convertAddenda(file *ach.File) *fileparser.TableData {
 	var records [][]string
 	for batchIdx, batch := range file.Batches {
 		for entryIdx, entry := range batch.GetEntries() {
-			addendaIdx := 0
+			// addenda_index numbers the addenda of the entry in the order
+			// they are emitted, so it is unique whatever types are present.
+			entryStart := len(records)
 
 			// Handle Addenda02 (MTE, POS, SHR)
 			if entry.Addenda02 != nil {
 				record := make([]string, len(headers))
 				record[0] = strconv.Itoa(batchIdx)
 				record[1] = strconv.Itoa(entryIdx)
-				record[2] = strconv.Itoa(addendaIdx)
+				record[2] = strconv.Itoa(len(records) - entryStart)
 				record[3] = "02"
 				record[4] = entry.Addenda02.TypeCode
 				record[5] = ""  // payment_related_information (not used)
@@ -457,18 +548,21 @@ func convertAddenda(file *ach.File) *fileparser.TableData {
 				record[22] = strings.TrimSpace(entry.Addenda02.TerminalCity)
 				record[23] = strings.TrimSpace(entry.Addenda02.TerminalState)
 				records = append(records, record)
-				addendaIdx++
 			}
 
 			// Handle Addenda05 records (most common - PPD, CCD, CTX, etc.)
-			for _, addenda := range entry.Addenda05 {
+			addenda05 := entry.Addenda05
+			if opts.MergeAddenda05 {
+				addenda05 = mergeAddenda05(entry.Addenda05)
+			}
+			for _, addenda := range addenda05 {
 				if addenda == nil {
 					continue
 				}
 				record := make([]string, len(headers))
 				record[0] = strconv.Itoa(batchIdx)
 				record[1] = strconv.Itoa(entryIdx)
-				record[2] = strconv.Itoa(addendaIdx)
+				record[2] = strconv.Itoa(len(records) - entryStart)
 				record[3] = "05"
 				record[4] = addenda.TypeCode
 				record[5] = strings.TrimSpace(addenda.PaymentRelatedInformation)
@@ -479,7 +573,6 @@ func convertAddenda(file *ach.File) *fileparser.TableData {
 					record[i] = ""
 				}
 				records = append(records, record)
-				addendaIdx++
 			}
 
 			// Handle Addenda98 (Notification of Change)
@@ -487,7 +580,7 @@ func convertAddenda(file *ach.File) *fileparser.TableData {
 				record := make([]string, len(headers))
 				record[0] = strconv.Itoa(batchIdx)
 				record[1] = strconv.Itoa(entryIdx)
-				record[2] = strconv.Itoa(addendaIdx)
+				record[2] = strconv.Itoa(len(records) - entryStart)
 				record[3] = "98"
 				record[4] = entry.Addenda98.TypeCode
 				record[5] = "" // payment_related_information
@@ -504,7 +597,6 @@ func convertAddenda(file *ach.File) *fileparser.TableData {
 					record[i] = ""
 				}
 				records = append(records, record)
-				addendaIdx++
 			}
 
 			// Handle Addenda99 (Returns)
@@ -512,7 +604,7 @@ func convertAddenda(file *ach.File) *fileparser.TableData {
 				record := make([]string, len(headers))
 				record[0] = strconv.Itoa(batchIdx)
 				record[1] = strconv.Itoa(entryIdx)
-				record[2] = strconv.Itoa(addendaIdx)
+				record[2] = strconv.Itoa(len(records) - entryStart)
 				record[3] = "99"
 				record[4] = entry.Addenda99.TypeCode
 				record[5] = "" // payment_related_information
@@ -529,7 +621,6 @@ func convertAddenda(file *ach.File) *fileparser.TableData {
 					record[i] = ""
 				}
 				records = append(records, record)
-				addendaIdx++
 			}
 
 			// Handle Addenda98Refused (Refused Notification of Change)
@@ -537,7 +628,7 @@ func convertAddenda(file *ach.File) *fileparser.TableData {
 				record := make([]string, len(headers))
 				record[0] = strconv.Itoa(batchIdx)
 				record[1] = strconv.Itoa(entryIdx)
-				record[2] = strconv.Itoa(addendaIdx)
+				record[2] = strconv.Itoa(len(records) - entryStart)
 				record[3] = addendaType98Refused
 				record[4] = entry.Addenda98Refused.TypeCode
 				record[5] = "" // payment_related_information
@@ -560,7 +651,6 @@ func convertAddenda(file *ach.File) *fileparser.TableData {
 					record[i] = ""
 				}
 				records = append(records, record)
-				addendaIdx++
 			}
 
 			// Handle Addenda99Dishonored (Dishonored Returns)
@@ -568,7 +658,7 @@ func convertAddenda(file *ach.File) *fileparser.TableData {
 				record := make([]string, len(headers))
 				record[0] = strconv.Itoa(batchIdx)
 				record[1] = strconv.Itoa(entryIdx)
-				record[2] = strconv.Itoa(addendaIdx)
+				record[2] = strconv.Itoa(len(records) - entryStart)
 				record[3] = addendaType99Dishonored
 				record[4] = entry.Addenda99Dishonored.TypeCode
 				record[5] = "" // payment_related_information
@@ -595,7 +685,6 @@ func convertAddenda(file *ach.File) *fileparser.TableData {
 					record[i] = ""
 				}
 				records = append(records, record)
-				addendaIdx++
 			}
 
 			// Handle Addenda99Contested (Contested Dishonored Returns)
@@ -603,7 +692,7 @@ func convertAddenda(file *ach.File) *fileparser.TableData {
 				record := make([]string, len(headers))
 				record[0] = strconv.Itoa(batchIdx)
 				record[1] = strconv.Itoa(entryIdx)
-				record[2] = strconv.Itoa(addendaIdx)
+				record[2] = strconv.Itoa(len(records) - entryStart)
 				record[3] = addendaType99Contested
 				record[4] = entry.Addenda99Contested.TypeCode
 				record[5] = "" // payment_related_information
@@ -633,7 +722,6 @@ func convertAddenda(file *ach.File) *fileparser.TableData {
 				record[36] = entry.Addenda99Contested.DishonoredReturnSettlementDate
 				record[37] = entry.Addenda99Contested.DishonoredReturnReasonCode
 				records = append(records, record)
-				// Note: addendaIdx increment omitted as this is the last addenda type processed
 			}
 		}
 	}
@@ -658,9 +746,27 @@ func convertAddenda(file *ach.File) *fileparser.TableData {
 //
 // After modification, the file's control records are automatically recalculated.
 func (ts *TableSet) ToFile() (*ach.File, error) {
+	newFile, err := ts.applyModifications()
+	if err != nil {
+		return nil, err
+	}
+
+	// Recalculate control records
+	if err := newFile.Create(); err != nil {
+		return nil, fmt.Errorf("failed to create file control: %w", err)
+	}
+
+	return newFile, nil
+}
+
+// applyModifications returns a deep copy of the original ACH file with the
+// modifications from all TableData applied. The file control record is not
+// recalculated.
+func (ts *TableSet) applyModifications() (*ach.File, error) {
 	if ts == nil || ts.originalFile == nil {
 		return nil, errors.New("no original ACH file available")
 	}
+	ts = ts.unmasked()
 
 	// Create a true deep copy of the original file to avoid modifying it
 	var newFile ach.File
@@ -680,6 +786,18 @@ func (ts *TableSet) ToFile() (*ach.File, error) {
 		}
 	}
 
+	// Apply modifications from ADVBatches and ADVEntries TableData
+	if ts.ADVBatches != nil && len(ts.ADVBatches.Records) > 0 {
+		if err := ts.applyADVBatchModifications(&newFile); err != nil {
+			return nil, fmt.Errorf("failed to apply ADV batch modifications: %w", err)
+		}
+	}
+	if ts.ADVEntries != nil && len(ts.ADVEntries.Records) > 0 {
+		if err := ts.applyADVEntryModifications(&newFile); err != nil {
+			return nil, fmt.Errorf("failed to apply ADV entry modifications: %w", err)
+		}
+	}
+
 	// Apply modifications from Entries TableData
 	if ts.Entries != nil && len(ts.Entries.Records) > 0 {
 		if err := ts.applyEntryModifications(&newFile); err != nil {
@@ -694,6 +812,15 @@ func (ts *TableSet) ToFile() (*ach.File, error) {
 		}
 	}
 
+	// Remove entries whose rows were deleted from the Entries TableData. This
+	// runs after the addenda modifications, which address entries by their
+	// original index.
+	if ts.Entries != nil {
+		if err := ts.applyEntryDeletions(&newFile); err != nil {
+			return nil, fmt.Errorf("failed to apply entry deletions: %w", err)
+		}
+	}
+
 	// Apply modifications from IATBatches TableData
 	if ts.IATBatches != nil && len(ts.IATBatches.Records) > 0 {
 		if err := ts.applyIATBatchModifications(&newFile); err != nil {
@@ -715,15 +842,13 @@ func (ts *TableSet) ToFile() (*ach.File, error) {
 		}
 	}
 
-	// Recalculate control records
-	if err := newFile.Create(); err != nil {
-		return nil, fmt.Errorf("failed to create file control: %w", err)
-	}
-
 	return &newFile, nil
 }
 
 // applyEntryModifications updates entries in the ACH file from TableData.
+// A record whose entry_index equals the batch's entry count is appended to
+// the batch as a new entry, and the batch is rebuilt so its control totals
+// and trace numbers cover the new entries.
 func (ts *TableSet) applyEntryModifications(file *ach.File) error {
 	// Build index mapping for quick lookup
 	headerIndex := make(map[string]int)
@@ -731,6 +856,7 @@ func (ts *TableSet) applyEntryModifications(file *ach.File) error {
 		headerIndex[h] = i
 	}
 
+	inserted := make(map[int]bool)
 	for _, record := range ts.Entries.Records {
 		batchIdx, err := strconv.Atoi(record[headerIndex["batch_index"]])
 		if err != nil {
@@ -741,60 +867,139 @@ func (ts *TableSet) applyEntryModifications(file *ach.File) error {
 			return fmt.Errorf("invalid entry_index: %w", err)
 		}
 
-		if batchIdx >= len(file.Batches) {
+		if batchIdx < 0 || batchIdx >= len(file.Batches) {
 			return fmt.Errorf("batch_index %d out of range", batchIdx)
 		}
 
-		entries := file.Batches[batchIdx].GetEntries()
-		if entryIdx >= len(entries) {
-			return fmt.Errorf("entry_index %d out of range for batch %d", entryIdx, batchIdx)
+		batch := file.Batches[batchIdx]
+		entries := batch.GetEntries()
+		switch {
+		case entryIdx < 0 || entryIdx > len(entries):
+			return fmt.Errorf("entry_index %d out of range for batch %d (next new entry is %d)", entryIdx, batchIdx, len(entries))
+		case entryIdx == len(entries):
+			entry := ach.NewEntryDetail()
+			setEntryFields(entry, record, headerIndex)
+			batch.AddEntry(entry)
+			inserted[batchIdx] = true
+		default:
+			setEntryFields(entries[entryIdx], record, headerIndex)
 		}
+	}
 
-		entry := entries[entryIdx]
+	for batchIdx := range file.Batches {
+		if !inserted[batchIdx] {
+			continue
+		}
+		if err := file.Batches[batchIdx].Create(); err != nil {
+			return fmt.Errorf("failed to rebuild batch %d with new entries: %w", batchIdx, err)
+		}
+	}
 
-		// Update modifiable fields
-		if idx, ok := headerIndex["transaction_code"]; ok {
-			if v, err := strconv.Atoi(record[idx]); err == nil {
-				entry.TransactionCode = v
-			}
+	return nil
+}
+
+// applyEntryDeletions removes the entries of the original file that no
+// longer have a row in the Entries TableData, and rebuilds each batch that
+// lost entries so its control totals match. Removing every entry of a batch
+// is an error, since NACHA requires at least one entry per batch.
+func (ts *TableSet) applyEntryDeletions(file *ach.File) error {
+	headerIndex := make(map[string]int)
+	for i, h := range ts.Entries.Headers {
+		headerIndex[h] = i
+	}
+
+	kept := make(map[int]map[int]bool)
+	for _, record := range ts.Entries.Records {
+		batchIdx, err := strconv.Atoi(record[headerIndex["batch_index"]])
+		if err != nil {
+			return fmt.Errorf("invalid batch_index: %w", err)
 		}
-		if idx, ok := headerIndex["rdfi_identification"]; ok {
-			entry.RDFIIdentification = record[idx]
+		entryIdx, err := strconv.Atoi(record[headerIndex["entry_index"]])
+		if err != nil {
+			return fmt.Errorf("invalid entry_index: %w", err)
 		}
-		if idx, ok := headerIndex["check_digit"]; ok {
-			entry.CheckDigit = record[idx]
+		if kept[batchIdx] == nil {
+			kept[batchIdx] = make(map[int]bool)
 		}
-		if idx, ok := headerIndex["dfi_account_number"]; ok {
-			entry.DFIAccountNumber = record[idx]
+		kept[batchIdx][entryIdx] = true
+	}
+
+	for batchIdx, original := range ts.originalFile.Batches {
+		if batchIdx >= len(file.Batches) {
+			break
 		}
-		if idx, ok := headerIndex["amount"]; ok {
-			if v, err := strconv.Atoi(record[idx]); err == nil {
-				entry.Amount = v
+		originalCount := len(original.GetEntries())
+
+		batch := file.Batches[batchIdx]
+		deleted := make(map[*ach.EntryDetail]bool)
+		for entryIdx, entry := range batch.GetEntries()[:originalCount] {
+			if !ts.options.includesCategory(original.GetEntries()[entryIdx].Category) {
+				continue // filtered out of the table, not deleted
+			}
+			if !kept[batchIdx][entryIdx] {
+				deleted[entry] = true
 			}
 		}
-		if idx, ok := headerIndex["identification_number"]; ok {
-			entry.IdentificationNumber = record[idx]
+		if len(deleted) == 0 {
+			continue
 		}
-		if idx, ok := headerIndex["individual_name"]; ok {
-			entry.IndividualName = record[idx]
+		if len(deleted) == len(batch.GetEntries()) {
+			return fmt.Errorf("cannot delete all entries of batch %d: a batch requires at least one entry", batchIdx)
 		}
-		if idx, ok := headerIndex["discretionary_data"]; ok {
-			entry.DiscretionaryData = record[idx]
+
+		batch.DeleteEntries(func(entry *ach.EntryDetail) bool {
+			return deleted[entry]
+		})
+		if err := batch.Create(); err != nil {
+			return fmt.Errorf("failed to rebuild batch %d after deleting entries: %w", batchIdx, err)
 		}
-		if idx, ok := headerIndex["addenda_record_indicator"]; ok {
-			if v, err := strconv.Atoi(record[idx]); err == nil {
-				entry.AddendaRecordIndicator = v
-			}
+	}
+
+	return nil
+}
+
+// setEntryFields copies the modifiable fields of an entries table record
+// into entry.
+func setEntryFields(entry *ach.EntryDetail, record []string, headerIndex map[string]int) {
+	if idx, ok := headerIndex["transaction_code"]; ok {
+		if v, err := strconv.Atoi(record[idx]); err == nil {
+			entry.TransactionCode = v
 		}
-		if idx, ok := headerIndex["trace_number"]; ok {
-			entry.TraceNumber = record[idx]
+	}
+	if idx, ok := headerIndex["rdfi_identification"]; ok {
+		entry.RDFIIdentification = record[idx]
+	}
+	if idx, ok := headerIndex["check_digit"]; ok {
+		entry.CheckDigit = record[idx]
+	}
+	if idx, ok := headerIndex["dfi_account_number"]; ok {
+		entry.DFIAccountNumber = record[idx]
+	}
+	if idx, ok := headerIndex["amount"]; ok {
+		if v, err := strconv.Atoi(record[idx]); err == nil {
+			entry.Amount = v
 		}
-		if idx, ok := headerIndex["category"]; ok {
-			entry.Category = record[idx]
+	}
+	if idx, ok := headerIndex["identification_number"]; ok {
+		entry.IdentificationNumber = record[idx]
+	}
+	if idx, ok := headerIndex["individual_name"]; ok {
+		entry.IndividualName = record[idx]
+	}
+	if idx, ok := headerIndex["discretionary_data"]; ok {
+		entry.DiscretionaryData = record[idx]
+	}
+	if idx, ok := headerIndex["addenda_record_indicator"]; ok {
+		if v, err := strconv.Atoi(record[idx]); err == nil {
+			entry.AddendaRecordIndicator = v
 		}
 	}
-
-	return nil
+	if idx, ok := headerIndex["trace_number"]; ok {
+		entry.TraceNumber = record[idx]
+	}
+	if idx, ok := headerIndex["category"]; ok && record[idx] != "" {
+		entry.Category = record[idx]
+	}
 }
 
 // applyFileHeaderModifications updates file header fields from TableData.
@@ -857,50 +1062,54 @@ func (ts *TableSet) applyBatchModifications(file *ach.File) error {
 			return fmt.Errorf("batch_index %d out of range", batchIdx)
 		}
 
-		bh := file.Batches[batchIdx].GetHeader()
+		setBatchHeaderFields(file.Batches[batchIdx].GetHeader(), record, headerIndex)
+	}
 
-		if idx, ok := headerIndex["service_class_code"]; ok && idx < len(record) {
-			if v, err := strconv.Atoi(record[idx]); err == nil {
-				bh.ServiceClassCode = v
-			}
-		}
-		if idx, ok := headerIndex["company_name"]; ok && idx < len(record) {
-			bh.CompanyName = record[idx]
-		}
-		if idx, ok := headerIndex["company_discretionary_data"]; ok && idx < len(record) {
-			bh.CompanyDiscretionaryData = record[idx]
-		}
-		if idx, ok := headerIndex["company_identification"]; ok && idx < len(record) {
-			bh.CompanyIdentification = record[idx]
-		}
-		if idx, ok := headerIndex["standard_entry_class_code"]; ok && idx < len(record) {
-			bh.StandardEntryClassCode = record[idx]
-		}
-		if idx, ok := headerIndex["company_entry_description"]; ok && idx < len(record) {
-			bh.CompanyEntryDescription = record[idx]
-		}
-		if idx, ok := headerIndex["company_descriptive_date"]; ok && idx < len(record) {
-			bh.CompanyDescriptiveDate = record[idx]
-		}
-		if idx, ok := headerIndex["effective_entry_date"]; ok && idx < len(record) {
-			bh.EffectiveEntryDate = record[idx]
-		}
-		if idx, ok := headerIndex["originator_status_code"]; ok && idx < len(record) {
-			if v, err := strconv.Atoi(record[idx]); err == nil {
-				bh.OriginatorStatusCode = v
-			}
+	return nil
+}
+
+// setBatchHeaderFields copies the modifiable fields of a batches table
+// record into bh.
+func setBatchHeaderFields(bh *ach.BatchHeader, record []string, headerIndex map[string]int) {
+	if idx, ok := headerIndex["service_class_code"]; ok && idx < len(record) {
+		if v, err := strconv.Atoi(record[idx]); err == nil {
+			bh.ServiceClassCode = v
 		}
-		if idx, ok := headerIndex["odfi_identification"]; ok && idx < len(record) {
-			bh.ODFIIdentification = record[idx]
+	}
+	if idx, ok := headerIndex["company_name"]; ok && idx < len(record) {
+		bh.CompanyName = record[idx]
+	}
+	if idx, ok := headerIndex["company_discretionary_data"]; ok && idx < len(record) {
+		bh.CompanyDiscretionaryData = record[idx]
+	}
+	if idx, ok := headerIndex["company_identification"]; ok && idx < len(record) {
+		bh.CompanyIdentification = record[idx]
+	}
+	if idx, ok := headerIndex["standard_entry_class_code"]; ok && idx < len(record) {
+		bh.StandardEntryClassCode = record[idx]
+	}
+	if idx, ok := headerIndex["company_entry_description"]; ok && idx < len(record) {
+		bh.CompanyEntryDescription = record[idx]
+	}
+	if idx, ok := headerIndex["company_descriptive_date"]; ok && idx < len(record) {
+		bh.CompanyDescriptiveDate = record[idx]
+	}
+	if idx, ok := headerIndex["effective_entry_date"]; ok && idx < len(record) {
+		bh.EffectiveEntryDate = record[idx]
+	}
+	if idx, ok := headerIndex["originator_status_code"]; ok && idx < len(record) {
+		if v, err := strconv.Atoi(record[idx]); err == nil {
+			bh.OriginatorStatusCode = v
 		}
-		if idx, ok := headerIndex["batch_number"]; ok && idx < len(record) {
-			if v, err := strconv.Atoi(record[idx]); err == nil {
-				bh.BatchNumber = v
-			}
+	}
+	if idx, ok := headerIndex["odfi_identification"]; ok && idx < len(record) {
+		bh.ODFIIdentification = record[idx]
+	}
+	if idx, ok := headerIndex["batch_number"]; ok && idx < len(record) {
+		if v, err := strconv.Atoi(record[idx]); err == nil {
+			bh.BatchNumber = v
 		}
 	}
-
-	return nil
 }
 
 // applyAddendaModifications updates addenda records from TableData.
@@ -945,8 +1154,13 @@ func (ts *TableSet) applyAddendaModifications(file *ach.File) error {
 				ts.applyAddenda02Modifications(entry.Addenda02, record, headerIndex)
 			}
 		case "05":
-			if addendaIdx < len(entry.Addenda05) && entry.Addenda05[addendaIdx] != nil {
-				ts.applyAddenda05Modifications(entry.Addenda05[addendaIdx], record, headerIndex)
+			if ts.options.MergeAddenda05 {
+				sec := file.Batches[batchIdx].GetHeader().StandardEntryClassCode
+				splitAddenda05(entry, sec, record, headerIndex)
+				continue
+			}
+			if addenda := addenda05At(entry, addendaIdx); addenda != nil {
+				ts.applyAddenda05Modifications(addenda, record, headerIndex)
 			}
 		case "98":
 			if entry.Addenda98 != nil {
@@ -974,6 +1188,29 @@ func (ts *TableSet) applyAddendaModifications(file *ach.File) error {
 	return nil
 }
 
+// addenda05At returns the Addenda05 record of entry that convertAddenda
+// numbered addendaIdx, or nil if there is none. The addenda_index column
+// counts the entry's addenda in order, so an Addenda02 takes index 0 and
+// shifts the Addenda05 records by one; nil records are not numbered.
+func addenda05At(entry *ach.EntryDetail, addendaIdx int) *ach.Addenda05 {
+	if entry.Addenda02 != nil {
+		addendaIdx--
+	}
+	if addendaIdx < 0 {
+		return nil
+	}
+	for _, addenda := range entry.Addenda05 {
+		if addenda == nil {
+			continue
+		}
+		if addendaIdx == 0 {
+			return addenda
+		}
+		addendaIdx--
+	}
+	return nil
+}
+
 // applyAddenda02Modifications applies modifications to Addenda02.
 func (ts *TableSet) applyAddenda02Modifications(addenda *ach.Addenda02, record []string, headerIndex map[string]int) {
 	if idx, ok := headerIndex["reference_information_one"]; ok && idx < len(record) {
@@ -1180,6 +1417,40 @@ func (ts *TableSet) GetAddendaTable() *fileparser.TableData {
 	return ts.Addenda
 }
 
+// Tables returns the tables of the TableSet keyed by their table names:
+// file_header, batches, entries, and addenda, plus iat_batches, iat_entries,
+// iat_addenda, adv_batches, and adv_entries when the file has IAT or ADV
+// batches. It is meant for registering every table with filesql at once.
+//
+// Example:
+//
+//	for name, table := range ts.Tables() {
+//	    // register table under name
+//	}
+func (ts *TableSet) Tables() map[string]*fileparser.TableData {
+	if ts == nil {
+		return nil
+	}
+
+	tables := make(map[string]*fileparser.TableData)
+	for name, table := range map[string]*fileparser.TableData{
+		"file_header": ts.FileHeader,
+		"batches":     ts.Batches,
+		"entries":     ts.Entries,
+		"addenda":     ts.Addenda,
+		"iat_batches": ts.IATBatches,
+		"iat_entries": ts.IATEntries,
+		"iat_addenda": ts.IATAddenda,
+		"adv_batches": ts.ADVBatches,
+		"adv_entries": ts.ADVEntries,
+	} {
+		if table != nil {
+			tables[name] = table
+		}
+	}
+	return tables
+}
+
 // UpdateEntriesFromTableData updates the internal entries data from modified TableData.
 // Call this after making SQL modifications to prepare for ToFile().
 func (ts *TableSet) UpdateEntriesFromTableData(entries *fileparser.TableData) {
@@ -1232,6 +1503,28 @@ func (ts *TableSet) WriteToWriter(writer io.Writer) error {
 	return w.Write(achFile)
 }
 
+// ParseBase64 parses a base64-encoded ACH file, as delivered inside JSON
+// payloads by some payment APIs, and returns a TableSet. The standard
+// encoding with padding is expected; surrounding whitespace and line breaks
+// are ignored.
+func ParseBase64(encoded string) (*TableSet, error) {
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode base64 ACH file: %w", err)
+	}
+	return ParseReader(bytes.NewReader(data))
+}
+
+// Base64 returns the ACH file from a TableSet encoded with standard base64,
+// the inverse of ParseBase64.
+func (ts *TableSet) Base64() (string, error) {
+	var buf bytes.Buffer
+	if err := ts.WriteToWriter(&buf); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
 // convertIATBatches extracts IAT batch information into TableData.
 func convertIATBatches(file *ach.File) *fileparser.TableData {
 	headers := []string{
@@ -67,6 +67,8 @@ func TestFromFile_WithBatchAndEntry(t *testing.T) {
 	assert.Equal(t, "27", entryRecord[2])                    // transaction_code (CheckingDebit)
 	assert.Equal(t, "100000000", entryRecord[6])             // amount (in cents)
 	assert.Equal(t, "Receiver Account Name", entryRecord[8]) // individual_name
+	assert.Equal(t, []string{"trace_number"}, ts.Entries.PrimaryKey)
+	assert.NoError(t, ts.Entries.Validate())
 }
 
 func TestToFile_NilTableSet(t *testing.T) {
@@ -352,6 +354,38 @@ func TestWriteToWriter(t *testing.T) {
 	assert.True(t, len(buf.String()) > 0, "expected non-empty output")
 }
 
+// TestBase64RoundTrip tests encoding an ACH file as base64 and parsing it back
+func TestBase64RoundTrip(t *testing.T) {
+	testFile := findTestACHFile(t)
+	if testFile == "" {
+		t.Skip("No test ACH file found")
+	}
+
+	data, err := os.ReadFile(testFile) //nolint:gosec // testFile is a hardcoded test file path
+	require.NoError(t, err)
+
+	ts, err := ParseReader(bytes.NewReader(data))
+	require.NoError(t, err)
+
+	encoded, err := ts.Base64()
+	require.NoError(t, err)
+
+	parsed, err := ParseBase64(encoded + "\n")
+	require.NoError(t, err)
+
+	assert.Equal(t, ts.FileHeader.Records, parsed.FileHeader.Records)
+	assert.Equal(t, ts.Batches.Records, parsed.Batches.Records)
+	assert.Equal(t, ts.Entries.Records, parsed.Entries.Records)
+	assert.Equal(t, ts.Addenda.Records, parsed.Addenda.Records)
+}
+
+// TestParseBase64_Invalid tests that invalid base64 is reported clearly
+func TestParseBase64_Invalid(t *testing.T) {
+	_, err := ParseBase64("not base64!")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to decode base64 ACH file")
+}
+
 // TestUpdateFromTableData tests updating internal TableData
 func TestUpdateFromTableData(t *testing.T) {
 	file := createTestACHFile(t)
@@ -912,6 +946,27 @@ func findTestFile(t *testing.T, filename string) string {
 	return filepath.Join("testdata", filename)
 }
 
+// rowMap returns record as a map from column name to value.
+func rowMap(headers, record []string) map[string]string {
+	row := make(map[string]string, len(headers))
+	for i, h := range headers {
+		row[h] = record[i]
+	}
+	return row
+}
+
+// setCell sets the value of column in the given row of table.
+func setCell(t *testing.T, table *fileparser.TableData, row int, column, value string) {
+	t.Helper()
+	for i, h := range table.Headers {
+		if h == column {
+			table.Records[row][i] = value
+			return
+		}
+	}
+	t.Fatalf("column %q not found", column)
+}
+
 // TestModifyAddenda98 tests modifying Addenda98 (Notification of Change) records
 func TestModifyAddenda98(t *testing.T) {
 	testFile := findTestFile(t, "cor-example.ach")
@@ -1431,3 +1486,314 @@ func TestModifyAddenda99Contested(t *testing.T) {
 	assert.Equal(t, newContestedReturnCode, foundAddenda99Contested.ContestedReturnCode)
 	assert.Equal(t, newOriginalSettlementDate, foundAddenda99Contested.OriginalSettlementDate)
 }
+
+// TestModifyAddenda05_AfterAddenda02 tests that edits to Addenda05 rows reach the
+// right record when an Addenda02 precedes them on the same entry
+func TestModifyAddenda05_AfterAddenda02(t *testing.T) {
+	file, err := ach.ReadFile(findTestFile(t, "pos-debit.ach"))
+	require.NoError(t, err)
+
+	entry := file.Batches[0].GetEntries()[0]
+	require.NotNil(t, entry.Addenda02)
+	for i, info := range []string{"FIRST MEMO", "SECOND MEMO"} {
+		addenda := ach.NewAddenda05()
+		addenda.PaymentRelatedInformation = info
+		addenda.SequenceNumber = i + 1
+		addenda.EntryDetailSequenceNumber = 1
+		entry.AddAddenda05(addenda)
+	}
+
+	ts := FromFile(file)
+	require.NotNil(t, ts)
+
+	headerIndex := make(map[string]int)
+	for i, h := range ts.Addenda.Headers {
+		headerIndex[h] = i
+	}
+	var rows [][]string
+	for _, record := range ts.Addenda.Records {
+		if record[headerIndex["batch_index"]] == "0" && record[headerIndex["entry_index"]] == "0" {
+			rows = append(rows, record)
+		}
+	}
+	require.Len(t, rows, 3)
+	assert.Equal(t, []string{"02", "05", "05"}, []string{
+		rows[0][headerIndex["addenda_type"]],
+		rows[1][headerIndex["addenda_type"]],
+		rows[2][headerIndex["addenda_type"]],
+	})
+	assert.Equal(t, "1", rows[1][headerIndex["addenda_index"]])
+	assert.Equal(t, "2", rows[2][headerIndex["addenda_index"]])
+
+	rows[1][headerIndex["payment_related_information"]] = "FIRST EDITED"
+	rows[2][headerIndex["payment_related_information"]] = "SECOND EDITED"
+	rows[0][headerIndex["terminal_city"]] = "EDITED CITY"
+
+	newFile, err := ts.ToFile()
+	require.NoError(t, err)
+
+	newEntry := newFile.Batches[0].GetEntries()[0]
+	require.Len(t, newEntry.Addenda05, 2)
+	assert.Equal(t, "FIRST EDITED", newEntry.Addenda05[0].PaymentRelatedInformation)
+	assert.Equal(t, "SECOND EDITED", newEntry.Addenda05[1].PaymentRelatedInformation)
+	assert.Equal(t, "EDITED CITY", strings.TrimSpace(newEntry.Addenda02.TerminalCity))
+}
+
+func TestAddenda05At(t *testing.T) {
+	first := ach.NewAddenda05()
+	second := ach.NewAddenda05()
+
+	entry := ach.NewEntryDetail()
+	entry.Addenda05 = []*ach.Addenda05{first, nil, second}
+	assert.Same(t, first, addenda05At(entry, 0))
+	assert.Same(t, second, addenda05At(entry, 1))
+	assert.Nil(t, addenda05At(entry, 2))
+
+	entry.Addenda02 = ach.NewAddenda02()
+	assert.Nil(t, addenda05At(entry, 0))
+	assert.Same(t, first, addenda05At(entry, 1))
+	assert.Same(t, second, addenda05At(entry, 2))
+}
+
+// TestConvertAddenda_UniqueIndexes tests that every addenda of an entry
+// carrying several addenda types gets its own addenda_index and that edits
+// round-trip to the right records
+func TestConvertAddenda_UniqueIndexes(t *testing.T) {
+	file, err := ach.ReadFile(findTestFile(t, "pos-debit.ach"))
+	require.NoError(t, err)
+
+	entry := file.Batches[0].GetEntries()[0]
+	require.NotNil(t, entry.Addenda02)
+	for i, info := range []string{"FIRST MEMO", "SECOND MEMO"} {
+		addenda := ach.NewAddenda05()
+		addenda.PaymentRelatedInformation = info
+		addenda.SequenceNumber = i + 1
+		addenda.EntryDetailSequenceNumber = 1
+		entry.AddAddenda05(addenda)
+	}
+	contested := ach.NewAddenda99Contested()
+	contested.ContestedReturnCode = "R71"
+	entry.Addenda99Contested = contested
+
+	ts := FromFile(file)
+	require.NotNil(t, ts)
+
+	headerIndex := make(map[string]int)
+	for i, h := range ts.Addenda.Headers {
+		headerIndex[h] = i
+	}
+	var rows [][]string
+	for _, record := range ts.Addenda.Records {
+		if record[headerIndex["batch_index"]] == "0" && record[headerIndex["entry_index"]] == "0" {
+			rows = append(rows, record)
+		}
+	}
+	require.Len(t, rows, 4)
+
+	var types, indexes []string
+	for _, row := range rows {
+		types = append(types, row[headerIndex["addenda_type"]])
+		indexes = append(indexes, row[headerIndex["addenda_index"]])
+	}
+	assert.Equal(t, []string{"02", "05", "05", addendaType99Contested}, types)
+	assert.Equal(t, []string{"0", "1", "2", "3"}, indexes)
+
+	rows[2][headerIndex["payment_related_information"]] = "SECOND EDITED"
+	rows[3][headerIndex["contested_return_code"]] = "R72"
+	require.NoError(t, ts.applyAddendaModifications(file))
+
+	assert.Equal(t, "FIRST MEMO", entry.Addenda05[0].PaymentRelatedInformation)
+	assert.Equal(t, "SECOND EDITED", entry.Addenda05[1].PaymentRelatedInformation)
+	assert.Equal(t, "R72", entry.Addenda99Contested.ContestedReturnCode)
+}
+
+// TestToFile_InsertEntry tests appending a new entry through the entries table
+func TestToFile_InsertEntry(t *testing.T) {
+	file, err := ach.ReadFile(findTestACHFile(t))
+	require.NoError(t, err)
+
+	ts := FromFile(file)
+	require.NotNil(t, ts)
+	require.Len(t, ts.Entries.Records, 1)
+
+	values := map[string]string{
+		"batch_index":         "0",
+		"entry_index":         "1",
+		"transaction_code":    "27",
+		"rdfi_identification": "23138010",
+		"check_digit":         "4",
+		"dfi_account_number":  "87654321",
+		"amount":              "2500",
+		"individual_name":     "New Receiver",
+	}
+	record := make([]string, len(ts.Entries.Headers))
+	for i, h := range ts.Entries.Headers {
+		record[i] = values[h]
+	}
+	ts.Entries.Records = append(ts.Entries.Records, record)
+
+	newFile, err := ts.ToFile()
+	require.NoError(t, err)
+
+	entries := newFile.Batches[0].GetEntries()
+	require.Len(t, entries, 2)
+	assert.Equal(t, "New Receiver", entries[1].IndividualName)
+	assert.Equal(t, 2500, entries[1].Amount)
+	assert.Equal(t, ach.CategoryForward, entries[1].Category)
+	assert.Equal(t, "121042880000002", entries[1].TraceNumber)
+
+	control := newFile.Batches[0].GetControl()
+	assert.Equal(t, 2, control.EntryAddendaCount)
+	assert.Equal(t, 100002500, control.TotalDebitEntryDollarAmount)
+	assert.Equal(t, 46276020, control.EntryHash)
+	assert.Equal(t, 2, newFile.Control.EntryAddendaCount)
+	assert.Equal(t, 100002500, newFile.Control.TotalDebitEntryDollarAmountInFile)
+	require.NoError(t, newFile.Validate())
+
+	// The original file is untouched
+	assert.Len(t, file.Batches[0].GetEntries(), 1)
+}
+
+// TestToFile_InsertEntryOutOfRange tests that new entries must continue the entry_index sequence
+func TestToFile_InsertEntryOutOfRange(t *testing.T) {
+	file, err := ach.ReadFile(findTestACHFile(t))
+	require.NoError(t, err)
+
+	ts := FromFile(file)
+	require.NotNil(t, ts)
+
+	record := append([]string(nil), ts.Entries.Records[0]...)
+	for i, h := range ts.Entries.Headers {
+		if h == "entry_index" {
+			record[i] = "2"
+		}
+	}
+	ts.Entries.Records = append(ts.Entries.Records, record)
+
+	_, err = ts.ToFile()
+	require.ErrorContains(t, err, "entry_index 2 out of range for batch 0 (next new entry is 1)")
+}
+
+// TestToFile_DeleteEntry tests removing entries whose rows were deleted from the entries table
+func TestToFile_DeleteEntry(t *testing.T) {
+	file, err := ach.ReadFile(findTestACHFile(t))
+	require.NoError(t, err)
+
+	second := ach.NewEntryDetail()
+	second.TransactionCode = ach.CheckingDebit
+	second.SetRDFI("231380104")
+	second.DFIAccountNumber = "87654321"
+	second.Amount = 2500
+	second.IndividualName = "Second Receiver"
+	second.SetTraceNumber(file.Batches[0].GetHeader().ODFIIdentification, 2)
+	file.Batches[0].AddEntry(second)
+	require.NoError(t, file.Batches[0].Create())
+	require.NoError(t, file.Create())
+
+	t.Run("removes the entry and recalculates totals", func(t *testing.T) {
+		ts := FromFile(file)
+		require.NotNil(t, ts)
+		require.Len(t, ts.Entries.Records, 2)
+
+		ts.Entries.Records = ts.Entries.Records[1:]
+
+		newFile, err := ts.ToFile()
+		require.NoError(t, err)
+
+		entries := newFile.Batches[0].GetEntries()
+		require.Len(t, entries, 1)
+		assert.Equal(t, "Second Receiver", entries[0].IndividualName)
+		assert.Equal(t, 1, newFile.Batches[0].GetControl().EntryAddendaCount)
+		assert.Equal(t, 2500, newFile.Batches[0].GetControl().TotalDebitEntryDollarAmount)
+		assert.Equal(t, 2500, newFile.Control.TotalDebitEntryDollarAmountInFile)
+		require.NoError(t, newFile.Validate())
+
+		// The original file is untouched
+		assert.Len(t, file.Batches[0].GetEntries(), 2)
+	})
+
+	t.Run("rejects deleting every entry of a batch", func(t *testing.T) {
+		ts := FromFile(file)
+		require.NotNil(t, ts)
+
+		ts.Entries.Records = nil
+
+		_, err := ts.ToFile()
+		require.ErrorContains(t, err, "cannot delete all entries of batch 0")
+	})
+}
+
+// TestConvertAddenda_EveryAddendaOnce tests that an entry carrying every
+// addenda kind gets one row per addenda record, each with a unique addenda_index
+func TestConvertAddenda_EveryAddendaOnce(t *testing.T) {
+	file, err := ach.ReadFile(findTestFile(t, "pos-debit.ach"))
+	require.NoError(t, err)
+
+	entry := file.Batches[0].GetEntries()[0]
+	require.NotNil(t, entry.Addenda02)
+	for i, info := range []string{"FIRST MEMO", "SECOND MEMO", "THIRD MEMO"} {
+		addenda := ach.NewAddenda05()
+		addenda.PaymentRelatedInformation = info
+		addenda.SequenceNumber = i + 1
+		addenda.EntryDetailSequenceNumber = 1
+		entry.AddAddenda05(addenda)
+	}
+	entry.Addenda98 = ach.NewAddenda98()
+	entry.Addenda98.ChangeCode = "C01"
+	entry.Addenda98Refused = ach.NewAddenda98Refused()
+	entry.Addenda98Refused.RefusedChangeCode = "C61"
+	entry.Addenda99 = ach.NewAddenda99()
+	entry.Addenda99.ReturnCode = "R01"
+	entry.Addenda99Dishonored = ach.NewAddenda99Dishonored()
+	entry.Addenda99Dishonored.DishonoredReturnReasonCode = "R61"
+	entry.Addenda99Contested = ach.NewAddenda99Contested()
+	entry.Addenda99Contested.ContestedReturnCode = "R71"
+
+	ts := FromFile(file)
+	require.NotNil(t, ts)
+
+	var types []string
+	indexes := make(map[string]bool)
+	for _, record := range ts.Addenda.Records {
+		row := rowMap(ts.Addenda.Headers, record)
+		if row["batch_index"] != "0" || row["entry_index"] != "0" {
+			continue
+		}
+		assert.False(t, indexes[row["addenda_index"]], "duplicate addenda_index %s", row["addenda_index"])
+		indexes[row["addenda_index"]] = true
+		types = append(types, row["addenda_type"])
+	}
+
+	assert.Equal(t, []string{
+		"02", "05", "05", "05", "98", "99",
+		addendaType98Refused, addendaType99Dishonored, addendaType99Contested,
+	}, types)
+	for i := range types {
+		assert.True(t, indexes[strconv.Itoa(i)], "missing addenda_index %d", i)
+	}
+}
+
+// TestTables tests that Tables returns every table present by name
+func TestTables(t *testing.T) {
+	file, err := ach.ReadFile(findTestACHFile(t))
+	require.NoError(t, err)
+	ts := FromFile(file)
+
+	tables := ts.Tables()
+	assert.Len(t, tables, 4)
+	assert.Same(t, ts.FileHeader, tables["file_header"])
+	assert.Same(t, ts.Batches, tables["batches"])
+	assert.Same(t, ts.Entries, tables["entries"])
+	assert.Same(t, ts.Addenda, tables["addenda"])
+
+	iatFile, err := ach.ReadFile(findTestFile(t, "iat-credit.ach"))
+	require.NoError(t, err)
+	iatTables := FromFile(iatFile).Tables()
+	assert.Contains(t, iatTables, "iat_batches")
+	assert.Contains(t, iatTables, "iat_entries")
+	assert.Contains(t, iatTables, "iat_addenda")
+	assert.NotContains(t, iatTables, "adv_batches")
+
+	var nilSet *TableSet
+	assert.Nil(t, nilSet.Tables())
+}
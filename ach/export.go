@@ -0,0 +1,123 @@
+package ach
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/nao1215/fileparser"
+)
+
+// entriesCSVBatchColumns are the batches table columns that EntriesToCSV
+// adds to every entry row.
+var entriesCSVBatchColumns = []string{
+	"company_name",
+	"company_identification",
+	"standard_entry_class_code",
+	"company_entry_description",
+	"effective_entry_date",
+}
+
+// EntriesCSVOptions configures EntriesToCSVWithOptions.
+type EntriesCSVOptions struct {
+	// MaskAccountNumbers replaces all but the last 4 characters of
+	// dfi_account_number with '*', e.g. "****5678" for "12345678".
+	MaskAccountNumbers bool
+}
+
+// EntriesToCSV writes the entries as CSV, one row per entry, with the
+// company and SEC code columns of the entry's batch added after batch_index.
+// It is shorthand for EntriesToCSVWithOptions with zero options.
+func (ts *TableSet) EntriesToCSV(w io.Writer) error {
+	return ts.EntriesToCSVWithOptions(w, EntriesCSVOptions{})
+}
+
+// EntriesToCSVWithOptions writes the entries as CSV, one row per entry. The
+// columns are batch_index, then company_name, company_identification,
+// standard_entry_class_code, company_entry_description, and
+// effective_entry_date from the entry's batch, then the remaining columns of
+// the entries table. The rows are built from the current Entries and Batches
+// tables, so they reflect any modifications made to them.
+//
+// See the package security note: account numbers are written verbatim unless
+// opts.MaskAccountNumbers is set.
+//
+// Example:
+//
+//	f, err := os.Create("entries.csv")
+//	if err != nil {
+//	    return err
+//	}
+//	defer f.Close()
+//	err = ts.EntriesToCSVWithOptions(f, ach.EntriesCSVOptions{MaskAccountNumbers: true})
+func (ts *TableSet) EntriesToCSVWithOptions(w io.Writer, opts EntriesCSVOptions) error {
+	if ts == nil || ts.Entries == nil {
+		return errors.New("no entries table available")
+	}
+
+	entryIndex := make(map[string]int)
+	for i, h := range ts.Entries.Headers {
+		entryIndex[h] = i
+	}
+	batchCol, ok := entryIndex["batch_index"]
+	if !ok {
+		return errors.New("entries table has no batch_index column")
+	}
+	accountCol, hasAccount := entryIndex["dfi_account_number"]
+
+	// Batch columns keyed by batch_index
+	batchValues := make(map[string][]string)
+	if ts.Batches != nil {
+		batchIndex := make(map[string]int)
+		for i, h := range ts.Batches.Headers {
+			batchIndex[h] = i
+		}
+		if idx, ok := batchIndex["batch_index"]; ok {
+			for _, record := range ts.Batches.Records {
+				values := make([]string, len(entriesCSVBatchColumns))
+				for i, column := range entriesCSVBatchColumns {
+					if col, ok := batchIndex[column]; ok && col < len(record) {
+						values[i] = record[col]
+					}
+				}
+				batchValues[record[idx]] = values
+			}
+		}
+	}
+
+	headers := make([]string, 0, len(ts.Entries.Headers)+len(entriesCSVBatchColumns))
+	headers = append(headers, "batch_index")
+	headers = append(headers, entriesCSVBatchColumns...)
+	for i, h := range ts.Entries.Headers {
+		if i != batchCol {
+			headers = append(headers, h)
+		}
+	}
+
+	records := make([][]string, 0, len(ts.Entries.Records))
+	for _, entry := range ts.Entries.Records {
+		record := make([]string, 0, len(headers))
+		record = append(record, entry[batchCol])
+		if values, ok := batchValues[entry[batchCol]]; ok {
+			record = append(record, values...)
+		} else {
+			record = append(record, make([]string, len(entriesCSVBatchColumns))...)
+		}
+		for i, value := range entry {
+			switch {
+			case i == batchCol:
+				continue
+			case hasAccount && i == accountCol && opts.MaskAccountNumbers:
+				value = maskAccountNumber(value)
+			}
+			record = append(record, value)
+		}
+		records = append(records, record)
+	}
+
+	table := &fileparser.TableData{Headers: headers, Records: records}
+	if _, err := table.WriteTo(w); err != nil {
+		return fmt.Errorf("failed to write entries CSV: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package ach
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/moov-io/ach"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntriesToCSV(t *testing.T) {
+	file, err := ach.ReadFile(findTestACHFile(t))
+	require.NoError(t, err)
+
+	t.Run("writes entries joined with batch columns", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, FromFile(file).EntriesToCSV(&buf))
+
+		want := "batch_index,company_name,company_identification,standard_entry_class_code,company_entry_description,effective_entry_date," +
+			"entry_index,transaction_code,rdfi_identification,check_digit,dfi_account_number,amount,identification_number," +
+			"individual_name,discretionary_data,addenda_record_indicator,trace_number,category\n" +
+			"0,Name on Account,121042882,PPD,REG.SALARY,190625," +
+			"0,27,23138010,4,12345678,100000000,,Receiver Account Name,,0,121042880000001,Forward\n"
+		assert.Equal(t, want, buf.String())
+	})
+
+	t.Run("masks account numbers", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, FromFile(file).EntriesToCSVWithOptions(&buf, EntriesCSVOptions{MaskAccountNumbers: true}))
+
+		assert.Contains(t, buf.String(), ",****5678,")
+		assert.NotContains(t, buf.String(), "12345678")
+	})
+
+	t.Run("returns an error without entries", func(t *testing.T) {
+		var ts *TableSet
+		assert.Error(t, ts.EntriesToCSV(&bytes.Buffer{}))
+	})
+}
@@ -0,0 +1,92 @@
+package ach
+
+import (
+	"slices"
+
+	"github.com/moov-io/ach"
+	"github.com/nao1215/fileparser"
+)
+
+// FromFileFiltered converts an ACH file like FromFile, but the entries
+// tables only hold entries whose Category is one of categories, such as
+// ach.CategoryReturn or ach.CategoryNOC, and the addenda tables only hold
+// the addenda of those entries. The file header and batch tables are not
+// filtered. With no categories, every entry is included.
+//
+// The TableSet still refers to the whole original file, so ToFile writes
+// every entry: the excluded entries are kept as they are and cannot be
+// modified or deleted through the filtered tables.
+//
+// Example:
+//
+//	returns := ach.FromFileFiltered(file, moovach.CategoryReturn)
+func FromFileFiltered(file *ach.File, categories ...string) *TableSet {
+	return FromFileWithOptions(file, FromFileOptions{Categories: categories})
+}
+
+// includesCategory reports whether entries of the given category are
+// included in the tables.
+func (opts FromFileOptions) includesCategory(category string) bool {
+	return len(opts.Categories) == 0 || slices.Contains(opts.Categories, category)
+}
+
+// filterCategories removes the entries excluded by options.Categories, and
+// their addenda, from the tables.
+func (ts *TableSet) filterCategories() {
+	ts.Entries, ts.Addenda = filterEntryTables(ts.Entries, ts.Addenda, ts.options)
+	ts.IATEntries, ts.IATAddenda = filterEntryTables(ts.IATEntries, ts.IATAddenda, ts.options)
+	ts.ADVEntries, _ = filterEntryTables(ts.ADVEntries, nil, ts.options)
+}
+
+// filterEntryTables returns entries without the rows whose category is
+// excluded by opts, and addenda without the rows of those entries.
+func filterEntryTables(entries, addenda *fileparser.TableData, opts FromFileOptions) (*fileparser.TableData, *fileparser.TableData) {
+	if entries == nil {
+		return entries, addenda
+	}
+
+	entryIndex := make(map[string]int)
+	for i, h := range entries.Headers {
+		entryIndex[h] = i
+	}
+	categoryCol, ok := entryIndex["category"]
+	if !ok {
+		return entries, addenda
+	}
+
+	included := make(map[entryKey]bool)
+	records := [][]string{}
+	for _, record := range entries.Records {
+		if !opts.includesCategory(record[categoryCol]) {
+			continue
+		}
+		included[entryKey{record[entryIndex["batch_index"]], record[entryIndex["entry_index"]]}] = true
+		records = append(records, record)
+	}
+	entries = &fileparser.TableData{
+		Headers:     entries.Headers,
+		Records:     records,
+		ColumnTypes: entries.ColumnTypes,
+		PrimaryKey:  entries.PrimaryKey,
+	}
+
+	if addenda == nil {
+		return entries, addenda
+	}
+	addendaIndex := make(map[string]int)
+	for i, h := range addenda.Headers {
+		addendaIndex[h] = i
+	}
+	addendaRecords := [][]string{}
+	for _, record := range addenda.Records {
+		if included[entryKey{record[addendaIndex["batch_index"]], record[addendaIndex["entry_index"]]}] {
+			addendaRecords = append(addendaRecords, record)
+		}
+	}
+	addenda = &fileparser.TableData{
+		Headers:     addenda.Headers,
+		Records:     addendaRecords,
+		ColumnTypes: addenda.ColumnTypes,
+	}
+	return entries, addenda
+}
@@ -0,0 +1,77 @@
+package ach
+
+import (
+	"testing"
+
+	"github.com/moov-io/ach"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createMixedCategoryFile returns ppd-debit.ach, which has one forward
+// entry, followed by the two return batches of return-WEB.ach.
+func createMixedCategoryFile(t *testing.T) *ach.File {
+	t.Helper()
+
+	file, err := ach.ReadFile(findTestACHFile(t))
+	require.NoError(t, err)
+	returns, err := ach.ReadFile(findTestFile(t, "return-WEB.ach"))
+	require.NoError(t, err)
+
+	for i, batch := range returns.Batches {
+		batch.GetHeader().BatchNumber = i + 2
+		batch.GetControl().BatchNumber = i + 2
+		file.AddBatch(batch)
+	}
+	require.NoError(t, file.Create())
+
+	return file
+}
+
+func TestFromFileFiltered(t *testing.T) {
+	t.Run("includes only entries of the given categories", func(t *testing.T) {
+		file := createMixedCategoryFile(t)
+
+		ts := FromFileFiltered(file, ach.CategoryReturn)
+		require.NotNil(t, ts)
+
+		require.Len(t, ts.Entries.Records, 2)
+		for _, record := range ts.Entries.Records {
+			entry := rowMap(ts.Entries.Headers, record)
+			assert.NotEqual(t, "0", entry["batch_index"])
+			assert.Equal(t, ach.CategoryReturn, entry["category"])
+		}
+
+		require.Len(t, ts.Addenda.Records, 2)
+		addenda := rowMap(ts.Addenda.Headers, ts.Addenda.Records[0])
+		assert.Equal(t, "1", addenda["batch_index"])
+		assert.Equal(t, "R01", addenda["return_code"])
+
+		assert.Len(t, ts.Batches.Records, 3)
+	})
+
+	t.Run("includes every entry without categories", func(t *testing.T) {
+		ts := FromFileFiltered(createMixedCategoryFile(t))
+		assert.Len(t, ts.Entries.Records, 3)
+	})
+
+	t.Run("keeps excluded entries in ToFile", func(t *testing.T) {
+		file := createMixedCategoryFile(t)
+
+		ts := FromFileFiltered(file, ach.CategoryForward)
+		require.Len(t, ts.Entries.Records, 1)
+		assert.Empty(t, ts.Addenda.Records)
+		setCell(t, ts.Entries, 0, "individual_name", "Edited Receiver")
+
+		newFile, err := ts.ToFile()
+		require.NoError(t, err)
+
+		require.Len(t, newFile.Batches, 3)
+		assert.Equal(t, "Edited Receiver", newFile.Batches[0].GetEntries()[0].IndividualName)
+		for _, batch := range newFile.Batches[1:] {
+			require.Len(t, batch.GetEntries(), 1)
+			assert.Equal(t, ach.CategoryReturn, batch.GetEntries()[0].Category)
+			assert.NotNil(t, batch.GetEntries()[0].Addenda99)
+		}
+	})
+}
@@ -0,0 +1,255 @@
+package ach
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/nao1215/fileparser"
+)
+
+// jsonBatchGroup names a batches table in the ToJSON document together with
+// the entries and addenda tables nested below its batches.
+type jsonBatchGroup struct {
+	name    string
+	batches *fileparser.TableData
+	entries *fileparser.TableData
+	addenda *fileparser.TableData
+}
+
+// ToJSON writes the TableSet as one JSON document in which the tables are
+// nested instead of flat: file_header is an object, and batches is an array
+// of batch objects, each holding its entries under "entries", each of which
+// holds its addenda under "addenda". IAT and ADV batches are written the same
+// way under iat_batches and adv_batches when the file has them.
+//
+// Objects keep the column order of their tables. Integer columns are written
+// as JSON numbers, an empty integer as null, and other columns as strings.
+// The batch_index and entry_index columns that link a row to its parent are
+// left out of nested objects. The document is built from the current tables,
+// so it reflects any modifications made to them, and account numbers are
+// masked only if the TableSet was created with FromFileOptions.MaskAccounts.
+//
+// An error is returned if an entry or addenda row refers to a batch or entry
+// that is not in its parent table.
+//
+// Example:
+//
+//	w.Header().Set("Content-Type", "application/json")
+//	err := ts.ToJSON(w)
+func (ts *TableSet) ToJSON(w io.Writer) error {
+	if ts == nil {
+		return errors.New("no tables available")
+	}
+
+	doc := &jsonObject{}
+	if ts.FileHeader != nil && len(ts.FileHeader.Records) > 0 {
+		doc.set("file_header", rowObject(ts.FileHeader, ts.FileHeader.Records[0], nil))
+	}
+
+	for _, group := range []jsonBatchGroup{
+		{name: "batches", batches: ts.Batches, entries: ts.Entries, addenda: ts.Addenda},
+		{name: "iat_batches", batches: ts.IATBatches, entries: ts.IATEntries, addenda: ts.IATAddenda},
+		{name: "adv_batches", batches: ts.ADVBatches, entries: ts.ADVEntries},
+	} {
+		if group.batches == nil {
+			continue
+		}
+		batches, err := group.nest()
+		if err != nil {
+			return fmt.Errorf("failed to build %s JSON: %w", group.name, err)
+		}
+		doc.set(group.name, batches)
+	}
+
+	if err := json.NewEncoder(w).Encode(doc); err != nil {
+		return fmt.Errorf("failed to write JSON: %w", err)
+	}
+	return nil
+}
+
+// nest returns the batch objects of g with their entries and addenda.
+func (g jsonBatchGroup) nest() ([]*jsonObject, error) {
+	batches := rowObjects(g.batches, nil)
+	if g.entries == nil {
+		return batches, nil
+	}
+
+	entries, err := childObjects(g.entries, "batch_index")
+	if err != nil {
+		return nil, err
+	}
+	if g.addenda != nil {
+		addenda, err := childObjects(g.addenda, "batch_index", "entry_index")
+		if err != nil {
+			return nil, err
+		}
+		if err := attachChildren(g.entries, entries, g.addenda, addenda, "addenda", "batch_index", "entry_index"); err != nil {
+			return nil, err
+		}
+	}
+	if err := attachChildren(g.batches, batches, g.entries, entries, "entries", "batch_index"); err != nil {
+		return nil, err
+	}
+	return batches, nil
+}
+
+// childObjects returns the row objects of table in order, leaving out the
+// parentKeys columns that link each row to its parent.
+func childObjects(table *fileparser.TableData, parentKeys ...string) ([]*jsonObject, error) {
+	indexes, err := keyIndexes(table, parentKeys)
+	if err != nil {
+		return nil, err
+	}
+	skip := make(map[int]bool, len(indexes))
+	for _, idx := range indexes {
+		skip[idx] = true
+	}
+	return rowObjects(table, skip), nil
+}
+
+// attachChildren adds the child objects of every row of parents to the
+// parent's object under field. Rows are matched by the values of the keys
+// columns, which both tables must have. parentObjs and childObjs hold
+// the objects of the rows of parents and children in order. An error is
+// returned for a child row without a parent row.
+func attachChildren(parents *fileparser.TableData, parentObjs []*jsonObject, children *fileparser.TableData, childObjs []*jsonObject, field string, keys ...string) error {
+	childIndexes, err := keyIndexes(children, keys)
+	if err != nil {
+		return err
+	}
+	groups := make(map[string][]*jsonObject)
+	for i, record := range children.Records {
+		key := rowKey(record, childIndexes)
+		groups[key] = append(groups[key], childObjs[i])
+	}
+
+	parentIndexes, err := keyIndexes(parents, keys)
+	if err != nil {
+		return err
+	}
+	for i, record := range parents.Records {
+		key := rowKey(record, parentIndexes)
+		parentObjs[i].set(field, append([]*jsonObject{}, groups[key]...))
+		delete(groups, key)
+	}
+
+	if len(groups) > 0 {
+		orphans := slices.Sorted(maps.Keys(groups))
+		return fmt.Errorf("%s rows refer to a missing parent row (%s %s)", field, strings.Join(keys, "/"), orphans[0])
+	}
+	return nil
+}
+
+// keyIndexes returns the positions of the keys columns in table.
+func keyIndexes(table *fileparser.TableData, keys []string) ([]int, error) {
+	indexes := make([]int, len(keys))
+	for i, key := range keys {
+		idx := slices.Index(table.Headers, key)
+		if idx < 0 {
+			return nil, fmt.Errorf("table has no %s column", key)
+		}
+		indexes[i] = idx
+	}
+	return indexes, nil
+}
+
+// rowKey joins the values of record at indexes with '/' into a map key.
+func rowKey(record []string, indexes []int) string {
+	values := make([]string, len(indexes))
+	for i, idx := range indexes {
+		if idx < len(record) {
+			values[i] = record[idx]
+		}
+	}
+	return strings.Join(values, "/")
+}
+
+// rowObjects returns an object for every record of table, leaving out the
+// columns in skip.
+func rowObjects(table *fileparser.TableData, skip map[int]bool) []*jsonObject {
+	objects := make([]*jsonObject, len(table.Records))
+	for i, record := range table.Records {
+		objects[i] = rowObject(table, record, skip)
+	}
+	return objects
+}
+
+// rowObject returns record of table as an object keyed by column name,
+// leaving out the columns in skip.
+func rowObject(table *fileparser.TableData, record []string, skip map[int]bool) *jsonObject {
+	obj := &jsonObject{}
+	for i, header := range table.Headers {
+		if skip[i] {
+			continue
+		}
+		var value string
+		if i < len(record) {
+			value = record[i]
+		}
+		colType := fileparser.TypeText
+		if i < len(table.ColumnTypes) {
+			colType = table.ColumnTypes[i]
+		}
+		obj.set(header, jsonValue(value, colType))
+	}
+	return obj
+}
+
+// jsonValue converts value of a column of colType to a JSON value. Integer
+// values become numbers and an empty integer becomes null; everything else
+// stays a string.
+func jsonValue(value string, colType fileparser.ColumnType) any {
+	if colType != fileparser.TypeInteger {
+		return value
+	}
+	if value == "" {
+		return nil
+	}
+	if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return n
+	}
+	return value
+}
+
+// jsonObject is a JSON object that keeps its keys in insertion order.
+type jsonObject struct {
+	keys   []string
+	values []any
+}
+
+// set adds key with value to the object.
+func (o *jsonObject) set(key string, value any) {
+	o.keys = append(o.keys, key)
+	o.values = append(o.values, value)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (o *jsonObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		encodedKey, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		encodedValue, err := json.Marshal(o.values[i])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(encodedKey)
+		buf.WriteByte(':')
+		buf.Write(encodedValue)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,110 @@
+package ach
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/moov-io/ach"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToJSON(t *testing.T) {
+	t.Run("nests entries in batches", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, FromFile(createTestACHFile(t)).ToJSON(&buf))
+
+		var doc struct {
+			FileHeader map[string]any `json:"file_header"`
+			Batches    []struct {
+				BatchIndex  float64          `json:"batch_index"`
+				CompanyName string           `json:"company_name"`
+				Entries     []map[string]any `json:"entries"`
+			} `json:"batches"`
+		}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+
+		assert.Equal(t, "231380104", doc.FileHeader["immediate_destination"])
+		require.Len(t, doc.Batches, 1)
+		assert.Equal(t, "Name on Account", doc.Batches[0].CompanyName)
+		require.Len(t, doc.Batches[0].Entries, 1)
+
+		entry := doc.Batches[0].Entries[0]
+		assert.Equal(t, float64(100000000), entry["amount"])
+		assert.Equal(t, "121042880000001", entry["trace_number"])
+		assert.Equal(t, []any{}, entry["addenda"])
+		assert.NotContains(t, entry, "batch_index")
+		assert.Contains(t, entry, "entry_index")
+	})
+
+	t.Run("keeps column order", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, FromFile(createTestACHFile(t)).ToJSON(&buf))
+
+		out := buf.String()
+		assert.True(t, strings.HasPrefix(out, `{"file_header":{"immediate_destination":"231380104","immediate_origin":`), out)
+		assert.Less(t, strings.Index(out, `"entry_index"`), strings.Index(out, `"transaction_code"`))
+	})
+
+	t.Run("nests addenda in entries", func(t *testing.T) {
+		file, err := ach.ReadFile(findTestFile(t, "return-WEB.ach"))
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		require.NoError(t, FromFile(file).ToJSON(&buf))
+
+		var doc struct {
+			Batches []struct {
+				Entries []struct {
+					Addenda []map[string]any `json:"addenda"`
+				} `json:"entries"`
+			} `json:"batches"`
+		}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+
+		require.NotEmpty(t, doc.Batches)
+		require.NotEmpty(t, doc.Batches[0].Entries)
+		addenda := doc.Batches[0].Entries[0].Addenda
+		require.NotEmpty(t, addenda)
+		assert.Equal(t, "99", addenda[0]["addenda_type"])
+		assert.NotContains(t, addenda[0], "entry_index")
+	})
+
+	t.Run("writes IAT batches", func(t *testing.T) {
+		file, err := ach.ReadFile(findTestFile(t, "iat-credit.ach"))
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		require.NoError(t, FromFile(file).ToJSON(&buf))
+
+		var doc map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+		assert.NotEmpty(t, doc["iat_batches"])
+	})
+
+	t.Run("masks accounts when the table set is masked", func(t *testing.T) {
+		var buf bytes.Buffer
+		ts := FromFileWithOptions(createTestACHFile(t), FromFileOptions{MaskAccounts: true})
+		require.NoError(t, ts.ToJSON(&buf))
+
+		assert.Contains(t, buf.String(), `"****5678"`)
+		assert.NotContains(t, buf.String(), "12345678")
+	})
+
+	t.Run("returns an error for an entry without its batch", func(t *testing.T) {
+		ts := FromFile(createTestACHFile(t))
+		setCell(t, ts.Entries, 0, "batch_index", "7")
+
+		err := ts.ToJSON(&bytes.Buffer{})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "entries rows refer to a missing parent row (batch_index 7)")
+	})
+
+	t.Run("returns an error for a nil table set", func(t *testing.T) {
+		var ts *TableSet
+		assert.Error(t, ts.ToJSON(&bytes.Buffer{}))
+	})
+}
@@ -0,0 +1,139 @@
+package ach
+
+import (
+	"strings"
+
+	"github.com/nao1215/fileparser"
+)
+
+// maskedColumns lists, per table, the columns masked by
+// FromFileOptions.MaskAccounts.
+var maskedColumns = map[string][]string{
+	"entries":     {"dfi_account_number"},
+	"iat_entries": {"dfi_account_number"},
+	"adv_entries": {"dfi_account_number"},
+	"addenda":     {"original_trace", "corrected_data", "original_entry_trace_number"},
+	"iat_addenda": {"original_trace", "corrected_data"},
+}
+
+// maskedRowKeyColumns are the columns that identify a row across SQL
+// modifications. Tables use the ones they have.
+var maskedRowKeyColumns = []string{"batch_index", "entry_index", "addenda_index", "addenda_type"}
+
+// maskedCell identifies a masked value by table, row, and column.
+type maskedCell struct {
+	table  string
+	row    string
+	column string
+}
+
+// maskedTables returns the tables of ts that hold masked columns, by name.
+func (ts *TableSet) maskedTables() map[string]**fileparser.TableData {
+	return map[string]**fileparser.TableData{
+		"entries":     &ts.Entries,
+		"iat_entries": &ts.IATEntries,
+		"adv_entries": &ts.ADVEntries,
+		"addenda":     &ts.Addenda,
+		"iat_addenda": &ts.IATAddenda,
+	}
+}
+
+// maskedRowKey returns the key of record built from the row key columns
+// present in headerIndex.
+func maskedRowKey(record []string, headerIndex map[string]int) string {
+	var sb strings.Builder
+	for _, column := range maskedRowKeyColumns {
+		if idx, ok := headerIndex[column]; ok && idx < len(record) {
+			sb.WriteString(record[idx])
+		}
+		sb.WriteByte(0)
+	}
+	return sb.String()
+}
+
+// maskAccountNumber replaces all but the last 4 characters of an account
+// number with '*'. Values of 4 characters or fewer are returned unchanged.
+func maskAccountNumber(account string) string {
+	runes := []rune(account)
+	if len(runes) <= 4 {
+		return account
+	}
+	return strings.Repeat("*", len(runes)-4) + string(runes[len(runes)-4:])
+}
+
+// maskAccounts masks the account-bearing columns of the tables and keeps
+// the original values so that ToFile can restore them.
+func (ts *TableSet) maskAccounts() {
+	ts.maskedValues = make(map[maskedCell]string)
+	for name, table := range ts.maskedTables() {
+		if *table == nil {
+			continue
+		}
+		headerIndex := make(map[string]int)
+		for i, h := range (*table).Headers {
+			headerIndex[h] = i
+		}
+		for _, record := range (*table).Records {
+			row := maskedRowKey(record, headerIndex)
+			for _, column := range maskedColumns[name] {
+				idx, ok := headerIndex[column]
+				if !ok || idx >= len(record) {
+					continue
+				}
+				masked := maskAccountNumber(record[idx])
+				if masked == record[idx] {
+					continue
+				}
+				ts.maskedValues[maskedCell{table: name, row: row, column: column}] = record[idx]
+				record[idx] = masked
+			}
+		}
+	}
+}
+
+// unmasked returns a copy of ts whose tables hold the original values of
+// masked cells that were not modified. A masked cell that was given a new
+// value keeps the new value.
+func (ts *TableSet) unmasked() *TableSet {
+	if len(ts.maskedValues) == 0 {
+		return ts
+	}
+
+	out := *ts
+	for name, table := range out.maskedTables() {
+		if *table == nil {
+			continue
+		}
+		headerIndex := make(map[string]int)
+		for i, h := range (*table).Headers {
+			headerIndex[h] = i
+		}
+
+		records := make([][]string, len((*table).Records))
+		for i, record := range (*table).Records {
+			row := maskedRowKey(record, headerIndex)
+			for _, column := range maskedColumns[name] {
+				idx, ok := headerIndex[column]
+				if !ok || idx >= len(record) {
+					continue
+				}
+				original, ok := ts.maskedValues[maskedCell{table: name, row: row, column: column}]
+				if !ok || record[idx] != maskAccountNumber(original) {
+					continue
+				}
+				if records[i] == nil {
+					records[i] = append([]string(nil), record...)
+				}
+				records[i][idx] = original
+			}
+			if records[i] == nil {
+				records[i] = record
+			}
+		}
+
+		copied := **table
+		copied.Records = records
+		*table = &copied
+	}
+	return &out
+}
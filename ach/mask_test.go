@@ -0,0 +1,92 @@
+package ach
+
+import (
+	"testing"
+
+	"github.com/moov-io/ach"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaskAccountNumber(t *testing.T) {
+	assert.Equal(t, "****5678", maskAccountNumber("12345678"))
+	assert.Equal(t, "1234", maskAccountNumber("1234"))
+	assert.Empty(t, maskAccountNumber(""))
+}
+
+func TestFromFileWithOptions_MaskAccounts(t *testing.T) {
+	column := func(t *testing.T, table [][]string, headers []string, name string) []string {
+		t.Helper()
+		for i, h := range headers {
+			if h == name {
+				values := make([]string, 0, len(table))
+				for _, record := range table {
+					values = append(values, record[i])
+				}
+				return values
+			}
+		}
+		t.Fatalf("column %q not found", name)
+		return nil
+	}
+
+	t.Run("masks entries and restores them in ToFile", func(t *testing.T) {
+		file, err := ach.ReadFile(findTestACHFile(t))
+		require.NoError(t, err)
+
+		ts := FromFileWithOptions(file, FromFileOptions{MaskAccounts: true})
+		require.NotNil(t, ts)
+		assert.Equal(t, []string{"****5678"}, column(t, ts.Entries.Records, ts.Entries.Headers, "dfi_account_number"))
+
+		newFile, err := ts.ToFile()
+		require.NoError(t, err)
+		assert.Equal(t, "12345678", newFile.Batches[0].GetEntries()[0].DFIAccountNumber)
+	})
+
+	t.Run("writes a modified masked value", func(t *testing.T) {
+		file, err := ach.ReadFile(findTestACHFile(t))
+		require.NoError(t, err)
+
+		ts := FromFileWithOptions(file, FromFileOptions{MaskAccounts: true})
+		for i, h := range ts.Entries.Headers {
+			if h == "dfi_account_number" {
+				ts.Entries.Records[0][i] = "99990000"
+			}
+		}
+
+		newFile, err := ts.ToFile()
+		require.NoError(t, err)
+		assert.Equal(t, "99990000", newFile.Batches[0].GetEntries()[0].DFIAccountNumber)
+	})
+
+	t.Run("masks account-bearing addenda fields", func(t *testing.T) {
+		file, err := ach.ReadFile(findTestFile(t, "cor-example.ach"))
+		require.NoError(t, err)
+
+		plain := FromFile(file)
+		ts := FromFileWithOptions(file, FromFileOptions{MaskAccounts: true})
+
+		originalTrace := column(t, plain.Addenda.Records, plain.Addenda.Headers, "original_trace")
+		maskedTrace := column(t, ts.Addenda.Records, ts.Addenda.Headers, "original_trace")
+		require.NotEmpty(t, originalTrace)
+		for i := range originalTrace {
+			assert.Equal(t, maskAccountNumber(originalTrace[i]), maskedTrace[i])
+		}
+		assert.NotEqual(t, originalTrace, maskedTrace)
+
+		newFile, err := ts.ToFile()
+		require.NoError(t, err)
+		assert.Equal(t, file.Batches[0].GetEntries()[0].Addenda98.OriginalTrace,
+			newFile.Batches[0].GetEntries()[0].Addenda98.OriginalTrace)
+		assert.Equal(t, file.Batches[0].GetEntries()[0].Addenda98.CorrectedData,
+			newFile.Batches[0].GetEntries()[0].Addenda98.CorrectedData)
+	})
+
+	t.Run("leaves the tables of FromFile unmasked", func(t *testing.T) {
+		file, err := ach.ReadFile(findTestACHFile(t))
+		require.NoError(t, err)
+
+		ts := FromFile(file)
+		assert.Equal(t, []string{"12345678"}, column(t, ts.Entries.Records, ts.Entries.Headers, "dfi_account_number"))
+	})
+}
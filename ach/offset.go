@@ -0,0 +1,113 @@
+package ach
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/moov-io/ach"
+)
+
+// offsetIndividualName is the individual name used for generated offset
+// entries. It matches the name moov-io/ach uses for its own offset records.
+const offsetIndividualName = "OFFSET"
+
+// offsetTransactionCodes pairs the credit and debit transaction codes of each
+// account type that can receive an offset entry.
+var offsetTransactionCodes = map[int][2]int{
+	ach.CheckingCredit: {ach.CheckingCredit, ach.CheckingDebit},
+	ach.CheckingDebit:  {ach.CheckingCredit, ach.CheckingDebit},
+	ach.SavingsCredit:  {ach.SavingsCredit, ach.SavingsDebit},
+	ach.SavingsDebit:   {ach.SavingsCredit, ach.SavingsDebit},
+	ach.GLCredit:       {ach.GLCredit, ach.GLDebit},
+	ach.GLDebit:        {ach.GLCredit, ach.GLDebit},
+	ach.LoanCredit:     {ach.LoanCredit, ach.LoanDebit},
+	ach.LoanDebit:      {ach.LoanCredit, ach.LoanDebit},
+}
+
+// AddOffset appends a balancing offset entry to every standard batch whose
+// credits and debits do not net to zero, then recomputes the batch and file
+// control records.
+//
+// transactionCode selects the account type of the offset account (checking,
+// savings, GL, or loan). The direction of each offset entry is chosen to
+// balance its batch, so passing ach.CheckingCredit or ach.CheckingDebit has the
+// same effect. Batches that already net to zero are left unchanged, and the
+// service class code of a balanced batch becomes 200 (mixed debits and credits).
+//
+// Pending modifications in the TableSet are applied first, and all tables are
+// rebuilt from the resulting file. IAT batches are not modified.
+func (ts *TableSet) AddOffset(routingNumber, accountNumber string, transactionCode int) error {
+	if ts == nil || ts.originalFile == nil {
+		return errors.New("no original ACH file available")
+	}
+	if err := ach.CheckRoutingNumber(routingNumber); err != nil {
+		return fmt.Errorf("invalid offset routing number %s: %w", routingNumber, err)
+	}
+	if accountNumber == "" {
+		return errors.New("offset account number cannot be empty")
+	}
+	codes, ok := offsetTransactionCodes[transactionCode]
+	if !ok {
+		return fmt.Errorf("unsupported offset transaction code %d", transactionCode)
+	}
+
+	file, err := ts.ToFile()
+	if err != nil {
+		return err
+	}
+
+	for batchIdx, batch := range file.Batches {
+		entries := batch.GetEntries()
+		net := 0
+		for _, entry := range entries {
+			net += signedAmount(entry.TransactionCode, entry.Amount)
+		}
+		if net == 0 {
+			continue
+		}
+
+		entry := ach.NewEntryDetail()
+		entry.SetRDFI(routingNumber)
+		entry.DFIAccountNumber = accountNumber
+		entry.IndividualName = offsetIndividualName
+		if net > 0 {
+			// Credits exceed debits, so the offset debits the offset account
+			entry.TransactionCode = codes[1]
+			entry.Amount = net
+		} else {
+			entry.TransactionCode = codes[0]
+			entry.Amount = -net
+		}
+		entry.TraceNumber = fmt.Sprintf("%15.15d", lastTraceNumber(entries)+1)
+		if len(entries) > 0 {
+			entry.Category = entries[0].Category
+		}
+
+		batch.GetHeader().ServiceClassCode = ach.MixedDebitsAndCredits
+		batch.AddEntry(entry)
+		if err := batch.Create(); err != nil {
+			return fmt.Errorf("failed to add offset to batch %d: %w", batchIdx, err)
+		}
+	}
+
+	if err := file.Create(); err != nil {
+		return fmt.Errorf("failed to create file control: %w", err)
+	}
+
+	*ts = *FromFileWithOptions(file, ts.options)
+	return nil
+}
+
+// lastTraceNumber returns the numeric trace number of the last entry, or 0
+// when there are no entries or the trace number is not numeric.
+func lastTraceNumber(entries []*ach.EntryDetail) int {
+	if len(entries) == 0 {
+		return 0
+	}
+	n, err := strconv.Atoi(entries[len(entries)-1].TraceNumber)
+	if err != nil {
+		return 0
+	}
+	return n
+}
@@ -0,0 +1,92 @@
+package ach
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/moov-io/ach"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddOffset(t *testing.T) {
+	t.Run("balances a debit batch", func(t *testing.T) {
+		ts := FromFile(createTestACHFile(t))
+		require.Len(t, ts.Entries.Records, 1)
+
+		err := ts.AddOffset("231380104", "987654321", ach.CheckingCredit)
+		require.NoError(t, err)
+
+		// Batch nets to zero after the offset
+		summary := ts.GetSummaryTable()
+		require.Len(t, summary.Records, 1)
+		assert.Equal(t, []string{"0", "2", "100000000", "100000000", "0", "0", "0"}, summary.Records[0])
+
+		// Offset entry is appended to the batch
+		require.Len(t, ts.Entries.Records, 2)
+		offset := ts.Entries.Records[1]
+		assert.Equal(t, "22", offset[2])        // transaction_code
+		assert.Equal(t, "23138010", offset[3])  // rdfi_identification
+		assert.Equal(t, "4", offset[4])         // check_digit
+		assert.Equal(t, "987654321", offset[5]) // dfi_account_number
+		assert.Equal(t, "100000000", offset[6]) // amount
+		assert.Equal(t, "OFFSET", offset[8])    // individual_name
+
+		// Controls are recomputed
+		file, err := ts.ToFile()
+		require.NoError(t, err)
+		control := file.Batches[0].GetControl()
+		assert.Equal(t, 100000000, control.TotalCreditEntryDollarAmount)
+		assert.Equal(t, 100000000, control.TotalDebitEntryDollarAmount)
+		assert.Equal(t, 2, control.EntryAddendaCount)
+		assert.Equal(t, ach.MixedDebitsAndCredits, file.Batches[0].GetHeader().ServiceClassCode)
+		assert.NoError(t, file.Validate())
+
+		var buf bytes.Buffer
+		require.NoError(t, ts.WriteToWriter(&buf))
+	})
+
+	t.Run("chooses direction from the batch net", func(t *testing.T) {
+		ts := FromFile(createTestACHFile(t))
+
+		// A debit code still produces a credit offset for a debit batch
+		err := ts.AddOffset("231380104", "987654321", ach.CheckingDebit)
+		require.NoError(t, err)
+
+		assert.Equal(t, "22", ts.Entries.Records[1][2])
+	})
+
+	t.Run("applies pending table modifications", func(t *testing.T) {
+		ts := FromFile(createTestACHFile(t))
+		ts.Entries.Records[0][6] = "5000" // amount
+
+		err := ts.AddOffset("231380104", "987654321", ach.SavingsCredit)
+		require.NoError(t, err)
+
+		require.Len(t, ts.Entries.Records, 2)
+		assert.Equal(t, "5000", ts.Entries.Records[0][6])
+		assert.Equal(t, "32", ts.Entries.Records[1][2])
+		assert.Equal(t, "5000", ts.Entries.Records[1][6])
+	})
+
+	t.Run("leaves balanced batches unchanged", func(t *testing.T) {
+		ts := FromFile(createTestACHFile(t))
+		require.NoError(t, ts.AddOffset("231380104", "987654321", ach.CheckingCredit))
+
+		err := ts.AddOffset("231380104", "987654321", ach.CheckingCredit)
+		require.NoError(t, err)
+
+		assert.Len(t, ts.Entries.Records, 2)
+	})
+
+	t.Run("returns error for invalid input", func(t *testing.T) {
+		ts := FromFile(createTestACHFile(t))
+
+		assert.Error(t, ts.AddOffset("12345", "987654321", ach.CheckingCredit))
+		assert.Error(t, ts.AddOffset("231380104", "", ach.CheckingCredit))
+		assert.Error(t, ts.AddOffset("231380104", "987654321", ach.CheckingReturnNOCCredit))
+
+		var nilTS *TableSet
+		assert.Error(t, nilTS.AddOffset("231380104", "987654321", ach.CheckingCredit))
+	})
+}
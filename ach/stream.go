@@ -0,0 +1,254 @@
+package ach
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/moov-io/ach"
+)
+
+// entryHashModulus keeps the file entry hash within its 10-digit field.
+const entryHashModulus = 10_000_000_000
+
+// FileHeaderSpec describes the file header of an ACH file written by WriteACHStream.
+type FileHeaderSpec struct {
+	// ImmediateDestination is the 9-digit routing number of the receiving point.
+	ImmediateDestination string
+	// ImmediateOrigin is the routing number or identifier of the sending point.
+	ImmediateOrigin string
+	// ImmediateDestinationName is the name of the receiving point.
+	ImmediateDestinationName string
+	// ImmediateOriginName is the name of the sending point.
+	ImmediateOriginName string
+	// FileIDModifier distinguishes files created on the same date. Defaults to "A".
+	FileIDModifier string
+	// FileCreation is the file creation date and time. Defaults to the current time.
+	FileCreation time.Time
+}
+
+// EntrySpec describes one entry detail record for WriteACHStream.
+// The company and SEC fields determine the batch the entry belongs to.
+type EntrySpec struct {
+	// StandardEntryClassCode is the SEC code of the batch, e.g. "PPD" or "CCD".
+	StandardEntryClassCode string
+	// CompanyName is the originator name of the batch.
+	CompanyName string
+	// CompanyIdentification is the originator identifier of the batch.
+	CompanyIdentification string
+	// CompanyEntryDescription describes the purpose of the batch, e.g. "PAYROLL".
+	CompanyEntryDescription string
+	// EffectiveEntryDate is the requested settlement date in YYMMDD format.
+	EffectiveEntryDate string
+	// ODFIIdentification is the first 8 digits of the originating bank's routing number.
+	ODFIIdentification string
+
+	// TransactionCode is the entry's transaction code, e.g. ach.CheckingCredit.
+	TransactionCode int
+	// RDFIRoutingNumber is the receiving bank's 9-digit routing number.
+	RDFIRoutingNumber string
+	// DFIAccountNumber is the receiver's account number.
+	DFIAccountNumber string
+	// Amount is the entry amount in cents.
+	Amount int
+	// IdentificationNumber is the receiver's identification number.
+	IdentificationNumber string
+	// IndividualName is the receiver's name.
+	IndividualName string
+	// DiscretionaryData is optional data for the ODFI's use.
+	DiscretionaryData string
+}
+
+// batchKey returns the fields that put entries into the same batch.
+func (e EntrySpec) batchKey() [6]string {
+	return [6]string{
+		e.StandardEntryClassCode,
+		e.CompanyName,
+		e.CompanyIdentification,
+		e.CompanyEntryDescription,
+		e.EffectiveEntryDate,
+		e.ODFIIdentification,
+	}
+}
+
+// achStreamWriter writes ACH records incrementally and accumulates the
+// totals needed for the file control record.
+type achStreamWriter struct {
+	w           *bufio.Writer
+	control     ach.FileControl
+	lines       int
+	traceNumber int
+}
+
+// WriteACHStream writes an ACH file to w from entries received on a channel,
+// without holding the whole file in memory. It returns after entries is
+// closed and the file control record has been written.
+//
+// Consecutive entries with the same SEC code, company, description, effective
+// date, and ODFI are grouped into one batch; a batch is written as soon as an
+// entry for a different batch arrives. Send entries sorted by batch to avoid
+// splitting a batch. Batch control totals, trace numbers, the file control
+// record, and block padding are computed automatically.
+//
+// On error, WriteACHStream stops reading from entries, and the output written
+// so far is incomplete.
+//
+// Example:
+//
+//	entries := make(chan ach.EntrySpec)
+//	go func() {
+//	    defer close(entries)
+//	    for _, p := range payments {
+//	        entries <- p.ToEntrySpec()
+//	    }
+//	}()
+//	err := ach.WriteACHStream(w, header, entries)
+func WriteACHStream(w io.Writer, header FileHeaderSpec, entries <-chan EntrySpec) error {
+	if w == nil {
+		return errors.New("writer cannot be nil")
+	}
+	if entries == nil {
+		return errors.New("entries channel cannot be nil")
+	}
+
+	fh := ach.NewFileHeader()
+	fh.ImmediateDestination = header.ImmediateDestination
+	fh.ImmediateOrigin = header.ImmediateOrigin
+	fh.ImmediateDestinationName = header.ImmediateDestinationName
+	fh.ImmediateOriginName = header.ImmediateOriginName
+	if header.FileIDModifier != "" {
+		fh.FileIDModifier = header.FileIDModifier
+	}
+	created := header.FileCreation
+	if created.IsZero() {
+		created = time.Now()
+	}
+	fh.FileCreationDate = created.Format("060102")
+	fh.FileCreationTime = created.Format("1504")
+	if err := fh.Validate(); err != nil {
+		return fmt.Errorf("invalid file header: %w", err)
+	}
+
+	sw := &achStreamWriter{w: bufio.NewWriter(w), control: ach.NewFileControl()}
+	sw.writeLine(fh.String())
+
+	var (
+		pending []EntrySpec
+		key     [6]string
+	)
+	for entry := range entries {
+		if len(pending) > 0 && entry.batchKey() != key {
+			if err := sw.writeBatch(pending); err != nil {
+				return err
+			}
+			pending = pending[:0]
+		}
+		key = entry.batchKey()
+		pending = append(pending, entry)
+	}
+	if len(pending) > 0 {
+		if err := sw.writeBatch(pending); err != nil {
+			return err
+		}
+	}
+
+	return sw.finish()
+}
+
+// writeBatch builds a batch from entries and writes its header, entries, and control.
+func (sw *achStreamWriter) writeBatch(entries []EntrySpec) error {
+	first := entries[0]
+	bh := ach.NewBatchHeader()
+	bh.StandardEntryClassCode = first.StandardEntryClassCode
+	bh.CompanyName = first.CompanyName
+	bh.CompanyIdentification = first.CompanyIdentification
+	bh.CompanyEntryDescription = first.CompanyEntryDescription
+	bh.EffectiveEntryDate = first.EffectiveEntryDate
+	bh.ODFIIdentification = first.ODFIIdentification
+	bh.BatchNumber = sw.control.BatchCount + 1
+
+	hasCredit, hasDebit := false, false
+	for _, entry := range entries {
+		if signedAmount(entry.TransactionCode, 1) < 0 {
+			hasDebit = true
+		} else {
+			hasCredit = true
+		}
+	}
+	switch {
+	case hasCredit && hasDebit:
+		bh.ServiceClassCode = ach.MixedDebitsAndCredits
+	case hasDebit:
+		bh.ServiceClassCode = ach.DebitsOnly
+	default:
+		bh.ServiceClassCode = ach.CreditsOnly
+	}
+
+	batch, err := ach.NewBatch(bh)
+	if err != nil {
+		return fmt.Errorf("failed to create batch %d: %w", bh.BatchNumber, err)
+	}
+	for _, spec := range entries {
+		sw.traceNumber++
+		entry := ach.NewEntryDetail()
+		entry.TransactionCode = spec.TransactionCode
+		entry.SetRDFI(spec.RDFIRoutingNumber)
+		entry.DFIAccountNumber = spec.DFIAccountNumber
+		entry.Amount = spec.Amount
+		entry.IdentificationNumber = spec.IdentificationNumber
+		entry.IndividualName = spec.IndividualName
+		entry.DiscretionaryData = spec.DiscretionaryData
+		entry.Category = ach.CategoryForward
+		entry.SetTraceNumber(spec.ODFIIdentification, sw.traceNumber)
+		batch.AddEntry(entry)
+	}
+	if err := batch.Create(); err != nil {
+		return fmt.Errorf("failed to create batch %d: %w", bh.BatchNumber, err)
+	}
+
+	sw.writeLine(batch.GetHeader().String())
+	for _, entry := range batch.GetEntries() {
+		sw.writeLine(entry.String())
+	}
+	control := batch.GetControl()
+	sw.writeLine(control.String())
+
+	sw.control.BatchCount++
+	sw.control.EntryAddendaCount += control.EntryAddendaCount
+	sw.control.EntryHash = (sw.control.EntryHash + control.EntryHash) % entryHashModulus
+	sw.control.TotalDebitEntryDollarAmountInFile += control.TotalDebitEntryDollarAmount
+	sw.control.TotalCreditEntryDollarAmountInFile += control.TotalCreditEntryDollarAmount
+
+	if err := sw.w.Flush(); err != nil {
+		return fmt.Errorf("failed to write ACH file: %w", err)
+	}
+	return nil
+}
+
+// finish writes the file control record and the block padding lines.
+func (sw *achStreamWriter) finish() error {
+	totalLines := sw.lines + 1
+	sw.control.BlockCount = (totalLines + 9) / 10
+	sw.writeLine(sw.control.String())
+
+	padding := strings.Repeat("9", 94)
+	for sw.lines%10 != 0 {
+		sw.writeLine(padding)
+	}
+
+	if err := sw.w.Flush(); err != nil {
+		return fmt.Errorf("failed to write ACH file: %w", err)
+	}
+	return nil
+}
+
+// writeLine writes one record followed by a newline.
+// Write errors are reported by the next Flush.
+func (sw *achStreamWriter) writeLine(record string) {
+	sw.w.WriteString(record) //nolint:errcheck // reported by Flush
+	sw.w.WriteByte('\n')     //nolint:errcheck // reported by Flush
+	sw.lines++
+}
@@ -0,0 +1,139 @@
+package ach
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/moov-io/ach"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testFileHeaderSpec() FileHeaderSpec {
+	return FileHeaderSpec{
+		ImmediateDestination:     "231380104",
+		ImmediateOrigin:          "121042882",
+		ImmediateDestinationName: "Federal Reserve Bank",
+		ImmediateOriginName:      "My Bank Name",
+		FileCreation:             time.Date(2024, 1, 15, 9, 30, 0, 0, time.UTC),
+	}
+}
+
+func testEntrySpec(sec, company string, transactionCode, amount int) EntrySpec {
+	return EntrySpec{
+		StandardEntryClassCode:  sec,
+		CompanyName:             company,
+		CompanyIdentification:   "121042882",
+		CompanyEntryDescription: "PAYMENT",
+		EffectiveEntryDate:      "240116",
+		ODFIIdentification:      "12104288",
+		TransactionCode:         transactionCode,
+		RDFIRoutingNumber:       "231380104",
+		DFIAccountNumber:        "123456789",
+		Amount:                  amount,
+		IndividualName:          "Receiver Account Name",
+	}
+}
+
+func TestWriteACHStream(t *testing.T) {
+	t.Run("writes 1000 entries from a channel", func(t *testing.T) {
+		entries := make(chan EntrySpec)
+		go func() {
+			defer close(entries)
+			for i := range 1000 {
+				company := "Company A"
+				if i >= 600 {
+					company = "Company B"
+				}
+				entries <- testEntrySpec("PPD", company, ach.CheckingCredit, i+1)
+			}
+		}()
+
+		var buf bytes.Buffer
+		err := WriteACHStream(&buf, testFileHeaderSpec(), entries)
+		require.NoError(t, err)
+
+		lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+		assert.Zero(t, len(lines)%10, "file must be padded to a multiple of 10 lines")
+
+		file, err := ach.NewReader(strings.NewReader(buf.String())).Read()
+		require.NoError(t, err)
+		require.NoError(t, file.Validate())
+
+		require.Len(t, file.Batches, 2)
+		assert.Len(t, file.Batches[0].GetEntries(), 600)
+		assert.Len(t, file.Batches[1].GetEntries(), 400)
+		assert.Equal(t, ach.CreditsOnly, file.Batches[0].GetHeader().ServiceClassCode)
+		assert.Equal(t, 1000, file.Control.EntryAddendaCount)
+		assert.Equal(t, 1000*1001/2, file.Control.TotalCreditEntryDollarAmountInFile)
+		assert.Zero(t, file.Control.TotalDebitEntryDollarAmountInFile)
+
+		ts := FromFile(&file)
+		assert.Len(t, ts.Entries.Records, 1000)
+	})
+
+	t.Run("sets service class code from entry directions", func(t *testing.T) {
+		entries := make(chan EntrySpec, 3)
+		entries <- testEntrySpec("PPD", "Company A", ach.CheckingCredit, 100)
+		entries <- testEntrySpec("PPD", "Company A", ach.CheckingDebit, 40)
+		entries <- testEntrySpec("CCD", "Company A", ach.CheckingDebit, 60)
+		close(entries)
+
+		var buf bytes.Buffer
+		require.NoError(t, WriteACHStream(&buf, testFileHeaderSpec(), entries))
+
+		file, err := ach.NewReader(&buf).Read()
+		require.NoError(t, err)
+		require.NoError(t, file.Validate())
+		require.Len(t, file.Batches, 2)
+		assert.Equal(t, ach.MixedDebitsAndCredits, file.Batches[0].GetHeader().ServiceClassCode)
+		assert.Equal(t, "CCD", file.Batches[1].GetHeader().StandardEntryClassCode)
+		assert.Equal(t, ach.DebitsOnly, file.Batches[1].GetHeader().ServiceClassCode)
+		assert.Equal(t, 100, file.Control.TotalDebitEntryDollarAmountInFile)
+	})
+
+	t.Run("returns error for invalid entry", func(t *testing.T) {
+		entries := make(chan EntrySpec, 1)
+		bad := testEntrySpec("PPD", "Company A", ach.CheckingCredit, 100)
+		bad.TransactionCode = 99
+		entries <- bad
+		close(entries)
+
+		err := WriteACHStream(&bytes.Buffer{}, testFileHeaderSpec(), entries)
+
+		require.Error(t, err)
+	})
+
+	t.Run("returns error for invalid file header", func(t *testing.T) {
+		entries := make(chan EntrySpec)
+		close(entries)
+
+		err := WriteACHStream(&bytes.Buffer{}, FileHeaderSpec{}, entries)
+
+		require.Error(t, err)
+	})
+
+	t.Run("returns write error", func(t *testing.T) {
+		entries := make(chan EntrySpec)
+		close(entries)
+
+		err := WriteACHStream(failingWriter{}, testFileHeaderSpec(), entries)
+
+		require.Error(t, err)
+	})
+
+	t.Run("returns error for nil arguments", func(t *testing.T) {
+		assert.Error(t, WriteACHStream(nil, testFileHeaderSpec(), make(chan EntrySpec)))
+		assert.Error(t, WriteACHStream(&bytes.Buffer{}, testFileHeaderSpec(), nil))
+	})
+}
+
+// failingWriter is an io.Writer that always fails.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("write failed")
+}
@@ -0,0 +1,254 @@
+package ach
+
+import (
+	"strconv"
+
+	"github.com/nao1215/fileparser"
+)
+
+// signedAmount returns amount signed by the direction of the transaction code.
+// ACH amounts are always unsigned; the second digit of the transaction code
+// tells credits (0-4) from debits (5-9), so debits are returned as negative
+// values. This holds for returns and NOCs too, e.g. code 26 (return of a
+// checking debit) nets negatively and code 21 (return of a checking credit)
+// nets positively.
+func signedAmount(transactionCode, amount int) int {
+	if transactionCode%10 >= 5 {
+		return -amount
+	}
+	return amount
+}
+
+// isReturnAddendaType reports whether the addenda_type value marks a return entry.
+func isReturnAddendaType(addendaType string) bool {
+	return addendaType == "99" || addendaType == addendaType99Dishonored || addendaType == addendaType99Contested
+}
+
+// GetReturnsTable returns a derived view of the entries that carry a return
+// addenda (Addenda99, Addenda99Dishonored, or Addenda99Contested).
+// The signed_amount column applies the sign implied by the transaction code.
+// The view is computed from the current Entries and Addenda tables, so it
+// reflects any modifications made through UpdateEntriesFromTableData and
+// UpdateAddendaFromTableData.
+func (ts *TableSet) GetReturnsTable() *fileparser.TableData {
+	if ts == nil {
+		return nil
+	}
+
+	headers := []string{
+		"batch_index",
+		"entry_index",
+		"transaction_code",
+		"amount",
+		"signed_amount",
+		"return_code",
+		"original_trace",
+	}
+
+	columnTypes := []fileparser.ColumnType{
+		fileparser.TypeInteger, // batch_index
+		fileparser.TypeInteger, // entry_index
+		fileparser.TypeInteger, // transaction_code
+		fileparser.TypeInteger, // amount (in cents)
+		fileparser.TypeInteger, // signed_amount (in cents)
+		fileparser.TypeText,    // return_code
+		fileparser.TypeText,    // original_trace
+	}
+
+	returns := ts.returnAddenda()
+	records := [][]string{}
+	for _, entry := range ts.entryAmounts() {
+		addenda, ok := returns[entryKey{entry.batchIndex, entry.entryIndex}]
+		if !ok {
+			continue
+		}
+		records = append(records, []string{
+			entry.batchIndex,
+			entry.entryIndex,
+			strconv.Itoa(entry.transactionCode),
+			strconv.Itoa(entry.amount),
+			strconv.Itoa(signedAmount(entry.transactionCode, entry.amount)),
+			addenda.returnCode,
+			addenda.originalTrace,
+		})
+	}
+
+	return &fileparser.TableData{
+		Headers:     headers,
+		Records:     records,
+		ColumnTypes: columnTypes,
+	}
+}
+
+// GetSummaryTable returns a derived view with one row per batch holding the
+// credit, debit, and net totals of its entries. Amounts are signed by the
+// transaction code, so net_amount is credits minus debits. return_count and
+// return_net_amount cover only entries that carry a return addenda.
+// The view is computed from the current Entries and Addenda tables.
+func (ts *TableSet) GetSummaryTable() *fileparser.TableData {
+	if ts == nil {
+		return nil
+	}
+
+	headers := []string{
+		"batch_index",
+		"entry_count",
+		"credit_amount",
+		"debit_amount",
+		"net_amount",
+		"return_count",
+		"return_net_amount",
+	}
+
+	columnTypes := []fileparser.ColumnType{
+		fileparser.TypeInteger, // batch_index
+		fileparser.TypeInteger, // entry_count
+		fileparser.TypeInteger, // credit_amount (in cents)
+		fileparser.TypeInteger, // debit_amount (in cents)
+		fileparser.TypeInteger, // net_amount (in cents)
+		fileparser.TypeInteger, // return_count
+		fileparser.TypeInteger, // return_net_amount (in cents)
+	}
+
+	type batchSummary struct {
+		entryCount      int
+		creditAmount    int
+		debitAmount     int
+		netAmount       int
+		returnCount     int
+		returnNetAmount int
+	}
+
+	returns := ts.returnAddenda()
+	var order []string
+	summaries := make(map[string]*batchSummary)
+	for _, entry := range ts.entryAmounts() {
+		summary, ok := summaries[entry.batchIndex]
+		if !ok {
+			summary = &batchSummary{}
+			summaries[entry.batchIndex] = summary
+			order = append(order, entry.batchIndex)
+		}
+
+		signed := signedAmount(entry.transactionCode, entry.amount)
+		summary.entryCount++
+		summary.netAmount += signed
+		if signed < 0 {
+			summary.debitAmount -= signed
+		} else {
+			summary.creditAmount += signed
+		}
+		if _, ok := returns[entryKey{entry.batchIndex, entry.entryIndex}]; ok {
+			summary.returnCount++
+			summary.returnNetAmount += signed
+		}
+	}
+
+	records := make([][]string, 0, len(order))
+	for _, batchIndex := range order {
+		summary := summaries[batchIndex]
+		records = append(records, []string{
+			batchIndex,
+			strconv.Itoa(summary.entryCount),
+			strconv.Itoa(summary.creditAmount),
+			strconv.Itoa(summary.debitAmount),
+			strconv.Itoa(summary.netAmount),
+			strconv.Itoa(summary.returnCount),
+			strconv.Itoa(summary.returnNetAmount),
+		})
+	}
+
+	return &fileparser.TableData{
+		Headers:     headers,
+		Records:     records,
+		ColumnTypes: columnTypes,
+	}
+}
+
+// entryKey identifies an entry by its batch_index and entry_index values.
+type entryKey struct {
+	batchIndex string
+	entryIndex string
+}
+
+// entryAmount holds the fields of an entries row needed for amount calculations.
+type entryAmount struct {
+	batchIndex      string
+	entryIndex      string
+	transactionCode int
+	amount          int
+}
+
+// entryAmounts reads the amount-related columns from the Entries table.
+// Rows with a non-numeric transaction code or amount are skipped.
+func (ts *TableSet) entryAmounts() []entryAmount {
+	if ts.Entries == nil {
+		return nil
+	}
+
+	headerIndex := make(map[string]int)
+	for i, h := range ts.Entries.Headers {
+		headerIndex[h] = i
+	}
+
+	var entries []entryAmount
+	for _, record := range ts.Entries.Records {
+		code, err := strconv.Atoi(columnValue(record, headerIndex, "transaction_code"))
+		if err != nil {
+			continue
+		}
+		amount, err := strconv.Atoi(columnValue(record, headerIndex, "amount"))
+		if err != nil {
+			continue
+		}
+		entries = append(entries, entryAmount{
+			batchIndex:      columnValue(record, headerIndex, "batch_index"),
+			entryIndex:      columnValue(record, headerIndex, "entry_index"),
+			transactionCode: code,
+			amount:          amount,
+		})
+	}
+	return entries
+}
+
+// returnAddendaInfo holds the fields of a return addenda row shown in the returns view.
+type returnAddendaInfo struct {
+	returnCode    string
+	originalTrace string
+}
+
+// returnAddenda indexes the return addenda rows of the Addenda table by entry.
+func (ts *TableSet) returnAddenda() map[entryKey]returnAddendaInfo {
+	returns := make(map[entryKey]returnAddendaInfo)
+	if ts.Addenda == nil {
+		return returns
+	}
+
+	headerIndex := make(map[string]int)
+	for i, h := range ts.Addenda.Headers {
+		headerIndex[h] = i
+	}
+
+	for _, record := range ts.Addenda.Records {
+		if !isReturnAddendaType(columnValue(record, headerIndex, "addenda_type")) {
+			continue
+		}
+		key := entryKey{
+			batchIndex: columnValue(record, headerIndex, "batch_index"),
+			entryIndex: columnValue(record, headerIndex, "entry_index"),
+		}
+		returns[key] = returnAddendaInfo{
+			returnCode:    columnValue(record, headerIndex, "return_code"),
+			originalTrace: columnValue(record, headerIndex, "original_trace"),
+		}
+	}
+	return returns
+}
+
+// columnValue returns the value of the named column in record, or "" if absent.
+func columnValue(record []string, headerIndex map[string]int, name string) string {
+	if idx, ok := headerIndex[name]; ok && idx < len(record) {
+		return record[idx]
+	}
+	return ""
+}
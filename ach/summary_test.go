@@ -0,0 +1,95 @@
+package ach
+
+import (
+	"testing"
+
+	"github.com/moov-io/ach"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignedAmount(t *testing.T) {
+	tests := []struct {
+		name            string
+		transactionCode int
+		amount          int
+		want            int
+	}{
+		{name: "checking credit", transactionCode: ach.CheckingCredit, amount: 100, want: 100},
+		{name: "checking debit", transactionCode: ach.CheckingDebit, amount: 100, want: -100},
+		{name: "returned checking credit", transactionCode: ach.CheckingReturnNOCCredit, amount: 100, want: 100},
+		{name: "returned checking debit", transactionCode: ach.CheckingReturnNOCDebit, amount: 100, want: -100},
+		{name: "savings debit", transactionCode: ach.SavingsDebit, amount: 250, want: -250},
+		{name: "GL credit", transactionCode: ach.GLCredit, amount: 250, want: 250},
+		{name: "zero-dollar prenote", transactionCode: ach.CheckingPrenoteDebit, amount: 0, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, signedAmount(tt.transactionCode, tt.amount))
+		})
+	}
+}
+
+func TestGetSummaryTable_Returns(t *testing.T) {
+	// return-WEB.ach holds a returned debit (code 26, 12354 cents) in batch 0
+	// and a returned credit (code 21, 4565 cents) in batch 1.
+	file, err := ach.ReadFile(findTestFile(t, "return-WEB.ach"))
+	require.NoError(t, err)
+
+	ts := FromFile(file)
+	require.NotNil(t, ts)
+
+	summary := ts.GetSummaryTable()
+	require.NotNil(t, summary)
+	assert.Equal(t, []string{
+		"batch_index", "entry_count", "credit_amount", "debit_amount",
+		"net_amount", "return_count", "return_net_amount",
+	}, summary.Headers)
+	require.Len(t, summary.Records, 2)
+
+	// Returned debit nets negatively
+	assert.Equal(t, []string{"0", "1", "0", "12354", "-12354", "1", "-12354"}, summary.Records[0])
+	// Returned credit nets positively
+	assert.Equal(t, []string{"1", "1", "4565", "0", "4565", "1", "4565"}, summary.Records[1])
+}
+
+func TestGetReturnsTable(t *testing.T) {
+	file, err := ach.ReadFile(findTestFile(t, "return-WEB.ach"))
+	require.NoError(t, err)
+
+	ts := FromFile(file)
+	returns := ts.GetReturnsTable()
+	require.NotNil(t, returns)
+	require.Len(t, returns.Records, 2)
+
+	headerIndex := make(map[string]int)
+	for i, h := range returns.Headers {
+		headerIndex[h] = i
+	}
+	assert.Equal(t, "26", returns.Records[0][headerIndex["transaction_code"]])
+	assert.Equal(t, "12354", returns.Records[0][headerIndex["amount"]])
+	assert.Equal(t, "-12354", returns.Records[0][headerIndex["signed_amount"]])
+	assert.Equal(t, "R01", returns.Records[0][headerIndex["return_code"]])
+	assert.Equal(t, "21", returns.Records[1][headerIndex["transaction_code"]])
+	assert.Equal(t, "4565", returns.Records[1][headerIndex["signed_amount"]])
+	assert.Equal(t, "R03", returns.Records[1][headerIndex["return_code"]])
+}
+
+func TestDerivedViews_NilTableSet(t *testing.T) {
+	var ts *TableSet
+	assert.Nil(t, ts.GetReturnsTable())
+	assert.Nil(t, ts.GetSummaryTable())
+}
+
+func TestGetSummaryTable_NoReturns(t *testing.T) {
+	ts := FromFile(createTestACHFile(t))
+
+	returns := ts.GetReturnsTable()
+	require.NotNil(t, returns)
+	assert.Empty(t, returns.Records)
+
+	summary := ts.GetSummaryTable()
+	require.Len(t, summary.Records, 1)
+	assert.Equal(t, []string{"0", "1", "0", "100000000", "-100000000", "0", "0"}, summary.Records[0])
+}
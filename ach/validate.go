@@ -0,0 +1,159 @@
+package ach
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/moov-io/ach"
+	"github.com/nao1215/fileparser"
+)
+
+// Validation issue levels for the level column of the Validate table
+const (
+	levelFileHeader = "file_header"
+	levelFile       = "file"
+	levelBatch      = "batch"
+	levelEntry      = "entry"
+	levelIATBatch   = "iat_batch"
+	levelIATEntry   = "iat_entry"
+)
+
+// validationIssues collects the rows of the Validate table.
+type validationIssues struct {
+	records [][]string
+}
+
+// add records err as an issue. batchIdx and entryIdx are -1 when the issue
+// is not tied to a batch or entry.
+func (v *validationIssues) add(level string, batchIdx, entryIdx int, err error) {
+	index := func(i int) string {
+		if i < 0 {
+			return ""
+		}
+		return strconv.Itoa(i)
+	}
+	v.records = append(v.records, []string{
+		level,
+		index(batchIdx),
+		index(entryIdx),
+		validationField(err),
+		err.Error(),
+	})
+}
+
+// validationField returns the name of the field a moov-io/ach validation
+// error refers to, or an empty string when the error does not name one.
+func validationField(err error) string {
+	var fieldErr *ach.FieldError
+	if errors.As(err, &fieldErr) {
+		return fieldErr.FieldName
+	}
+	var batchErr *ach.BatchError
+	if errors.As(err, &batchErr) {
+		return batchErr.FieldName
+	}
+	return ""
+}
+
+// Validate runs moov-io/ach validation on the file ToFile would produce and
+// returns the problems found as a table with one row per issue, so they can
+// be queried like the other tables:
+//
+//   - level: file_header, batch, entry, iat_batch, iat_entry, or file
+//   - batch_index, entry_index: the batch and entry the issue belongs to,
+//     empty when it does not belong to one
+//   - field: the name of the offending field, when moov-io/ach reports one
+//   - message: the validation error message
+//
+// Every entry is validated on its own, so all invalid entries are reported.
+// A batch is validated as a whole (control totals, trace number order, and
+// so on) only when its entries are valid, and the file as a whole only when
+// no other issue was found, so one problem is not reported several times.
+// An empty table means the file is valid.
+//
+// The returned error is non-nil only when the tables cannot be applied to
+// the original file at all, e.g. because of an out of range entry_index.
+//
+// Example:
+//
+//	issues, err := ts.Validate()
+//	if err != nil {
+//	    return err
+//	}
+//	for _, issue := range issues.Records {
+//	    log.Printf("%s batch=%s entry=%s %s: %s", issue[0], issue[1], issue[2], issue[3], issue[4])
+//	}
+func (ts *TableSet) Validate() (*fileparser.TableData, error) {
+	file, err := ts.applyModifications()
+	if err != nil {
+		return nil, err
+	}
+
+	var issues validationIssues
+	if err := file.Header.Validate(); err != nil {
+		issues.add(levelFileHeader, -1, -1, err)
+	}
+
+	for batchIdx, batch := range file.Batches {
+		valid := true
+		for entryIdx, entry := range batch.GetEntries() {
+			if err := entry.Validate(); err != nil {
+				issues.add(levelEntry, batchIdx, entryIdx, err)
+				valid = false
+			}
+		}
+		if !valid {
+			continue
+		}
+		if err := batch.Validate(); err != nil {
+			issues.add(levelBatch, batchIdx, -1, err)
+		}
+	}
+
+	for batchIdx, iatBatch := range file.IATBatches {
+		valid := true
+		for entryIdx, entry := range iatBatch.GetEntries() {
+			if err := entry.Validate(); err != nil {
+				issues.add(levelIATEntry, batchIdx, entryIdx, err)
+				valid = false
+			}
+		}
+		if !valid {
+			continue
+		}
+		if err := iatBatch.Validate(); err != nil {
+			issues.add(levelIATBatch, batchIdx, -1, err)
+		}
+	}
+
+	if len(issues.records) == 0 {
+		if err := file.Create(); err != nil {
+			issues.add(levelFile, -1, -1, err)
+		} else if err := file.Validate(); err != nil {
+			issues.add(levelFile, -1, -1, err)
+		}
+	}
+
+	records := issues.records
+	if records == nil {
+		records = [][]string{}
+	}
+
+	return &fileparser.TableData{
+		Headers: []string{
+			"level",
+			"batch_index",
+			"entry_index",
+			"field",
+			"message",
+		},
+		Records: records,
+		ColumnTypes: []fileparser.ColumnType{
+			fileparser.TypeText,    // level
+			fileparser.TypeInteger, // batch_index
+			fileparser.TypeInteger, // entry_index
+			fileparser.TypeText,    // field
+			fileparser.TypeText,    // message
+		},
+	}, nil
+}
@@ -0,0 +1,74 @@
+package ach
+
+import (
+	"testing"
+
+	"github.com/moov-io/ach"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	columnIndex := func(t *testing.T, ts *TableSet, name string) int {
+		t.Helper()
+		for i, h := range ts.Entries.Headers {
+			if h == name {
+				return i
+			}
+		}
+		t.Fatalf("column %q not found", name)
+		return -1
+	}
+
+	t.Run("returns no issues for valid files", func(t *testing.T) {
+		for _, name := range []string{"ppd-debit.ach", "cor-example.ach", "iat-credit.ach", "pos-debit.ach", "return-WEB.ach"} {
+			file, err := ach.ReadFile(findTestFile(t, name))
+			require.NoError(t, err, name)
+
+			issues, err := FromFile(file).Validate()
+			require.NoError(t, err, name)
+			assert.Equal(t, []string{"level", "batch_index", "entry_index", "field", "message"}, issues.Headers)
+			assert.Empty(t, issues.Records, name)
+		}
+	})
+
+	t.Run("reports an invalid entry field", func(t *testing.T) {
+		file, err := ach.ReadFile(findTestACHFile(t))
+		require.NoError(t, err)
+
+		ts := FromFile(file)
+		ts.Entries.Records[0][columnIndex(t, ts, "transaction_code")] = "99"
+
+		issues, err := ts.Validate()
+		require.NoError(t, err)
+		require.Len(t, issues.Records, 1)
+
+		issue := issues.Records[0]
+		assert.Equal(t, []string{levelEntry, "0", "0", "TransactionCode"}, issue[:4])
+		assert.Contains(t, issue[4], "TransactionCode")
+	})
+
+	t.Run("reports a batch control that no longer matches its entries", func(t *testing.T) {
+		file, err := ach.ReadFile(findTestACHFile(t))
+		require.NoError(t, err)
+
+		ts := FromFile(file)
+		ts.Entries.Records[0][columnIndex(t, ts, "amount")] = "12345"
+
+		issues, err := ts.Validate()
+		require.NoError(t, err)
+		require.Len(t, issues.Records, 1)
+		assert.Equal(t, []string{levelBatch, "0", ""}, issues.Records[0][:3])
+	})
+
+	t.Run("returns an error when the tables cannot be applied", func(t *testing.T) {
+		file, err := ach.ReadFile(findTestACHFile(t))
+		require.NoError(t, err)
+
+		ts := FromFile(file)
+		ts.Entries.Records[0][columnIndex(t, ts, "batch_index")] = "7"
+
+		_, err = ts.Validate()
+		require.ErrorContains(t, err, "batch_index 7 out of range")
+	})
+}
@@ -0,0 +1,47 @@
+package fileparser
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+)
+
+// blankLineFilter is a reader that drops lines consisting only of whitespace
+// from CSV or TSV input. Lines inside a quoted field are kept, so multi-line
+// values are not altered.
+type blankLineFilter struct {
+	reader  *bufio.Reader
+	pending []byte
+	quotes  quoteTracker
+	err     error
+}
+
+// newBlankLineFilter returns a reader over the content of reader, whose
+// fields are separated by delimiter, without its whitespace-only lines.
+func newBlankLineFilter(reader io.Reader, delimiter rune) io.Reader {
+	return &blankLineFilter{
+		reader: bufio.NewReader(reader),
+		quotes: quoteTracker{delimiter: byte(delimiter)},
+	}
+}
+
+// Read implements io.Reader.
+func (f *blankLineFilter) Read(p []byte) (int, error) {
+	for len(f.pending) == 0 {
+		if f.err != nil {
+			return 0, f.err
+		}
+		var line []byte
+		line, f.err = f.reader.ReadBytes('\n')
+		if !f.quotes.inQuotes && len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		for _, b := range line {
+			f.quotes.quoted(b)
+		}
+		f.pending = line
+	}
+	n := copy(p, f.pending)
+	f.pending = f.pending[n:]
+	return n, nil
+}
@@ -0,0 +1,179 @@
+package fileparser
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// CompressionInfo describes a compressed stream as reported by CompressionMetadata.
+type CompressionInfo struct {
+	// Format is the compression format: "gzip", "bzip2", "xz", "zstd",
+	// "zlib", "snappy", "s2", or "lz4". It is empty when the data does not
+	// start with a known compression header. Brotli streams have no header
+	// and are reported as uncompressed.
+	Format string
+	// OriginalName is the name of the uncompressed file stored in the header.
+	// Only gzip stores a name, and only when the writer set one.
+	OriginalName string
+	// ModTime is the modification time stored in the header, or the zero time.
+	// Only gzip stores a modification time.
+	ModTime time.Time
+}
+
+// compressionKind identifies a compression format by the name reported in
+// CompressionInfo.Format.
+type compressionKind string
+
+// Compression formats that can be detected from the leading bytes of a stream.
+const (
+	compressionGzip   compressionKind = "gzip"
+	compressionBzip2  compressionKind = "bzip2"
+	compressionXZ     compressionKind = "xz"
+	compressionZstd   compressionKind = "zstd"
+	compressionZlib   compressionKind = "zlib"
+	compressionSnappy compressionKind = "snappy"
+	compressionS2     compressionKind = "s2"
+	compressionLZ4    compressionKind = "lz4"
+)
+
+// compressionPeekSize is the number of leading bytes needed to recognize
+// every format in compressionMagics.
+const compressionPeekSize = 10
+
+// compressionMagics maps the leading bytes of compressed streams to their format.
+var compressionMagics = []struct {
+	magic []byte
+	kind  compressionKind
+}{
+	{[]byte{0x1f, 0x8b}, compressionGzip},
+	{[]byte("BZh"), compressionBzip2},
+	{[]byte{0xfd, '7', 'z', 'X', 'Z', 0x00}, compressionXZ},
+	{[]byte{0x28, 0xb5, 0x2f, 0xfd}, compressionZstd},
+	{[]byte{0xff, 0x06, 0x00, 0x00, 's', 'N', 'a', 'P', 'p', 'Y'}, compressionSnappy},
+	{[]byte{0xff, 0x06, 0x00, 0x00, 'S', '2', 's', 'T', 'w', 'O'}, compressionS2},
+	{[]byte{0x04, 0x22, 0x4d, 0x18}, compressionLZ4},
+}
+
+// compressedVariants maps each detectable compression format to the
+// compressed variant of every base file type.
+var compressedVariants = map[compressionKind]map[FileType]FileType{
+	compressionGzip:   {CSV: CSVGZ, TSV: TSVGZ, LTSV: LTSVGZ, Parquet: ParquetGZ, XLSX: XLSXGZ, XML: XMLGZ, YAML: YAMLGZ},
+	compressionBzip2:  {CSV: CSVBZ2, TSV: TSVBZ2, LTSV: LTSVBZ2, Parquet: ParquetBZ2, XLSX: XLSXBZ2, XML: XMLBZ2, YAML: YAMLBZ2},
+	compressionXZ:     {CSV: CSVXZ, TSV: TSVXZ, LTSV: LTSVXZ, Parquet: ParquetXZ, XLSX: XLSXXZ, XML: XMLXZ, YAML: YAMLXZ},
+	compressionZstd:   {CSV: CSVZSTD, TSV: TSVZSTD, LTSV: LTSVZSTD, Parquet: ParquetZSTD, XLSX: XLSXZSTD, XML: XMLZSTD, YAML: YAMLZSTD},
+	compressionZlib:   {CSV: CSVZLIB, TSV: TSVZLIB, LTSV: LTSVZLIB, Parquet: ParquetZLIB, XLSX: XLSXZLIB, XML: XMLZLIB, YAML: YAMLZLIB},
+	compressionSnappy: {CSV: CSVSNAPPY, TSV: TSVSNAPPY, LTSV: LTSVSNAPPY, Parquet: ParquetSNAPPY, XLSX: XLSXSNAPPY, XML: XMLSNAPPY, YAML: YAMLSNAPPY},
+	compressionS2:     {CSV: CSVS2, TSV: TSVS2, LTSV: LTSVS2, Parquet: ParquetS2, XLSX: XLSXS2, XML: XMLS2, YAML: YAMLS2},
+	compressionLZ4:    {CSV: CSVLZ4, TSV: TSVLZ4, LTSV: LTSVLZ4, Parquet: ParquetLZ4, XLSX: XLSXLZ4, XML: XMLLZ4, YAML: YAMLLZ4},
+}
+
+// detectCompression reports the compression format of a stream from its
+// leading bytes. peek should hold at least compressionPeekSize bytes unless
+// the stream is shorter. Brotli streams have no header and are never detected.
+func detectCompression(peek []byte) (compressionKind, bool) {
+	for _, m := range compressionMagics {
+		if bytes.HasPrefix(peek, m.magic) {
+			return m.kind, true
+		}
+	}
+	if isZlibHeader(peek) {
+		return compressionZlib, true
+	}
+	return "", false
+}
+
+// sniffCompression peeks at the start of reader and returns fileType adjusted
+// to the compression actually found there, along with a reader that still
+// yields the peeked bytes. Uncompressed input gives the base file type.
+// Brotli cannot be detected, so a brotli fileType is kept as given when no
+// other format is found.
+func sniffCompression(reader io.Reader, fileType FileType) (io.Reader, FileType, error) {
+	br := bufio.NewReader(reader)
+	peek, err := br.Peek(compressionPeekSize)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, Unsupported, fmt.Errorf("failed to read compression header: %w", err)
+	}
+
+	baseType := BaseFileType(fileType)
+	if kind, ok := detectCompression(peek); ok {
+		if variant, ok := compressedVariants[kind][baseType]; ok {
+			return br, variant, nil
+		}
+	}
+	if isBrotli(fileType) {
+		return br, fileType, nil
+	}
+	return br, baseType, nil
+}
+
+// isBrotli reports whether fileType is brotli-compressed.
+func isBrotli(fileType FileType) bool {
+	switch fileType {
+	case CSVBR, TSVBR, LTSVBR, ParquetBR, XLSXBR, XMLBR, YAMLBR:
+		return true
+	default:
+		return false
+	}
+}
+
+// CompressionMetadata inspects the header of a compressed stream and reports
+// its format along with the original file name and modification time stored
+// in it, if any. Only the header is read; the data is not decompressed.
+//
+// Example:
+//
+//	f, _ := os.Open("export.csv.gz")
+//	info, err := fileparser.CompressionMetadata(f)
+//	fmt.Println(info.OriginalName) // "export.csv"
+func CompressionMetadata(r io.Reader) (CompressionInfo, error) {
+	if r == nil {
+		return CompressionInfo{}, errors.New("reader cannot be nil")
+	}
+
+	br := bufio.NewReader(r)
+	head, err := br.Peek(compressionPeekSize)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return CompressionInfo{}, fmt.Errorf("failed to read compression header: %w", err)
+	}
+
+	kind, ok := detectCompression(head)
+	if !ok {
+		return CompressionInfo{}, nil
+	}
+	if kind != compressionGzip {
+		return CompressionInfo{Format: string(kind)}, nil
+	}
+
+	gzReader, err := gzip.NewReader(br)
+	if err != nil {
+		return CompressionInfo{}, fmt.Errorf("failed to read gzip header: %w", err)
+	}
+	defer gzReader.Close()
+	return CompressionInfo{
+		Format:       string(kind),
+		OriginalName: gzReader.Name,
+		ModTime:      gzReader.ModTime,
+	}, nil
+}
+
+// isZlibHeader reports whether head starts with a zlib header as written by
+// common encoders: deflate compression with a 32 KiB window, no preset
+// dictionary, and a valid check value. Plain text can pass the header check,
+// e.g. "x^", so the rest of head must also inflate without error.
+func isZlibHeader(head []byte) bool {
+	if len(head) < 2 {
+		return false
+	}
+	cmf, flg := head[0], head[1]
+	if cmf != 0x78 || flg&0x20 != 0 || (uint16(cmf)<<8|uint16(flg))%31 != 0 {
+		return false
+	}
+	_, err := io.Copy(io.Discard, flate.NewReader(bytes.NewReader(head[2:])))
+	return err == nil || errors.Is(err, io.ErrUnexpectedEOF)
+}
@@ -0,0 +1,38 @@
+package fileparser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectCompression(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		peek   []byte
+		want   compressionKind
+		wantOK bool
+	}{
+		{name: "gzip", peek: []byte{0x1f, 0x8b, 0x08, 0x00}, want: compressionGzip, wantOK: true},
+		{name: "bzip2", peek: []byte("BZh91AY&SY"), want: compressionBzip2, wantOK: true},
+		{name: "zstd", peek: []byte{0x28, 0xb5, 0x2f, 0xfd, 0x04}, want: compressionZstd, wantOK: true},
+		{name: "zlib", peek: []byte{0x78, 0x9c}, want: compressionZlib, wantOK: true},
+		{name: "plain text", peek: []byte("id,name\n1,"), wantOK: false},
+		{name: "text with a zlib check value", peek: []byte("H,value\n1,"), wantOK: false},
+		{name: "text with a zlib check value and window", peek: []byte("80,90\n1,2\n"), wantOK: false},
+		{name: "text starting with x^", peek: []byte("x^2,y\n1,2\n"), wantOK: false},
+		{name: "empty", peek: nil, wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := detectCompression(tt.peek)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package fileparser
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// numericTolerance is the relative difference up to which AssertSameData
+// treats two numbers as equal. Spreadsheet applications keep 15 significant
+// digits, so values read from XLSX can differ from CSV in the last digits.
+const numericTolerance = 1e-12
+
+// AssertSameData reports whether a and b hold the same logical data, e.g. the
+// same dataset parsed from CSV, Parquet, and XLSX. It is meant for tests that
+// check parsers agree with each other. It returns nil if the tables match and
+// otherwise an error describing the first difference.
+//
+// Headers must be identical and the records must match row by row. Column
+// types must be compatible: equal, or TypeInteger and TypeReal. Values are
+// compared according to the column type, so formatting differences are
+// tolerated: "1.50" matches "1.5" in numeric columns, "true" matches "TRUE"
+// in boolean columns, and datetime values match if they denote the same
+// instant. Other values must be equal after trimming surrounding whitespace.
+//
+// Example:
+//
+//	fromCSV, _ := fileparser.Parse(csvFile, fileparser.CSV)
+//	fromParquet, _ := fileparser.Parse(parquetFile, fileparser.Parquet)
+//	if err := fileparser.AssertSameData(fromCSV, fromParquet); err != nil {
+//	    t.Error(err)
+//	}
+func AssertSameData(a, b *TableData) error {
+	if a == nil || b == nil {
+		return errors.New("tables to compare cannot be nil")
+	}
+
+	if len(a.Headers) != len(b.Headers) {
+		return fmt.Errorf("header count differs: %d != %d", len(a.Headers), len(b.Headers))
+	}
+	for i, header := range a.Headers {
+		if header != b.Headers[i] {
+			return fmt.Errorf("header %d differs: %q != %q", i+1, header, b.Headers[i])
+		}
+	}
+
+	columnTypes := make([]ColumnType, len(a.Headers))
+	for i, header := range a.Headers {
+		typeA, typeB := columnTypeAt(a.ColumnTypes, i), columnTypeAt(b.ColumnTypes, i)
+		if typeA != typeB && widenColumnType(typeA, typeB) != TypeReal {
+			return fmt.Errorf("column %q has incompatible types: %s != %s", header, typeA, typeB)
+		}
+		columnTypes[i] = widenColumnType(typeA, typeB)
+	}
+
+	if len(a.Records) != len(b.Records) {
+		return fmt.Errorf("record count differs: %d != %d", len(a.Records), len(b.Records))
+	}
+	for i := range a.Records {
+		for j, header := range a.Headers {
+			valueA, valueB := cellAt(a.Records[i], j), cellAt(b.Records[i], j)
+			if !sameValue(valueA, valueB, columnTypes[j]) {
+				return fmt.Errorf("record %d, column %q differs: %q != %q", i+1, header, valueA, valueB)
+			}
+		}
+	}
+
+	return nil
+}
+
+// sameValue reports whether a and b denote the same value of type colType.
+func sameValue(a, b string, colType ColumnType) bool {
+	a, b = strings.TrimSpace(a), strings.TrimSpace(b)
+	if a == b {
+		return true
+	}
+
+	switch colType {
+	case TypeInteger, TypeReal:
+		x, errA := strconv.ParseFloat(a, 64)
+		y, errB := strconv.ParseFloat(b, 64)
+		if errA != nil || errB != nil {
+			return false
+		}
+		return x == y || math.Abs(x-y) <= numericTolerance*max(math.Abs(x), math.Abs(y))
+	case TypeDatetime:
+		x, okA := parseDatetime(a)
+		y, okB := parseDatetime(b)
+		return okA && okB && x.Equal(y)
+	case TypeBoolean:
+		x, errA := strconv.ParseBool(a)
+		y, errB := strconv.ParseBool(b)
+		return errA == nil && errB == nil && x == y
+	default:
+		return false
+	}
+}
@@ -0,0 +1,120 @@
+package fileparser
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/apache/arrow/go/v18/arrow"
+	"github.com/apache/arrow/go/v18/arrow/array"
+	"github.com/apache/arrow/go/v18/arrow/memory"
+	"github.com/apache/arrow/go/v18/parquet"
+	"github.com/apache/arrow/go/v18/parquet/pqarrow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestAssertSameData(t *testing.T) {
+	t.Parallel()
+
+	t.Run("CSV, Parquet, and XLSX parses of the same data agree", func(t *testing.T) {
+		t.Parallel()
+
+		fromCSV, err := ParseWithOptions(strings.NewReader("id,name,amount,active\n1,Laptop,1000000,true\n2,Mouse,1.50,false\n3,Cable,0.1,true"), CSV,
+			ParseOptions{Inference: InferenceConfig{InferBooleans: true}})
+		require.NoError(t, err)
+
+		pool := memory.NewGoAllocator()
+		schema := arrow.NewSchema([]arrow.Field{
+			{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+			{Name: "name", Type: arrow.BinaryTypes.String},
+			{Name: "amount", Type: arrow.PrimitiveTypes.Float64},
+			{Name: "active", Type: arrow.FixedWidthTypes.Boolean},
+		}, nil)
+		ids := array.NewInt64Builder(pool)
+		defer ids.Release()
+		ids.AppendValues([]int64{1, 2, 3}, nil)
+		names := array.NewStringBuilder(pool)
+		defer names.Release()
+		names.AppendValues([]string{"Laptop", "Mouse", "Cable"}, nil)
+		amounts := array.NewFloat64Builder(pool)
+		defer amounts.Release()
+		amounts.AppendValues([]float64{1000000, 1.5, 0.1}, nil)
+		actives := array.NewBooleanBuilder(pool)
+		defer actives.Release()
+		actives.AppendValues([]bool{true, false, true}, nil)
+		columns := []arrow.Array{ids.NewArray(), names.NewArray(), amounts.NewArray(), actives.NewArray()}
+		for _, column := range columns {
+			defer column.Release()
+		}
+		record := array.NewRecord(schema, columns, 3)
+		defer record.Release()
+		table := array.NewTableFromRecords(schema, []arrow.Record{record})
+		defer table.Release()
+		var parquetBuf bytes.Buffer
+		require.NoError(t, pqarrow.WriteTable(table, &parquetBuf, 1024, parquet.NewWriterProperties(), pqarrow.DefaultWriterProps()))
+
+		fromParquet, err := Parse(&parquetBuf, Parquet)
+		require.NoError(t, err)
+
+		f := excelize.NewFile()
+		defer f.Close()
+		require.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]any{"id", "name", "amount", "active"}))
+		require.NoError(t, f.SetSheetRow("Sheet1", "A2", &[]any{1, "Laptop", 1000000.0, true}))
+		require.NoError(t, f.SetSheetRow("Sheet1", "A3", &[]any{2, "Mouse", 1.5, false}))
+		require.NoError(t, f.SetSheetRow("Sheet1", "A4", &[]any{3, "Cable", 0.1, true}))
+		var xlsxBuf bytes.Buffer
+		require.NoError(t, f.Write(&xlsxBuf))
+
+		fromXLSX, err := Parse(&xlsxBuf, XLSX)
+		require.NoError(t, err)
+
+		assert.NoError(t, AssertSameData(fromCSV, fromParquet))
+		assert.NoError(t, AssertSameData(fromCSV, fromXLSX))
+		assert.NoError(t, AssertSameData(fromParquet, fromXLSX))
+	})
+
+	t.Run("tolerates numeric, boolean, and datetime formatting", func(t *testing.T) {
+		t.Parallel()
+
+		a := &TableData{
+			Headers:     []string{"n", "b", "d"},
+			Records:     [][]string{{"1.50", "TRUE", "2024-01-15"}},
+			ColumnTypes: []ColumnType{TypeReal, TypeBoolean, TypeDatetime},
+		}
+		b := &TableData{
+			Headers:     []string{"n", "b", "d"},
+			Records:     [][]string{{"1.5", "true", "2024-01-15T00:00:00Z"}},
+			ColumnTypes: []ColumnType{TypeInteger, TypeBoolean, TypeDatetime},
+		}
+
+		assert.NoError(t, AssertSameData(a, b))
+	})
+
+	t.Run("reports the first differing value", func(t *testing.T) {
+		t.Parallel()
+
+		a := &TableData{Headers: []string{"id", "name"}, Records: [][]string{{"1", "a"}, {"2", "b"}}}
+		b := &TableData{Headers: []string{"id", "name"}, Records: [][]string{{"1", "a"}, {"2", "c"}}}
+
+		err := AssertSameData(a, b)
+
+		require.Error(t, err)
+		assert.Equal(t, `record 2, column "name" differs: "b" != "c"`, err.Error())
+	})
+
+	t.Run("reports header and type differences", func(t *testing.T) {
+		t.Parallel()
+
+		a := &TableData{Headers: []string{"id"}, ColumnTypes: []ColumnType{TypeInteger}}
+
+		err := AssertSameData(a, &TableData{Headers: []string{"key"}, ColumnTypes: []ColumnType{TypeInteger}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "header 1 differs")
+
+		err = AssertSameData(a, &TableData{Headers: []string{"id"}, ColumnTypes: []ColumnType{TypeText}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "incompatible types: INTEGER != TEXT")
+	})
+}
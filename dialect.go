@@ -0,0 +1,175 @@
+package fileparser
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// CSVDialect selects how strictly CSV and TSV input follows RFC 4180, in
+// particular how quotes and line endings are handled.
+type CSVDialect int
+
+const (
+	// CSVDialectDefault parses like encoding/csv: records end with LF or CRLF,
+	// a carriage return elsewhere is kept as data, and quotes must be well
+	// formed. This is the default.
+	CSVDialectDefault CSVDialect = iota
+	// CSVDialectRFC4180 requires records to end with CRLF, as RFC 4180 does.
+	// A carriage return or line feed outside a quoted field that is not part
+	// of a CRLF pair is an error, as are malformed quotes. The last record
+	// may end without a line break.
+	CSVDialectRFC4180
+	// CSVDialectExcel tolerates the quirks of files saved by spreadsheet
+	// applications: CR, LF, and CRLF all end a record, and a quote inside an
+	// unquoted field or a stray quote inside a quoted field is kept as data.
+	CSVDialectExcel
+	// CSVDialectUnix expects records to end with LF and removes every carriage
+	// return outside quoted fields, e.g. stray ones left by editors on
+	// other platforms.
+	CSVDialectUnix
+)
+
+// String returns the name of the dialect.
+func (d CSVDialect) String() string {
+	switch d {
+	case CSVDialectDefault:
+		return "default"
+	case CSVDialectRFC4180:
+		return "rfc4180"
+	case CSVDialectExcel:
+		return "excel"
+	case CSVDialectUnix:
+		return "unix"
+	default:
+		return "unknown"
+	}
+}
+
+// validate returns an error for an unknown dialect.
+func (d CSVDialect) validate() error {
+	if d < CSVDialectDefault || d > CSVDialectUnix {
+		return fmt.Errorf("unknown CSV dialect %d", d)
+	}
+	return nil
+}
+
+// dialectReader is a reader that normalizes or checks the line endings of
+// CSV or TSV input according to a CSVDialect before encoding/csv reads it.
+// Carriage returns and line feeds inside quoted fields are left alone.
+type dialectReader struct {
+	reader  *bufio.Reader
+	dialect CSVDialect
+	quotes  quoteTracker
+	pending []byte
+	line    int
+	err     error
+}
+
+// newDialectReader returns a reader over the content of reader, whose fields
+// are separated by delimiter, with its line endings handled according to
+// dialect.
+func newDialectReader(reader io.Reader, delimiter rune, dialect CSVDialect) io.Reader {
+	return &dialectReader{
+		reader:  bufio.NewReader(reader),
+		dialect: dialect,
+		quotes:  quoteTracker{delimiter: byte(delimiter)},
+	}
+}
+
+// Read implements io.Reader.
+func (r *dialectReader) Read(p []byte) (int, error) {
+	for len(r.pending) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		var line []byte
+		line, r.err = r.reader.ReadBytes('\n')
+		r.line++
+		var err error
+		r.pending, err = r.convertLine(line)
+		if err != nil {
+			r.pending = nil
+			r.err = err
+		}
+	}
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+// convertLine applies the dialect to one line of input, including its
+// trailing line feed if any.
+func (r *dialectReader) convertLine(line []byte) ([]byte, error) {
+	out := line[:0]
+	for i, b := range line {
+		if r.quotes.quoted(b) {
+			out = append(out, b)
+			continue
+		}
+
+		switch b {
+		case '\r':
+			crlf := i == len(line)-2 && line[i+1] == '\n'
+			switch {
+			case r.dialect == CSVDialectUnix:
+				continue
+			case crlf:
+			case r.dialect == CSVDialectRFC4180:
+				return nil, &lineError{line: r.line, err: fmt.Errorf("line %d: carriage return without line feed is not allowed by RFC 4180", r.line)}
+			case r.dialect == CSVDialectExcel:
+				b = '\n'
+			}
+		case '\n':
+			if r.dialect == CSVDialectRFC4180 && (i == 0 || line[i-1] != '\r') {
+				return nil, &lineError{line: r.line, err: fmt.Errorf("line %d: line feed without carriage return is not allowed by RFC 4180", r.line)}
+			}
+		}
+		out = append(out, b)
+	}
+	return out, nil
+}
+
+// quoteTracker follows the quoted fields of CSV or TSV input byte by byte.
+// Only a quote at the start of a field opens a quoted field, so a stray quote
+// inside an unquoted field such as 5" wide is data. Inside a quoted field a
+// doubled quote is an escaped quote, and a quote closes the field only when a
+// delimiter or line break follows; any other quote is kept as data, as with
+// the LazyQuotes option of encoding/csv. Strict dialects leave such quotes
+// for encoding/csv to reject.
+type quoteTracker struct {
+	delimiter  byte
+	inQuotes   bool
+	afterQuote bool
+	midField   bool
+}
+
+// quoted reports whether b is part of a quoted field, including its quotes,
+// and advances the state past b.
+func (q *quoteTracker) quoted(b byte) bool {
+	if q.inQuotes {
+		if !q.afterQuote {
+			q.afterQuote = b == '"'
+			return true
+		}
+		q.afterQuote = false
+		if b != q.delimiter && b != '\r' && b != '\n' {
+			return true
+		}
+		q.inQuotes = false
+	}
+
+	switch b {
+	case q.delimiter, '\r', '\n':
+		q.midField = false
+		return false
+	case '"':
+		if !q.midField {
+			q.inQuotes = true
+			q.midField = true
+			return true
+		}
+	}
+	q.midField = true
+	return false
+}
@@ -0,0 +1,100 @@
+package fileparser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWithOptions_CSVDialect(t *testing.T) {
+	t.Parallel()
+
+	parse := func(t *testing.T, input string, dialect CSVDialect) (*TableData, error) {
+		t.Helper()
+		return ParseWithOptions(strings.NewReader(input), CSV, ParseOptions{CSVDialect: dialect})
+	}
+
+	t.Run("RFC 4180 accepts CRLF line endings and quoted line breaks", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := parse(t, "id,note\r\n1,\"a\nb\"\r\n2,\"c\rd\"", CSVDialectRFC4180)
+
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{{"1", "a\nb"}, {"2", "c\rd"}}, result.Records)
+	})
+
+	t.Run("RFC 4180 rejects lone carriage returns and line feeds", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := parse(t, "id,note\r\n1,a\rb\r\n", CSVDialectRFC4180)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "line 2: carriage return without line feed")
+
+		_, err = parse(t, "id,note\n1,a\n", CSVDialectRFC4180)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "line 1: line feed without carriage return")
+
+		var parseErr *ParseError
+		require.ErrorAs(t, err, &parseErr)
+		assert.Equal(t, 1, parseErr.Line)
+	})
+
+	t.Run("RFC 4180 rejects bare quotes", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := parse(t, "id,size\r\n1,5\" screen\r\n", CSVDialectRFC4180)
+
+		require.Error(t, err)
+	})
+
+	t.Run("Excel treats lone carriage returns as line endings and tolerates bare quotes", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := parse(t, "id,size\r1,big\r\n2,5\" wide\n", CSVDialectExcel)
+
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{{"1", "big"}, {"2", "5\" wide"}}, result.Records)
+	})
+
+	t.Run("Excel ends records at carriage returns after a stray quote", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := parse(t, "id,size,note\r1,5\" wide,a\r2,\"12\" \"\"x\"\"\",\"b\rc\"\r3,big,d\r", CSVDialectExcel)
+
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{
+			{"1", "5\" wide", "a"},
+			{"2", "12\" \"x\"", "b\rc"},
+			{"3", "big", "d"},
+		}, result.Records)
+	})
+
+	t.Run("Unix removes carriage returns outside quoted fields", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := parse(t, "id,note\n1,a\rb\r\n2,\"c\rd\"\n", CSVDialectUnix)
+
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{{"1", "ab"}, {"2", "c\rd"}}, result.Records)
+	})
+
+	t.Run("default keeps lone carriage returns as data", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := parse(t, "id,note\n1,a\rb\n", CSVDialectDefault)
+
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{{"1", "a\rb"}}, result.Records)
+	})
+
+	t.Run("returns error for an unknown dialect", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := parse(t, "id\n1\n", CSVDialect(99))
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unknown CSV dialect 99")
+	})
+}
@@ -0,0 +1,111 @@
+package fileparser
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// Byte order marks recognized at the start of text input.
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// newBOMAwareReader inspects the leading byte order mark of text input.
+// A UTF-8 BOM is discarded, and UTF-16 LE/BE input (detected by its BOM) is
+// decoded to UTF-8. Input without a BOM is returned unchanged.
+func newBOMAwareReader(reader io.Reader) io.Reader {
+	bufReader := bufio.NewReader(reader)
+	peek, err := bufReader.Peek(len(bomUTF8))
+	if err != nil && len(peek) == 0 {
+		// Empty or unreadable input; subsequent reads report the error
+		return bufReader
+	}
+
+	switch {
+	case bytes.HasPrefix(peek, bomUTF8):
+		bufReader.Discard(len(bomUTF8)) //nolint:errcheck // cannot fail after a successful Peek
+		return bufReader
+	case bytes.HasPrefix(peek, bomUTF16LE):
+		decoder := unicode.UTF16(unicode.LittleEndian, unicode.ExpectBOM).NewDecoder()
+		return transform.NewReader(bufReader, decoder)
+	case bytes.HasPrefix(peek, bomUTF16BE):
+		decoder := unicode.UTF16(unicode.BigEndian, unicode.ExpectBOM).NewDecoder()
+		return transform.NewReader(bufReader, decoder)
+	default:
+		return bufReader
+	}
+}
+
+// newDecodingReader wraps reader with a decoder that converts text in the named
+// character encoding (e.g. "shift_jis", "euc-jp", "windows-1252") to UTF-8.
+// Encoding names follow the WHATWG Encoding Standard labels.
+func newDecodingReader(reader io.Reader, name string) (io.Reader, error) {
+	enc, err := htmlindex.Get(strings.TrimSpace(name))
+	if err != nil {
+		return nil, fmt.Errorf("unsupported encoding %q: %w", name, err)
+	}
+	return transform.NewReader(reader, enc.NewDecoder()), nil
+}
+
+// textSniffSize is the number of leading bytes inspected by checkBinaryInput.
+const textSniffSize = 1024
+
+// checkBinaryInput returns an error wrapping ErrNotBinaryFormat when data does
+// not start with magic and its leading bytes look like text, which usually
+// means a CSV/TSV/LTSV file was passed with a binary format's extension.
+func checkBinaryInput(data, magic []byte, formatName string) error {
+	if bytes.HasPrefix(data, magic) || !looksLikeText(data) {
+		return nil
+	}
+	return fmt.Errorf("%w: data does not look like %s; %s",
+		ErrNotBinaryFormat, formatName, suggestTextFormat(data))
+}
+
+// looksLikeText reports whether the leading bytes of data are printable
+// UTF-8 text (a UTF-8 BOM is allowed). Empty data is not considered text.
+func looksLikeText(data []byte) bool {
+	data = bytes.TrimPrefix(data, bomUTF8)
+	if len(data) == 0 {
+		return false
+	}
+
+	sample := data[:min(len(data), textSniffSize)]
+	for len(sample) > 0 {
+		r, size := utf8.DecodeRune(sample)
+		if r == utf8.RuneError && size <= 1 {
+			// A multi-byte character cut off by the sample size is still text
+			return len(data) > textSniffSize && !utf8.FullRune(sample)
+		}
+		if r != '\t' && r != '\n' && r != '\r' && !strconv.IsPrint(r) {
+			return false
+		}
+		sample = sample[size:]
+	}
+	return true
+}
+
+// suggestTextFormat guesses the text format of data from its first line.
+func suggestTextFormat(data []byte) string {
+	firstLine, _, _ := bytes.Cut(bytes.TrimPrefix(data, bomUTF8), []byte("\n"))
+	switch {
+	case bytes.Contains(firstLine, []byte("\t")) && bytes.Contains(firstLine, []byte(":")):
+		return "it may be LTSV or TSV"
+	case bytes.Contains(firstLine, []byte("\t")):
+		return "it may be TSV"
+	case bytes.Contains(firstLine, []byte(",")):
+		return "it may be CSV"
+	default:
+		return "it may be CSV, TSV, or LTSV"
+	}
+}
@@ -0,0 +1,178 @@
+package fileparser
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding/japanese"
+)
+
+// encodeUTF16 encodes s as UTF-16 with a leading byte order mark.
+func encodeUTF16(t *testing.T, s string, bigEndian bool) []byte {
+	t.Helper()
+
+	units := utf16.Encode([]rune("\ufeff" + s))
+	buf := make([]byte, 0, len(units)*2)
+	for _, u := range units {
+		if bigEndian {
+			buf = append(buf, byte(u>>8), byte(u))
+		} else {
+			buf = append(buf, byte(u), byte(u>>8))
+		}
+	}
+	return buf
+}
+
+func TestParse_ByteOrderMark(t *testing.T) {
+	t.Parallel()
+
+	t.Run("strips UTF-8 BOM from CSV header", func(t *testing.T) {
+		t.Parallel()
+
+		input := "\ufeffname,age\nAlice,30\n"
+
+		result, err := Parse(strings.NewReader(input), CSV)
+
+		require.NoError(t, err)
+		assert.Equal(t, "name", result.Headers[0])
+		assert.Equal(t, []string{"Alice", "30"}, result.Records[0])
+	})
+
+	t.Run("strips UTF-8 BOM from LTSV label", func(t *testing.T) {
+		t.Parallel()
+
+		input := "\ufeffhost:a\tstatus:200\n"
+
+		result, err := Parse(strings.NewReader(input), LTSV)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"host", "status"}, result.Headers)
+	})
+
+	t.Run("strips UTF-8 BOM when streaming", func(t *testing.T) {
+		t.Parallel()
+
+		input := "\ufeffname\nAlice\n"
+
+		var headers []string
+		err := ParseStream(strings.NewReader(input), CSV, func(_, h []string) error {
+			headers = h
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"name"}, headers)
+	})
+
+	t.Run("decodes UTF-16 LE CSV", func(t *testing.T) {
+		t.Parallel()
+
+		data := encodeUTF16(t, "name,city\nAlice,東京\n", false)
+
+		result, err := Parse(bytes.NewReader(data), CSV)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"name", "city"}, result.Headers)
+		assert.Equal(t, []string{"Alice", "東京"}, result.Records[0])
+	})
+
+	t.Run("decodes UTF-16 BE TSV", func(t *testing.T) {
+		t.Parallel()
+
+		data := encodeUTF16(t, "id\tname\n1\tBob\n", true)
+
+		result, err := Parse(bytes.NewReader(data), TSV)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"id", "name"}, result.Headers)
+		assert.Equal(t, []string{"1", "Bob"}, result.Records[0])
+	})
+
+	t.Run("leaves input without BOM unchanged", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := Parse(strings.NewReader("a\n1\n"), CSV)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a"}, result.Headers)
+	})
+}
+
+func TestParseWithOptions_Encoding(t *testing.T) {
+	t.Parallel()
+
+	t.Run("decodes Shift-JIS CSV", func(t *testing.T) {
+		t.Parallel()
+
+		data, err := japanese.ShiftJIS.NewEncoder().Bytes([]byte("名前,都市\n山田,東京\n"))
+		require.NoError(t, err)
+
+		result, err := ParseWithOptions(bytes.NewReader(data), CSV, ParseOptions{Encoding: "shift_jis"})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"名前", "都市"}, result.Headers)
+		assert.Equal(t, []string{"山田", "東京"}, result.Records[0])
+	})
+
+	t.Run("decodes EUC-JP LTSV", func(t *testing.T) {
+		t.Parallel()
+
+		data, err := japanese.EUCJP.NewEncoder().Bytes([]byte("name:鈴木\tcity:大阪\n"))
+		require.NoError(t, err)
+
+		result, err := ParseWithOptions(bytes.NewReader(data), LTSV, ParseOptions{Encoding: "euc-jp"})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"鈴木", "大阪"}, result.Records[0])
+	})
+
+	t.Run("decodes windows-1252 CSV", func(t *testing.T) {
+		t.Parallel()
+
+		data := []byte("name\ncaf\xe9\n")
+
+		result, err := ParseWithOptions(bytes.NewReader(data), CSV, ParseOptions{Encoding: "windows-1252"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "café", result.Records[0][0])
+	})
+
+	t.Run("returns error for unknown encoding", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParseWithOptions(strings.NewReader("a\n1\n"), CSV, ParseOptions{Encoding: "no-such-encoding"})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unsupported encoding")
+	})
+}
+
+func TestLooksLikeText(t *testing.T) {
+	t.Parallel()
+
+	long := strings.Repeat("a", textSniffSize-1) + "東京"
+
+	tests := []struct {
+		name string
+		data []byte
+		want bool
+	}{
+		{name: "ASCII CSV", data: []byte("id,name\n1,Alice\n"), want: true},
+		{name: "UTF-8 with BOM", data: []byte("\ufeffname\n東京\n"), want: true},
+		{name: "multi-byte character cut by sample size", data: []byte(long), want: true},
+		{name: "NUL byte", data: []byte("PAR1\x00\x00"), want: false},
+		{name: "invalid UTF-8", data: []byte{'a', 0xff, 'b'}, want: false},
+		{name: "empty", data: nil, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, looksLikeText(tt.data))
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package fileparser
+
+import (
+	"compress/bzip2"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// ErrNotBinaryFormat is returned when text data is passed to a parser for a
+// binary format such as Parquet or XLSX, typically because a text file was
+// given the wrong extension. The error message suggests a text format to use
+// instead.
+var ErrNotBinaryFormat = errors.New("input is text, not a binary format")
+
+// Sentinel errors for common parse failures, for use with errors.Is. The
+// returned errors keep their descriptive messages, e.g. "empty CSV data"
+// matches ErrEmptyData.
+var (
+	// ErrEmptyData is returned when the input holds no header or no records,
+	// e.g. an empty CSV file, a Parquet file without rows, or an empty sheet.
+	ErrEmptyData = errors.New("empty data")
+	// ErrUnsupportedType is returned for a file type that cannot be parsed,
+	// or streamed by ParseStream.
+	ErrUnsupportedType = errors.New("unsupported file type")
+	// ErrDuplicateColumn is returned when two columns have the same name.
+	ErrDuplicateColumn = errors.New("duplicate column name")
+	// ErrDecompression is returned when compressed input is corrupt or
+	// truncated, or is not in the compression format of the file type.
+	ErrDecompression = errors.New("failed to decompress")
+	// ErrEncrypted is returned for a password-protected XLSX workbook when
+	// ParseOptions.Password is empty or wrong, so callers can prompt for one.
+	ErrEncrypted = errors.New("XLSX workbook is encrypted")
+)
+
+// ParseError describes a failure of Parse or ParseWithOptions. Its message is
+// that of Err, and errors.Is and errors.As see through it to Err and to the
+// sentinel errors above.
+//
+// Example:
+//
+//	var parseErr *fileparser.ParseError
+//	if errors.As(err, &parseErr) && parseErr.Line > 0 {
+//	    log.Printf("%s input is malformed at line %d", parseErr.FileType, parseErr.Line)
+//	}
+type ParseError struct {
+	// FileType is the file type the input was parsed as.
+	FileType FileType
+	// Line is the 1-based line of the input where the failure was found,
+	// or 0 if it is not known. It is set for malformed CSV and TSV records
+	// and for line endings rejected by a CSVDialect.
+	Line int
+	// Err is the underlying error.
+	Err error
+}
+
+// Error implements error.
+func (e *ParseError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// newParseError wraps err, returned while parsing input of fileType, in a
+// ParseError. Corrupt compressed input is marked with ErrDecompression.
+func newParseError(fileType FileType, err error) error {
+	var parseErr *ParseError
+	if errors.As(err, &parseErr) {
+		return err
+	}
+	if !errors.Is(err, ErrDecompression) && isCorruptCompression(fileType, err) {
+		err = withKind(err, ErrDecompression)
+	}
+
+	parseErr = &ParseError{FileType: fileType, Err: err}
+	var lineErr *lineError
+	var csvErr *csv.ParseError
+	switch {
+	case errors.As(err, &lineErr):
+		parseErr.Line = lineErr.line
+	case errors.As(err, &csvErr):
+		parseErr.Line = csvErr.Line
+	}
+	return parseErr
+}
+
+// lineError is an error found at a 1-based line of the input.
+type lineError struct {
+	line int
+	err  error
+}
+
+// Error implements error.
+func (e *lineError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *lineError) Unwrap() error {
+	return e.err
+}
+
+// delimitedReadError describes err, returned by the CSV reader of
+// fileTypeName input, with the line where a malformed record was found,
+// e.g. "CSV parse error at line 10423: wrong number of fields".
+func delimitedReadError(fileTypeName string, err error) error {
+	var csvErr *csv.ParseError
+	if errors.As(err, &csvErr) {
+		err = &lineError{
+			line: csvErr.Line,
+			err:  fmt.Errorf("%s parse error at line %d: %w", fileTypeName, csvErr.Line, csvErr.Err),
+		}
+	}
+	return fmt.Errorf("failed to read %s: %w", fileTypeName, err)
+}
+
+// isCorruptCompression reports whether err, returned while reading input of
+// fileType, was caused by corrupt or truncated compressed data.
+func isCorruptCompression(fileType FileType, err error) bool {
+	if !IsCompressed(fileType) {
+		return false
+	}
+	var flateErr flate.CorruptInputError
+	var bzip2Err bzip2.StructuralError
+	return errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, gzip.ErrChecksum) || errors.Is(err, gzip.ErrHeader) ||
+		errors.Is(err, zlib.ErrChecksum) || errors.Is(err, zlib.ErrHeader) ||
+		errors.Is(err, zstd.ErrCRCMismatch) || errors.Is(err, zstd.ErrMagicMismatch) ||
+		errors.As(err, &flateErr) || errors.As(err, &bzip2Err)
+}
+
+// kindError is an error that keeps the message of err while also matching
+// kind with errors.Is.
+type kindError struct {
+	err  error
+	kind error
+}
+
+// withKind returns err marked with the sentinel error kind.
+func withKind(err, kind error) error {
+	return &kindError{err: err, kind: kind}
+}
+
+// Error implements error.
+func (e *kindError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the marked error and its kind.
+func (e *kindError) Unwrap() []error {
+	return []error{e.err, e.kind}
+}
@@ -0,0 +1,86 @@
+package fileparser
+
+import (
+	"bytes"
+	"compress/gzip"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse_ErrorTypes(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty input matches ErrEmptyData and keeps its message", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Parse(strings.NewReader(""), TSV)
+
+		require.ErrorIs(t, err, ErrEmptyData)
+		assert.Equal(t, "empty TSV data", err.Error())
+
+		var parseErr *ParseError
+		require.ErrorAs(t, err, &parseErr)
+		assert.Equal(t, TSV, parseErr.FileType)
+		assert.Zero(t, parseErr.Line)
+	})
+
+	t.Run("empty formats match ErrEmptyData", func(t *testing.T) {
+		t.Parallel()
+
+		for _, fileType := range []FileType{CSV, LTSV, Parquet, XLSX, XML, YAML} {
+			_, err := Parse(strings.NewReader(""), fileType)
+
+			require.ErrorIs(t, err, ErrEmptyData, fileType.String())
+		}
+	})
+
+	t.Run("unsupported type matches ErrUnsupportedType", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Parse(strings.NewReader("a"), Unsupported)
+		require.ErrorIs(t, err, ErrUnsupportedType)
+
+		err = ParseStream(strings.NewReader("a:1"), LTSV, func(_, _ []string) error { return nil })
+		require.ErrorIs(t, err, ErrUnsupportedType)
+	})
+
+	t.Run("duplicate column matches ErrDuplicateColumn", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Parse(strings.NewReader("id,id\n1,2"), CSV)
+
+		require.ErrorIs(t, err, ErrDuplicateColumn)
+		assert.Equal(t, "duplicate column name: id", err.Error())
+	})
+
+	t.Run("malformed CSV reports the line", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Parse(strings.NewReader("a,b\n1,2\n3\n"), CSV)
+
+		var parseErr *ParseError
+		require.ErrorAs(t, err, &parseErr)
+		assert.Equal(t, 3, parseErr.Line)
+		assert.NotErrorIs(t, err, ErrDecompression)
+	})
+
+	t.Run("corrupt and truncated compression match ErrDecompression", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Parse(strings.NewReader("id,name\n1,Alice\n"), CSVGZ)
+		require.ErrorIs(t, err, ErrDecompression)
+
+		var buf bytes.Buffer
+		gw := gzip.NewWriter(&buf)
+		_, err = gw.Write([]byte(strings.Repeat("id,name\n1,Alice\n", 100)))
+		require.NoError(t, err)
+		require.NoError(t, gw.Close())
+
+		_, err = Parse(bytes.NewReader(buf.Bytes()[:buf.Len()/2]), CSVGZ)
+		require.ErrorIs(t, err, ErrDecompression)
+		assert.NotErrorIs(t, err, ErrEmptyData)
+	})
+}
@@ -0,0 +1,115 @@
+package fileparser
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"runtime"
+	"sync"
+)
+
+// ParseFiles parses the files at paths and combines them into one table, e.g.
+// to ingest a directory of daily exports. The format of each file is detected
+// from its extension with DetectFileType, so the files may mix formats and
+// compression as long as they share a schema.
+//
+// The files are parsed in parallel by up to GOMAXPROCS goroutines. Their
+// records are concatenated in the order of paths, and their headers must
+// match as AppendRows requires; column types are widened the same way.
+// An error names the file that failed to parse or does not match the first
+// file.
+//
+// Example:
+//
+//	paths, _ := filepath.Glob("exports/*.csv.gz")
+//	combined, err := fileparser.ParseFiles(paths)
+func ParseFiles(paths []string) (*TableData, error) {
+	if len(paths) == 0 {
+		return nil, errors.New("no files to parse")
+	}
+
+	tables := make([]*TableData, len(paths))
+	errs := make([]error, len(paths))
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range min(runtime.GOMAXPROCS(0), len(paths)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				tables[i], errs[i] = parseFile(paths[i])
+			}
+		}()
+	}
+	for i := range paths {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", paths[i], err)
+		}
+	}
+
+	combined := tables[0]
+	for i, table := range tables[1:] {
+		if err := combined.AppendRows(table); err != nil {
+			return nil, fmt.Errorf("%s: %w", paths[i+1], err)
+		}
+	}
+	return combined, nil
+}
+
+// parseFile parses the file at path in the format given by its extension.
+func parseFile(path string) (_ *TableData, err error) {
+	fileType := DetectFileType(path)
+	if fileType == Unsupported {
+		return nil, ErrUnsupportedType
+	}
+
+	f, err := os.Open(path) //nolint:gosec // reading caller-provided paths is the purpose of ParseFiles
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer func() {
+		if closeErr := f.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("failed to close file: %w", closeErr)
+		}
+	}()
+
+	return Parse(f, fileType)
+}
+
+// ParseFS parses the file name in fsys, such as an embed.FS or an
+// fstest.MapFS, in the format given by its extension. It lets reference data
+// embedded in a binary, or test fixtures, be parsed without touching the OS
+// filesystem.
+//
+// Example:
+//
+//	//go:embed testdata/routing_numbers.csv
+//	var refData embed.FS
+//
+//	table, err := fileparser.ParseFS(refData, "testdata/routing_numbers.csv")
+func ParseFS(fsys fs.FS, name string) (_ *TableData, err error) {
+	fileType := DetectFileType(name)
+	if fileType == Unsupported {
+		return nil, fmt.Errorf("%s: %w", name, ErrUnsupportedType)
+	}
+
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer func() {
+		if closeErr := f.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("failed to close file: %w", closeErr)
+		}
+	}()
+
+	return Parse(f, fileType)
+}
@@ -0,0 +1,142 @@
+package fileparser
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFiles(t *testing.T) {
+	t.Parallel()
+
+	writeFiles := func(t *testing.T, files map[string]string) string {
+		t.Helper()
+		dir := t.TempDir()
+		for name, content := range files {
+			require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+		}
+		return dir
+	}
+
+	t.Run("combines files in input order", func(t *testing.T) {
+		t.Parallel()
+
+		days := []string{"01", "02", "03", "04", "05", "06", "07", "08", "09", "10"}
+		files := map[string]string{}
+		var paths []string
+		for i, day := range days {
+			name := "day" + day + ".csv"
+			files[name] = "id,amount\n" + day + "," + []string{"1", "2.5"}[i%2] + "\n"
+			paths = append(paths, name)
+		}
+		files["extra.tsv"] = "id\tamount\n11\t3\n"
+		paths = append(paths, "extra.tsv")
+
+		dir := writeFiles(t, files)
+		for i := range paths {
+			paths[i] = filepath.Join(dir, paths[i])
+		}
+
+		table, err := ParseFiles(paths)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"id", "amount"}, table.Headers)
+		require.Len(t, table.Records, 11)
+		for i, record := range table.Records[:10] {
+			assert.Equal(t, days[i], record[0])
+		}
+		assert.Equal(t, []string{"11", "3"}, table.Records[10])
+		assert.Equal(t, []ColumnType{TypeInteger, TypeReal}, table.ColumnTypes)
+	})
+
+	t.Run("names the file whose schema does not match", func(t *testing.T) {
+		t.Parallel()
+
+		dir := writeFiles(t, map[string]string{
+			"a.csv": "id,name\n1,Alice\n",
+			"b.csv": "id,email\n2,bob@example.com\n",
+		})
+
+		_, err := ParseFiles([]string{filepath.Join(dir, "a.csv"), filepath.Join(dir, "b.csv")})
+		require.ErrorContains(t, err, "b.csv: headers do not match")
+	})
+
+	t.Run("names the file that fails to parse", func(t *testing.T) {
+		t.Parallel()
+
+		dir := writeFiles(t, map[string]string{"a.csv": "id\n1\n", "notes.txt": "hello"})
+
+		_, err := ParseFiles([]string{filepath.Join(dir, "a.csv"), filepath.Join(dir, "notes.txt")})
+		require.ErrorContains(t, err, "notes.txt: unsupported file type")
+
+		_, err = ParseFiles([]string{filepath.Join(dir, "missing.csv")})
+		require.ErrorContains(t, err, "missing.csv: failed to open file")
+	})
+
+	t.Run("returns an error without files", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParseFiles(nil)
+		require.Error(t, err)
+	})
+}
+
+func TestParseFS(t *testing.T) {
+	t.Parallel()
+
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	_, err := zw.Write([]byte("routing_number,bank\n011000015,Federal Reserve Bank\n"))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+
+	fsys := fstest.MapFS{
+		"data/products.csv":           {Data: []byte("id,name\n1,Laptop\n2,Mouse\n")},
+		"data/routing_numbers.csv.gz": {Data: gz.Bytes()},
+		"data/notes.txt":              {Data: []byte("hello")},
+	}
+
+	t.Run("parses a file by its extension", func(t *testing.T) {
+		t.Parallel()
+
+		table, err := ParseFS(fsys, "data/products.csv")
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"id", "name"}, table.Headers)
+		assert.Equal(t, [][]string{{"1", "Laptop"}, {"2", "Mouse"}}, table.Records)
+	})
+
+	t.Run("parses a compressed file", func(t *testing.T) {
+		t.Parallel()
+
+		table, err := ParseFS(fsys, "data/routing_numbers.csv.gz")
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"routing_number", "bank"}, table.Headers)
+		assert.Equal(t, [][]string{{"011000015", "Federal Reserve Bank"}}, table.Records)
+	})
+
+	t.Run("returns an error for a missing file", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParseFS(fsys, "data/missing.csv")
+
+		require.ErrorIs(t, err, fs.ErrNotExist)
+	})
+
+	t.Run("returns ErrUnsupportedType for an unknown extension", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParseFS(fsys, "data/notes.txt")
+
+		require.ErrorIs(t, err, ErrUnsupportedType)
+		assert.Contains(t, err.Error(), "data/notes.txt")
+	})
+}
@@ -0,0 +1,274 @@
+package fileparser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// AggregateFunc is a function that GroupBy applies to the values of a column
+// within each group.
+type AggregateFunc int
+
+const (
+	// AggregateCount counts the non-empty values of the column, or the
+	// records of the group if the column is empty.
+	AggregateCount AggregateFunc = iota
+	// AggregateSum adds up the values of a TypeInteger or TypeReal column.
+	AggregateSum
+	// AggregateAvg averages the values of a TypeInteger or TypeReal column.
+	AggregateAvg
+	// AggregateMin keeps the smallest value of the column, compared as in SortBy.
+	AggregateMin
+	// AggregateMax keeps the largest value of the column, compared as in SortBy.
+	AggregateMax
+)
+
+// String returns the lowercase name of the function, e.g. "sum".
+func (f AggregateFunc) String() string {
+	switch f {
+	case AggregateCount:
+		return "count"
+	case AggregateSum:
+		return "sum"
+	case AggregateAvg:
+		return "avg"
+	case AggregateMin:
+		return "min"
+	case AggregateMax:
+		return "max"
+	default:
+		return "unknown"
+	}
+}
+
+// Aggregation describes one aggregated column in the result of GroupBy.
+type Aggregation struct {
+	// Column is the header name of the column to aggregate. It may be empty
+	// for AggregateCount to count records.
+	Column string
+	// Func is the function applied to the values of Column in each group.
+	Func AggregateFunc
+	// Name is the header of the result column. An empty Name means
+	// "<func>_<column>", e.g. "sum_amount", or "count" for a record count.
+	Name string
+}
+
+// name returns the header of the result column for the aggregation.
+func (a Aggregation) name() string {
+	switch {
+	case a.Name != "":
+		return a.Name
+	case a.Column == "":
+		return a.Func.String()
+	default:
+		return a.Func.String() + "_" + a.Column
+	}
+}
+
+// aggregator accumulates the values of one aggregation within one group.
+type aggregator struct {
+	count    int
+	intSum   int64
+	floatSum float64
+	extreme  string
+}
+
+// GroupBy groups the records by the values of the key columns and computes
+// the aggregations for each group. The result has the key columns followed
+// by one column per aggregation, and one record per distinct key in order of
+// first appearance. With no keys, the whole table forms a single group.
+//
+// Empty values are ignored by every function. AggregateSum and AggregateAvg
+// parse values according to the column type and ignore values that do not
+// parse, such as "n/a" or "1.5" in a TypeInteger column, the same way: type
+// inference allows a share of such values in a column (see
+// InferenceConfig.Confidence). An error is returned if the column is not
+// TypeInteger or TypeReal, or if the sum of a TypeInteger column overflows
+// int64. The sum of a TypeInteger column is TypeInteger, averages are TypeReal,
+// counts are TypeInteger, and minimums and maximums keep the column type.
+// Sums, averages, minimums, and maximums of a group without values are empty.
+//
+// Example:
+//
+//	totals, err := entries.GroupBy([]string{"batch_index"}, []fileparser.Aggregation{
+//	    {Column: "amount", Func: fileparser.AggregateSum},
+//	    {Func: fileparser.AggregateCount},
+//	})
+func (t *TableData) GroupBy(keys []string, aggs []Aggregation) (*TableData, error) {
+	keyIndexes := make([]int, len(keys))
+	headers := make([]string, 0, len(keys)+len(aggs))
+	columnTypes := make([]ColumnType, 0, len(keys)+len(aggs))
+	for i, key := range keys {
+		idx, ok := t.ColumnIndex(key)
+		if !ok {
+			return nil, fmt.Errorf("column %q not found", key)
+		}
+		keyIndexes[i] = idx
+		headers = append(headers, key)
+		columnTypes = append(columnTypes, columnTypeAt(t.ColumnTypes, idx))
+	}
+
+	aggIndexes := make([]int, len(aggs))
+	aggTypes := make([]ColumnType, len(aggs))
+	for i, agg := range aggs {
+		aggIndexes[i] = -1
+		if agg.Column != "" {
+			idx, ok := t.ColumnIndex(agg.Column)
+			if !ok {
+				return nil, fmt.Errorf("column %q not found", agg.Column)
+			}
+			aggIndexes[i] = idx
+			aggTypes[i] = columnTypeAt(t.ColumnTypes, idx)
+		}
+
+		resultType, err := agg.resultType(aggTypes[i])
+		if err != nil {
+			return nil, err
+		}
+		headers = append(headers, agg.name())
+		columnTypes = append(columnTypes, resultType)
+	}
+	if err := validateColumnNames(headers); err != nil {
+		return nil, err
+	}
+
+	var (
+		groupKeys   [][]string
+		aggregators [][]aggregator
+	)
+	groupIndex := make(map[string]int)
+	if len(keys) == 0 {
+		groupKeys = append(groupKeys, nil)
+		aggregators = append(aggregators, make([]aggregator, len(aggs)))
+	}
+
+	for recordIdx, record := range t.Records {
+		keyValues := make([]string, len(keyIndexes))
+		for i, idx := range keyIndexes {
+			keyValues[i] = cellAt(record, idx)
+		}
+		key := recordKey(keyValues)
+		g, ok := groupIndex[key]
+		if !ok && len(keys) > 0 {
+			g = len(groupKeys)
+			groupIndex[key] = g
+			groupKeys = append(groupKeys, keyValues)
+			aggregators = append(aggregators, make([]aggregator, len(aggs)))
+		}
+
+		for i, agg := range aggs {
+			if aggIndexes[i] < 0 {
+				aggregators[g][i].count++
+				continue
+			}
+			value := strings.TrimSpace(cellAt(record, aggIndexes[i]))
+			if value == "" {
+				continue
+			}
+			if err := aggregators[g][i].add(agg.Func, value, aggTypes[i]); err != nil {
+				return nil, fmt.Errorf("record %d, column %q: %w", recordIdx+1, agg.Column, err)
+			}
+		}
+	}
+
+	records := make([][]string, len(groupKeys))
+	for g, keyValues := range groupKeys {
+		record := make([]string, 0, len(headers))
+		record = append(record, keyValues...)
+		for i, agg := range aggs {
+			record = append(record, aggregators[g][i].result(agg.Func, aggTypes[i]))
+		}
+		records[g] = record
+	}
+
+	return &TableData{
+		Headers:     headers,
+		Records:     records,
+		ColumnTypes: columnTypes,
+	}, nil
+}
+
+// resultType returns the type of the result column of the aggregation over a
+// column of type colType, or an error if the function cannot be applied.
+func (a Aggregation) resultType(colType ColumnType) (ColumnType, error) {
+	switch a.Func {
+	case AggregateCount:
+		return TypeInteger, nil
+	case AggregateSum, AggregateAvg:
+		if a.Column == "" {
+			return TypeText, fmt.Errorf("%s requires a column", a.Func)
+		}
+		if colType != TypeInteger && colType != TypeReal {
+			return TypeText, fmt.Errorf("cannot %s column %q of type %s", a.Func, a.Column, colType)
+		}
+		if a.Func == AggregateSum && colType == TypeInteger {
+			return TypeInteger, nil
+		}
+		return TypeReal, nil
+	case AggregateMin, AggregateMax:
+		if a.Column == "" {
+			return TypeText, fmt.Errorf("%s requires a column", a.Func)
+		}
+		return colType, nil
+	default:
+		return TypeText, fmt.Errorf("unsupported aggregate function %d", int(a.Func))
+	}
+}
+
+// add accumulates a non-empty value of a column of type colType. Sums and
+// averages skip values that do not parse as colType.
+func (a *aggregator) add(fn AggregateFunc, value string, colType ColumnType) error {
+	switch fn {
+	case AggregateSum, AggregateAvg:
+		if colType == TypeInteger {
+			n, ok := parseInteger(value)
+			if !ok {
+				return nil
+			}
+			sum := a.intSum + n
+			if fn == AggregateSum && (n > 0 && sum < a.intSum || n < 0 && sum > a.intSum) {
+				return fmt.Errorf("sum overflows int64 at value %q", value)
+			}
+			a.intSum = sum
+			a.floatSum += float64(n)
+		} else {
+			f, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil
+			}
+			a.floatSum += f
+		}
+	case AggregateMin:
+		if a.count == 0 || compareCells(value, a.extreme, colType) < 0 {
+			a.extreme = value
+		}
+	case AggregateMax:
+		if a.count == 0 || compareCells(value, a.extreme, colType) > 0 {
+			a.extreme = value
+		}
+	}
+	a.count++
+	return nil
+}
+
+// result returns the aggregated value formatted as a cell.
+func (a *aggregator) result(fn AggregateFunc, colType ColumnType) string {
+	if fn == AggregateCount {
+		return strconv.Itoa(a.count)
+	}
+	if a.count == 0 {
+		return ""
+	}
+	switch fn {
+	case AggregateSum:
+		if colType == TypeInteger {
+			return strconv.FormatInt(a.intSum, 10)
+		}
+		return formatFloat(a.floatSum, 64)
+	case AggregateAvg:
+		return formatFloat(a.floatSum/float64(a.count), 64)
+	default:
+		return a.extreme
+	}
+}
@@ -0,0 +1,152 @@
+package fileparser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTableData_GroupBy(t *testing.T) {
+	t.Parallel()
+
+	input := "batch,amount,rate,name\n" +
+		"1,100,0.5,carol\n" +
+		"2,250,1.25,alice\n" +
+		"1,300,,bob\n" +
+		"2,,2.5,dave\n"
+
+	t.Run("aggregates each group in order of first appearance", func(t *testing.T) {
+		t.Parallel()
+
+		table, err := Parse(strings.NewReader(input), CSV)
+		require.NoError(t, err)
+
+		result, err := table.GroupBy([]string{"batch"}, []Aggregation{
+			{Column: "amount", Func: AggregateSum},
+			{Func: AggregateCount},
+			{Column: "amount", Func: AggregateCount, Name: "amounts"},
+			{Column: "rate", Func: AggregateAvg},
+			{Column: "amount", Func: AggregateMax},
+			{Column: "name", Func: AggregateMin},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"batch", "sum_amount", "count", "amounts", "avg_rate", "max_amount", "min_name"}, result.Headers)
+		assert.Equal(t, [][]string{
+			{"1", "400", "2", "2", "0.5", "300", "bob"},
+			{"2", "250", "2", "1", "1.875", "250", "alice"},
+		}, result.Records)
+		assert.Equal(t, []ColumnType{
+			TypeInteger, TypeInteger, TypeInteger, TypeInteger, TypeReal, TypeInteger, TypeText,
+		}, result.ColumnTypes)
+	})
+
+	t.Run("treats the whole table as one group without keys", func(t *testing.T) {
+		t.Parallel()
+
+		table, err := Parse(strings.NewReader(input), CSV)
+		require.NoError(t, err)
+
+		result, err := table.GroupBy(nil, []Aggregation{
+			{Column: "amount", Func: AggregateSum},
+			{Column: "rate", Func: AggregateSum},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{{"650", "4.25"}}, result.Records)
+	})
+
+	t.Run("compares minimums by column type", func(t *testing.T) {
+		t.Parallel()
+
+		table := &TableData{
+			Headers:     []string{"n"},
+			Records:     [][]string{{"10"}, {"9"}, {"100"}},
+			ColumnTypes: []ColumnType{TypeInteger},
+		}
+
+		result, err := table.GroupBy(nil, []Aggregation{{Column: "n", Func: AggregateMin}})
+
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{{"9"}}, result.Records)
+	})
+
+	t.Run("skips values that do not parse like empty values", func(t *testing.T) {
+		t.Parallel()
+
+		table := &TableData{
+			Headers:     []string{"n", "r"},
+			Records:     [][]string{{"1", "0.5"}, {"n/a", "-"}, {"1.5", ""}, {"", "1.5"}, {"4", "x"}},
+			ColumnTypes: []ColumnType{TypeInteger, TypeReal},
+		}
+
+		result, err := table.GroupBy(nil, []Aggregation{
+			{Column: "n", Func: AggregateSum},
+			{Column: "n", Func: AggregateAvg},
+			{Column: "r", Func: AggregateSum},
+			{Column: "r", Func: AggregateAvg},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{{"5", "2.5", "2", "1"}}, result.Records)
+	})
+
+	t.Run("returns error when an integer sum overflows", func(t *testing.T) {
+		t.Parallel()
+
+		for _, values := range [][]string{
+			{"9223372036854775807", "1"},
+			{"-9223372036854775808", "-1"},
+		} {
+			table := &TableData{
+				Headers:     []string{"n"},
+				Records:     [][]string{{values[0]}, {values[1]}},
+				ColumnTypes: []ColumnType{TypeInteger},
+			}
+
+			_, err := table.GroupBy(nil, []Aggregation{{Column: "n", Func: AggregateSum}})
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), `record 2, column "n": sum overflows int64 at value "`+values[1]+`"`)
+
+			result, err := table.GroupBy(nil, []Aggregation{{Column: "n", Func: AggregateAvg}})
+			require.NoError(t, err)
+			assert.Len(t, result.Records, 1)
+		}
+	})
+
+	t.Run("returns errors for invalid aggregations", func(t *testing.T) {
+		t.Parallel()
+
+		table := &TableData{
+			Headers:     []string{"k", "n", "s"},
+			Records:     [][]string{{"a", "1", "x"}, {"a", "oops", "y"}},
+			ColumnTypes: []ColumnType{TypeText, TypeInteger, TypeText},
+		}
+
+		tests := []struct {
+			name string
+			keys []string
+			aggs []Aggregation
+			want string
+		}{
+			{name: "unknown key", keys: []string{"missing"}, want: `column "missing" not found`},
+			{name: "unknown column", aggs: []Aggregation{{Column: "missing", Func: AggregateMax}}, want: `column "missing" not found`},
+			{name: "sum of text", aggs: []Aggregation{{Column: "s", Func: AggregateSum}}, want: `cannot sum column "s" of type TEXT`},
+			{name: "duplicate result name", keys: []string{"k"}, aggs: []Aggregation{{Func: AggregateCount, Name: "k"}}, want: "duplicate column name: k"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				_, err := table.GroupBy(tt.keys, tt.aggs)
+
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.want)
+			})
+		}
+	})
+}
@@ -0,0 +1,79 @@
+package fileparser
+
+import (
+	"strconv"
+	"strings"
+)
+
+// HeaderDetection controls whether the first row of CSV, TSV, and XLSX input
+// is treated as the header.
+type HeaderDetection int
+
+const (
+	// HeaderAlways treats the first row as the header. This is the default.
+	HeaderAlways HeaderDetection = iota
+	// HeaderNever treats every row as data and generates synthetic headers
+	// named column1, column2, and so on.
+	HeaderNever
+	// HeaderAuto infers column types from the rows after the first and treats
+	// the first row as the header only if its values do not fit those types.
+	// When no column has a non-text type, the first row is treated as the header.
+	HeaderAuto
+)
+
+// resolveHeader decides whether firstRow is the header according to mode and
+// reports the decision. HeaderAuto infers types with config, as the column
+// types of the result are inferred. When firstRow is data, it is prepended to records and
+// synthetic headers are returned instead. maxRows limits the number of returned
+// records as in ParseOptions.MaxRows.
+func resolveHeader(mode HeaderDetection, config InferenceConfig, firstRow []string, records [][]string, maxRows int) ([]string, [][]string, bool) {
+	if mode == HeaderAlways || (mode == HeaderAuto && config.looksLikeHeader(firstRow, records)) {
+		return firstRow, records, true
+	}
+
+	records = append([][]string{firstRow}, records...)
+	if maxRows > 0 && len(records) > maxRows {
+		records = records[:maxRows]
+	}
+	return syntheticHeaders(len(firstRow)), records, false
+}
+
+// looksLikeHeader reports whether firstRow contains a value whose type does not
+// match the type c infers for its column from records. Empty values in
+// firstRow are ignored because they are compatible with any column type.
+func (c InferenceConfig) looksLikeHeader(firstRow []string, records [][]string) bool {
+	if len(records) == 0 {
+		return true
+	}
+
+	typedColumns := 0
+	for i, value := range firstRow {
+		colType := c.inferColumnType(records, i)
+		if colType == TypeText {
+			continue
+		}
+		typedColumns++
+
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		valueType := c.classifyValue(value)
+		if valueType == colType || (colType == TypeReal && valueType == TypeInteger) {
+			continue
+		}
+		return true
+	}
+
+	// Without any typed column, there is no evidence that the first row is data
+	return typedColumns == 0
+}
+
+// syntheticHeaders returns n generated column names: column1, column2, ...
+func syntheticHeaders(n int) []string {
+	headers := make([]string, n)
+	for i := range headers {
+		headers[i] = "column" + strconv.Itoa(i+1)
+	}
+	return headers
+}
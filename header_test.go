@@ -0,0 +1,144 @@
+package fileparser
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestParseWithOptions_HeaderDetection(t *testing.T) {
+	t.Parallel()
+
+	t.Run("auto detects headered CSV", func(t *testing.T) {
+		t.Parallel()
+
+		input := "id,name,price\n1,Laptop,999.99\n2,Mouse,29.99\n"
+
+		result, err := ParseWithOptions(strings.NewReader(input), CSV, ParseOptions{HeaderDetection: HeaderAuto})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"id", "name", "price"}, result.Headers)
+		assert.Len(t, result.Records, 2)
+	})
+
+	t.Run("auto detects headerless CSV", func(t *testing.T) {
+		t.Parallel()
+
+		input := "1,Laptop,999.99\n2,Mouse,29.99\n3,Keyboard,79\n"
+
+		result, err := ParseWithOptions(strings.NewReader(input), CSV, ParseOptions{HeaderDetection: HeaderAuto})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"column1", "column2", "column3"}, result.Headers)
+		assert.Equal(t, [][]string{
+			{"1", "Laptop", "999.99"},
+			{"2", "Mouse", "29.99"},
+			{"3", "Keyboard", "79"},
+		}, result.Records)
+		assert.Equal(t, []ColumnType{TypeInteger, TypeText, TypeReal}, result.ColumnTypes)
+	})
+
+	t.Run("auto treats first row as header when all columns are text", func(t *testing.T) {
+		t.Parallel()
+
+		input := "alice,tokyo\nbob,osaka\n"
+
+		result, err := ParseWithOptions(strings.NewReader(input), CSV, ParseOptions{HeaderDetection: HeaderAuto})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"alice", "tokyo"}, result.Headers)
+	})
+
+	t.Run("auto infers types with the inference config", func(t *testing.T) {
+		t.Parallel()
+
+		input := "$5,45%\n\"$1,234\",12.5%\n$20,3%\n"
+		opts := ParseOptions{
+			HeaderDetection: HeaderAuto,
+			Inference:       InferenceConfig{ParseLocalizedNumbers: true, ParsePercentages: true},
+		}
+
+		result, err := ParseWithOptions(strings.NewReader(input), CSV, opts)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"column1", "column2"}, result.Headers)
+		assert.Len(t, result.Records, 3)
+		assert.Equal(t, []ColumnType{TypeInteger, TypeReal}, result.ColumnTypes)
+
+		result, err = ParseWithOptions(strings.NewReader(input), CSV, ParseOptions{HeaderDetection: HeaderAuto})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"$5", "45%"}, result.Headers)
+	})
+
+	t.Run("never generates synthetic headers", func(t *testing.T) {
+		t.Parallel()
+
+		input := "id\tname\n1\tLaptop\n"
+
+		result, err := ParseWithOptions(strings.NewReader(input), TSV, ParseOptions{HeaderDetection: HeaderNever})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"column1", "column2"}, result.Headers)
+		assert.Equal(t, [][]string{{"id", "name"}, {"1", "Laptop"}}, result.Records)
+	})
+
+	t.Run("never respects MaxRows", func(t *testing.T) {
+		t.Parallel()
+
+		input := "1\n2\n3\n"
+
+		result, err := ParseWithOptions(strings.NewReader(input), CSV, ParseOptions{HeaderDetection: HeaderNever, MaxRows: 2})
+
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{{"1"}, {"2"}}, result.Records)
+	})
+
+	t.Run("never allows duplicate values in the first row", func(t *testing.T) {
+		t.Parallel()
+
+		input := "1,1\n2,2\n"
+
+		result, err := ParseWithOptions(strings.NewReader(input), CSV, ParseOptions{HeaderDetection: HeaderNever})
+
+		require.NoError(t, err)
+		assert.Len(t, result.Records, 2)
+	})
+
+	t.Run("headerless CSV keeps line prefix captures aligned", func(t *testing.T) {
+		t.Parallel()
+
+		input := "[a] 1,2\n[b] 3,4\n"
+
+		result, err := ParseWithOptions(strings.NewReader(input), CSV, ParseOptions{
+			HeaderDetection: HeaderNever,
+			LinePrefix:      regexp.MustCompile(`^\[(?P<tag>\w+)\] `),
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"tag", "column1", "column2"}, result.Headers)
+		assert.Equal(t, [][]string{{"a", "1", "2"}, {"b", "3", "4"}}, result.Records)
+	})
+
+	t.Run("auto detects headerless XLSX", func(t *testing.T) {
+		t.Parallel()
+
+		f := excelize.NewFile()
+		defer f.Close()
+		require.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]any{1, "a"}))
+		require.NoError(t, f.SetSheetRow("Sheet1", "A2", &[]any{2, "b"}))
+		var buf bytes.Buffer
+		require.NoError(t, f.Write(&buf))
+
+		result, err := ParseWithOptions(&buf, XLSX, ParseOptions{HeaderDetection: HeaderAuto})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"column1", "column2"}, result.Headers)
+		assert.Equal(t, [][]string{{"1", "a"}, {"2", "b"}}, result.Records)
+	})
+}
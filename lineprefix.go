@@ -0,0 +1,94 @@
+package fileparser
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// linePrefixCaptures holds the named capture group values extracted from
+// line prefixes, one entry per non-empty line in input order.
+type linePrefixCaptures struct {
+	names  []string
+	values [][]string
+}
+
+// stripLinePrefixes removes the text matched by prefix from the start of every line.
+// It returns a reader over the remaining content and the captured group values.
+// Lines that do not match the prefix are passed through unchanged.
+func stripLinePrefixes(reader io.Reader, prefix *regexp.Regexp) (io.Reader, *linePrefixCaptures, error) {
+	captures := &linePrefixCaptures{}
+	groupIndexes := make([]int, 0, prefix.NumSubexp())
+	for i, name := range prefix.SubexpNames() {
+		if name != "" {
+			captures.names = append(captures.names, name)
+			groupIndexes = append(groupIndexes, i)
+		}
+	}
+
+	var sb strings.Builder
+	bufReader := bufio.NewReader(newBOMAwareReader(reader))
+	for {
+		line, err := bufReader.ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, nil, fmt.Errorf("failed to read line: %w", err)
+		}
+		if line != "" {
+			rest := line
+			values := make([]string, len(groupIndexes))
+			if loc := prefix.FindStringSubmatchIndex(line); loc != nil && loc[0] == 0 {
+				rest = line[loc[1]:]
+				for i, group := range groupIndexes {
+					if start := loc[2*group]; start >= 0 {
+						values[i] = line[start:loc[2*group+1]]
+					}
+				}
+			}
+			if strings.TrimSpace(rest) != "" {
+				captures.values = append(captures.values, values)
+			}
+			sb.WriteString(rest)
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+	}
+
+	return strings.NewReader(sb.String()), captures, nil
+}
+
+// addColumns prepends the captured prefix values to the table as leading columns.
+// When hasHeaderLine is true, the captures of the first line belong to the header
+// row and are discarded.
+func (c *linePrefixCaptures) addColumns(table *TableData, hasHeaderLine bool, opts ParseOptions) error {
+	if len(c.names) == 0 {
+		return nil
+	}
+
+	values := c.values
+	if hasHeaderLine && len(values) > 0 {
+		values = values[1:]
+	}
+	if opts.reachedMaxRows(len(table.Records)) && len(values) > len(table.Records) {
+		values = values[:len(table.Records)]
+	}
+	if len(values) != len(table.Records) {
+		return fmt.Errorf("line prefix captured %d lines but %d records were parsed", len(values), len(table.Records))
+	}
+
+	headers := append(append([]string{}, c.names...), table.Headers...)
+	if err := validateColumnNames(headers); err != nil {
+		return err
+	}
+
+	for i, record := range table.Records {
+		table.Records[i] = append(append([]string{}, values[i]...), record...)
+	}
+	table.Headers = headers
+	table.ColumnTypes = append(opts.inferColumnTypes(c.names, values), table.ColumnTypes...)
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package fileparser
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWithOptions_LinePrefix(t *testing.T) {
+	t.Parallel()
+
+	t.Run("strips syslog prefix before LTSV payload", func(t *testing.T) {
+		t.Parallel()
+
+		input := "Jan 2 10:00:00 host host:192.168.0.1\tmethod:GET\tstatus:200\n" +
+			"Jan 2 10:00:01 host host:192.168.0.2\tmethod:POST\tstatus:404\n"
+		opts := ParseOptions{LinePrefix: regexp.MustCompile(`^\w{3} +\d+ [\d:]+ \S+ `)}
+
+		result, err := ParseWithOptions(strings.NewReader(input), LTSV, opts)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"host", "method", "status"}, result.Headers)
+		assert.Equal(t, []string{"192.168.0.1", "GET", "200"}, result.Records[0])
+		assert.Equal(t, []string{"192.168.0.2", "POST", "404"}, result.Records[1])
+		assert.Equal(t, TypeInteger, result.ColumnTypes[2])
+	})
+
+	t.Run("captures named groups into leading columns", func(t *testing.T) {
+		t.Parallel()
+
+		input := "Jan 2 10:00:00 web01 method:GET\tstatus:200\n" +
+			"Jan 2 10:00:01 web02 method:POST\tstatus:404\n"
+		opts := ParseOptions{LinePrefix: regexp.MustCompile(`^(?P<time>\w{3} +\d+ [\d:]+) (?P<server>\S+) `)}
+
+		result, err := ParseWithOptions(strings.NewReader(input), LTSV, opts)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"time", "server", "method", "status"}, result.Headers)
+		assert.Equal(t, []string{"Jan 2 10:00:00", "web01", "GET", "200"}, result.Records[0])
+		assert.Equal(t, []string{"Jan 2 10:00:01", "web02", "POST", "404"}, result.Records[1])
+		assert.Len(t, result.ColumnTypes, 4)
+		assert.Equal(t, TypeText, result.ColumnTypes[1])
+		assert.Equal(t, TypeInteger, result.ColumnTypes[3])
+	})
+
+	t.Run("strips prefix before CSV and discards header captures", func(t *testing.T) {
+		t.Parallel()
+
+		input := "name,age\n" +
+			"Jan 2 10:00:00 web01 Alice,30\n" +
+			"Jan 2 10:00:01 web02 Bob,25\n"
+		opts := ParseOptions{LinePrefix: regexp.MustCompile(`^\w{3} +\d+ [\d:]+ (?P<server>\S+) `)}
+
+		result, err := ParseWithOptions(strings.NewReader(input), CSV, opts)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"server", "name", "age"}, result.Headers)
+		assert.Equal(t, []string{"web01", "Alice", "30"}, result.Records[0])
+		assert.Equal(t, []string{"web02", "Bob", "25"}, result.Records[1])
+	})
+
+	t.Run("returns error when capture name collides with a column", func(t *testing.T) {
+		t.Parallel()
+
+		input := "web01 host:a\nweb02 host:b\n"
+		opts := ParseOptions{LinePrefix: regexp.MustCompile(`^(?P<host>\S+) `)}
+
+		_, err := ParseWithOptions(strings.NewReader(input), LTSV, opts)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "duplicate column name")
+	})
+}
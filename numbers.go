@@ -0,0 +1,85 @@
+package fileparser
+
+import (
+	"strings"
+	"unicode"
+)
+
+// separators returns the decimal and group separators of localized numbers,
+// applying the defaults for zero values.
+func (c InferenceConfig) separators() (decimalSep, groupSep rune) {
+	decimalSep, groupSep = c.DecimalSep, c.GroupSep
+	if decimalSep == 0 {
+		decimalSep = '.'
+	}
+	if groupSep == 0 {
+		groupSep = ','
+	}
+	return decimalSep, groupSep
+}
+
+// normalizeNumber converts a localized number such as "$1,234.56" into the
+// plain form strconv accepts, "1234.56". It reports false when s is not a
+// number in the separators of c. Currency symbols may precede or follow the
+// number, and the sign may come before or after a leading symbol.
+func (c InferenceConfig) normalizeNumber(s string) (string, bool) {
+	decimalSep, groupSep := c.separators()
+
+	s = strings.TrimSpace(s)
+	sign, s := cutSign(s)
+	s = strings.TrimLeftFunc(s, isCurrencyOrSpace)
+	if sign == "" {
+		sign, s = cutSign(s)
+	}
+	s = strings.TrimRightFunc(s, isCurrencyOrSpace)
+	if s == "" {
+		return "", false
+	}
+
+	intPart, fracPart, hasFrac := strings.Cut(s, string(decimalSep))
+	if hasFrac && !isDigits(fracPart) {
+		return "", false
+	}
+
+	groups := strings.Split(intPart, string(groupSep))
+	if !isDigits(groups[0]) || (len(groups) > 1 && len(groups[0]) > 3) {
+		return "", false
+	}
+	for _, group := range groups[1:] {
+		if len(group) != 3 || !isDigits(group) {
+			return "", false
+		}
+	}
+
+	normalized := sign + strings.Join(groups, "")
+	if hasFrac {
+		normalized += "." + fracPart
+	}
+	return normalized, true
+}
+
+// cutSign removes a leading '-' or '+' from s and returns it separately.
+func cutSign(s string) (string, string) {
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		return s[:1], s[1:]
+	}
+	return "", s
+}
+
+// isCurrencyOrSpace reports whether r is a currency symbol or white space.
+func isCurrencyOrSpace(r rune) bool {
+	return unicode.Is(unicode.Sc, r) || unicode.IsSpace(r)
+}
+
+// isDigits reports whether s is a non-empty string of ASCII digits.
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
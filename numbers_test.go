@@ -0,0 +1,113 @@
+package fileparser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInferenceConfig_normalizeNumber(t *testing.T) {
+	t.Parallel()
+
+	t.Run("default separators", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := InferenceConfig{ParseLocalizedNumbers: true}
+		testCases := []struct {
+			input    string
+			expected string
+			ok       bool
+		}{
+			{"1,234.56", "1234.56", true},
+			{"$1,234", "1234", true},
+			{"-$1,234.50", "-1234.50", true},
+			{"$-12", "-12", true},
+			{"1 234 €", "", false},
+			{"12.50 €", "12.50", true},
+			{"1234", "1234", true},
+			{"1,234,567", "1234567", true},
+			{"1.234,56", "", false},
+			{"12,34", "", false},
+			{"1234,567", "", false},
+			{"$", "", false},
+			{"abc", "", false},
+		}
+
+		for _, tc := range testCases {
+			normalized, ok := cfg.normalizeNumber(tc.input)
+
+			assert.Equal(t, tc.ok, ok, tc.input)
+			assert.Equal(t, tc.expected, normalized, tc.input)
+		}
+	})
+
+	t.Run("european separators", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := InferenceConfig{ParseLocalizedNumbers: true, DecimalSep: ',', GroupSep: '.'}
+
+		normalized, ok := cfg.normalizeNumber("€1.234,56")
+		assert.True(t, ok)
+		assert.Equal(t, "1234.56", normalized)
+
+		_, ok = cfg.normalizeNumber("1,234.56")
+		assert.False(t, ok)
+	})
+}
+
+func TestParseWithOptions_LocalizedNumbers(t *testing.T) {
+	t.Parallel()
+
+	t.Run("infers numeric types for localized values", func(t *testing.T) {
+		t.Parallel()
+
+		data := "price,count\n\"$1,234.56\",\"1,000\"\n$12.00,7\n"
+		opts := ParseOptions{Inference: InferenceConfig{ParseLocalizedNumbers: true}}
+
+		result, err := ParseWithOptions(strings.NewReader(data), CSV, opts)
+
+		require.NoError(t, err)
+		assert.Equal(t, []ColumnType{TypeReal, TypeInteger}, result.ColumnTypes)
+		assert.Equal(t, 1234.56, opts.Inference.ParseValue(result.Records[0][0], TypeReal))
+		assert.Equal(t, int64(1000), opts.Inference.ParseValue(result.Records[0][1], TypeInteger))
+	})
+
+	t.Run("keeps values as text without the option", func(t *testing.T) {
+		t.Parallel()
+
+		data := "price\n\"$1,234.56\"\n"
+
+		result, err := Parse(strings.NewReader(data), CSV)
+
+		require.NoError(t, err)
+		assert.Equal(t, []ColumnType{TypeText}, result.ColumnTypes)
+		assert.Equal(t, "$1,234.56", ParseValue(result.Records[0][0], TypeReal))
+	})
+
+	t.Run("reads european numbers with matching separators", func(t *testing.T) {
+		t.Parallel()
+
+		data := "amount\n\"1.234,56\"\n\"7,5\"\n1.000\n"
+		cfg := InferenceConfig{ParseLocalizedNumbers: true, DecimalSep: ',', GroupSep: '.'}
+
+		result, err := ParseWithOptions(strings.NewReader(data), CSV, ParseOptions{Inference: cfg})
+
+		require.NoError(t, err)
+		assert.Equal(t, []ColumnType{TypeReal}, result.ColumnTypes)
+		assert.Equal(t, 1234.56, cfg.ParseValue("1.234,56", TypeReal))
+		assert.Equal(t, 1000.0, cfg.ParseValue("1.000", TypeReal))
+	})
+
+	t.Run("returns error for identical separators", func(t *testing.T) {
+		t.Parallel()
+
+		opts := ParseOptions{Inference: InferenceConfig{ParseLocalizedNumbers: true, DecimalSep: ','}}
+
+		_, err := ParseWithOptions(strings.NewReader("a\n1\n"), CSV, opts)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "separators must differ")
+	})
+}
@@ -0,0 +1,422 @@
+package fileparser
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// ParseOptions configures optional parsing behavior for ParseWithOptions.
+// The zero value produces the same result as Parse.
+type ParseOptions struct {
+	// LinePrefix, when set, is matched against the start of every line of
+	// CSV, TSV, and LTSV input, and the matched text is removed before the
+	// line is parsed. This handles formats such as syslog-wrapped LTSV where
+	// each line starts with a timestamp and host.
+	//
+	// Named capture groups in the expression become extra leading columns
+	// holding the captured text for each record. For CSV/TSV, each record
+	// must fit on a single line when LinePrefix is used.
+	LinePrefix *regexp.Regexp
+
+	// AutoDecompress detects the compression of the input from its leading
+	// bytes instead of trusting the compression implied by the file type, so
+	// Parse(r, CSV) and Parse(r, CSVGZ) both succeed whether or not the input
+	// is actually gzip-compressed. All supported formats except brotli have a
+	// recognizable header; brotli input is decompressed only when the file
+	// type says so.
+	AutoDecompress bool
+
+	// MaxRows limits the number of data rows read from the input.
+	// Reading stops once MaxRows records have been parsed, and column types
+	// are inferred from those records only. Zero or a negative value means
+	// no limit.
+	MaxRows int
+
+	// HeaderDetection controls whether the first row of CSV, TSV, and XLSX
+	// input is the header. The default, HeaderAlways, treats it as the header.
+	HeaderDetection HeaderDetection
+
+	// FieldsPerRecord sets the number of fields each CSV and TSV record must
+	// have, as csv.Reader.FieldsPerRecord does. Zero, the default, requires
+	// every record to have as many fields as the first one; a positive value
+	// requires exactly that many. A negative value allows ragged rows: short
+	// records are padded with empty strings and long records are truncated to
+	// the number of columns, as is done for XLSX rows.
+	FieldsPerRecord int
+
+	// SkipEmptyLines drops CSV and TSV lines that contain only whitespace,
+	// which would otherwise be parsed as records with a single blank field.
+	// Lines inside quoted fields are kept. Completely empty lines are always
+	// skipped, and LTSV input ignores blank lines regardless of this option.
+	SkipEmptyLines bool
+
+	// Encoding is the character encoding of CSV, TSV, LTSV, XML, and YAML
+	// input, such as "shift_jis", "euc-jp", or "windows-1252". The input is
+	// converted to UTF-8 before parsing. Labels follow the WHATWG Encoding Standard.
+	// An empty string means UTF-8, or for XML the encoding it declares.
+	Encoding string
+
+	// ColumnTypeOverrides forces the type of the named columns after type
+	// inference, e.g. to keep zip codes such as "01234" as TypeText instead
+	// of TypeInteger. Names that do not match a column are ignored.
+	ColumnTypeOverrides map[string]ColumnType
+
+	// DisableTypeInference skips type inference and gives every column
+	// TypeText, e.g. to keep leading zeros of codes and phone numbers from
+	// being read as integers. It also saves the inference pass over large
+	// files. ColumnTypeOverrides still apply.
+	DisableTypeInference bool
+
+	// Inference tunes column type inference, e.g. to restrict the types it
+	// may produce. The zero value infers every supported type.
+	Inference InferenceConfig
+
+	// Columns limits Parquet input to the named top-level columns. Only these
+	// columns are decoded, which saves memory and time on wide files, and the
+	// resulting Headers follow the requested order. An error is returned if a
+	// column does not exist. Empty means all columns.
+	Columns []string
+
+	// RowGroup reads only the given one-based Parquet row group, so large files
+	// can be processed in chunks (see ParquetNumRowGroups). Zero reads all
+	// row groups.
+	RowGroup int
+
+	// SheetName selects the XLSX sheet to read by name.
+	// It takes precedence over SheetIndex.
+	SheetName string
+
+	// SheetIndex selects the XLSX sheet to read by its zero-based position in
+	// the workbook. The default reads the first sheet.
+	SheetIndex int
+
+	// Password opens a password-protected XLSX workbook. Parsing an
+	// encrypted workbook without it, or with a wrong one, fails with an
+	// error matching ErrEncrypted.
+	Password string
+
+	// LTSVDuplicateKeys controls which value is kept when an LTSV line
+	// repeats a label, e.g. "tag:a<TAB>tag:b". The default, DuplicateKeyLast,
+	// keeps the last value.
+	LTSVDuplicateKeys DuplicateKeyPolicy
+
+	// LTSVJoinSeparator separates the values of a repeated LTSV label when
+	// LTSVDuplicateKeys is DuplicateKeyJoin. An empty string means ",".
+	LTSVJoinSeparator string
+
+	// LTSVFieldSeparator separates the labeled fields of an LTSV line and may
+	// be several characters long, e.g. " | " for key-value logs that are not
+	// tab-separated. An empty string means a tab.
+	LTSVFieldSeparator string
+
+	// LTSVLabelSeparator separates the label from the value within an LTSV
+	// field and may be several characters long, e.g. " => ". Only the first
+	// occurrence in a field splits it. An empty string means ":".
+	LTSVLabelSeparator string
+
+	// XMLRecordElement is the name of the XML element that holds one record,
+	// e.g. "row" for <rows><row><a>1</a></row></rows>. An empty string treats
+	// every child of the root element as a record.
+	XMLRecordElement string
+
+	// DropTrailingEmptyColumn removes the last column of CSV and TSV input
+	// when its header is empty, as in exports that end every line with a
+	// delimiter, e.g. "a,b,\n1,2,\n". Each record loses its extra trailing
+	// value to match. Only one column is removed.
+	DropTrailingEmptyColumn bool
+
+	// CSVDialect selects how strictly CSV and TSV input must follow RFC 4180,
+	// e.g. CSVDialectRFC4180 to reject files with bare LF or CR line endings
+	// or CSVDialectExcel to accept the quirks of spreadsheet exports. The
+	// zero value parses like encoding/csv.
+	CSVDialect CSVDialect
+
+	// TrimSpace removes leading and trailing white space from every value of
+	// CSV, TSV, LTSV, and XLSX input, so " 42 " becomes "42". By default CSV,
+	// TSV, and XLSX values are kept as they are, while LTSV values are always
+	// trimmed. Headers are not affected.
+	TrimSpace bool
+
+	// Strict validates the parsed table with TableData.Validate and returns
+	// its error, so malformed input fails loudly instead of producing ragged
+	// records.
+	Strict bool
+
+	// PrimaryKey names the columns that identify a record and is copied to
+	// TableData.PrimaryKey. Parsing fails if a named column is missing.
+	// Uniqueness is checked by TableData.Validate, e.g. together with Strict.
+	PrimaryKey []string
+
+	// XLSX holds options that only apply to XLSX input.
+	XLSX XLSXOptions
+
+	// Zstd tunes the decoder of zstd-compressed input.
+	Zstd ZstdOptions
+}
+
+// DuplicateKeyPolicy controls how a label that appears more than once on the
+// same LTSV line is handled.
+type DuplicateKeyPolicy int
+
+const (
+	// DuplicateKeyLast keeps the last value of a repeated label. This is the default.
+	DuplicateKeyLast DuplicateKeyPolicy = iota
+	// DuplicateKeyFirst keeps the first value of a repeated label.
+	DuplicateKeyFirst
+	// DuplicateKeyJoin keeps every value of a repeated label in one cell,
+	// separated by ParseOptions.LTSVJoinSeparator.
+	DuplicateKeyJoin
+	// DuplicateKeyError fails parsing with an error naming the repeated label
+	// and the record it appears in.
+	DuplicateKeyError
+)
+
+// Defaults for the LTSV separator options.
+const (
+	defaultLTSVJoinSeparator  = ","
+	defaultLTSVFieldSeparator = "\t"
+	defaultLTSVLabelSeparator = ":"
+)
+
+// ZstdOptions configures the decoder of zstd-compressed input.
+// The zero value uses the defaults of github.com/klauspost/compress/zstd.
+type ZstdOptions struct {
+	// Concurrency is the number of goroutines decoding blocks in parallel.
+	// 1 decodes synchronously, which allocates the least and is the fastest
+	// choice when parsing many small files. Zero uses the library default
+	// of up to 4. A negative value is an error.
+	Concurrency int
+
+	// MaxWindowSize limits the window size, and so the memory, a frame may
+	// require; input that needs a larger window fails to decompress. Zero
+	// uses the library default of 512 MiB.
+	MaxWindowSize uint64
+}
+
+// decoderOptions returns the zstd decoder options for o.
+func (o ZstdOptions) decoderOptions() ([]zstd.DOption, error) {
+	if o.Concurrency < 0 {
+		return nil, fmt.Errorf("invalid zstd concurrency %d", o.Concurrency)
+	}
+
+	var opts []zstd.DOption
+	if o.Concurrency > 0 {
+		opts = append(opts, zstd.WithDecoderConcurrency(o.Concurrency))
+	}
+	if o.MaxWindowSize > 0 {
+		opts = append(opts, zstd.WithDecoderMaxWindow(o.MaxWindowSize))
+	}
+	return opts, nil
+}
+
+// XLSXOptions configures XLSX-specific parsing behavior.
+type XLSXOptions struct {
+	// ExtractHyperlinks adds a "<column>_url" column for every column that
+	// contains hyperlinked cells. The new column holds the link target while
+	// the original column keeps the cell's display text. URL columns are
+	// appended after the sheet's own columns.
+	ExtractHyperlinks bool
+}
+
+// ParseWithOptions reads data from an io.Reader and returns parsed results,
+// applying the behavior configured in opts.
+//
+// Errors found while reading the input are returned as a *ParseError and
+// match ErrEmptyData, ErrUnsupportedType, ErrDuplicateColumn, or
+// ErrDecompression with errors.Is where they apply. Invalid options are
+// reported with a plain error.
+//
+// Example:
+//
+//	opts := fileparser.ParseOptions{
+//	    LinePrefix: regexp.MustCompile(`^(?P<timestamp>\w{3} +\d+ [\d:]+) (?P<host>\S+) `),
+//	}
+//	result, err := fileparser.ParseWithOptions(f, fileparser.LTSV, opts)
+func ParseWithOptions(reader io.Reader, fileType FileType, opts ParseOptions) (result *TableData, err error) {
+	if reader == nil {
+		return nil, errors.New("reader cannot be nil")
+	}
+	if err := opts.Inference.validate(); err != nil {
+		return nil, err
+	}
+	if err := opts.CSVDialect.validate(); err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			err = newParseError(fileType, err)
+		}
+	}()
+
+	if opts.AutoDecompress {
+		reader, fileType, err = sniffCompression(reader, fileType)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Handle decompression
+	decompressedReader, closeFunc, decompErr := createDecompressedReaderWithOptions(reader, fileType, opts.Zstd)
+	if decompErr != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDecompression, decompErr)
+	}
+	if closeFunc != nil {
+		defer func() {
+			if closeErr := closeFunc(); closeErr != nil && err == nil {
+				err = fmt.Errorf("failed to close decompressor: %w", closeErr)
+			}
+		}()
+	}
+
+	baseType := BaseFileType(fileType)
+
+	// Convert legacy character encodings to UTF-8
+	if opts.Encoding != "" && isTextFileType(baseType) {
+		decompressedReader, err = newDecodingReader(decompressedReader, opts.Encoding)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Strip per-line prefixes from text formats
+	var prefixes *linePrefixCaptures
+	if opts.LinePrefix != nil && isTextFileType(baseType) {
+		decompressedReader, prefixes, err = stripLinePrefixes(decompressedReader, opts.LinePrefix)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Parse based on base file type
+	hasHeaderLine := false
+	switch baseType {
+	case CSV:
+		result, hasHeaderLine, err = parseDelimited(decompressedReader, ',', "CSV", opts)
+	case TSV:
+		result, hasHeaderLine, err = parseDelimited(decompressedReader, '\t', "TSV", opts)
+	case LTSV:
+		result, err = parseLTSV(decompressedReader, opts)
+	case Parquet:
+		result, err = parseParquet(decompressedReader, opts)
+	case XLSX:
+		result, err = parseXLSX(decompressedReader, opts)
+	case XML:
+		result, err = parseXML(decompressedReader, opts)
+	case YAML:
+		result, err = parseYAML(decompressedReader, opts)
+	default:
+		return nil, ErrUnsupportedType
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if prefixes != nil {
+		if err := prefixes.addColumns(result, hasHeaderLine, opts); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.TrimSpace && (isTextFileType(baseType) || baseType == XLSX) {
+		trimRecordValues(result)
+	}
+
+	applyColumnTypeOverrides(result, opts.ColumnTypeOverrides)
+
+	if len(opts.PrimaryKey) > 0 {
+		for _, column := range opts.PrimaryKey {
+			if _, ok := result.ColumnIndex(column); !ok {
+				return nil, fmt.Errorf("primary key column %q not found", column)
+			}
+		}
+		result.PrimaryKey = slices.Clone(opts.PrimaryKey)
+	}
+
+	if opts.Strict {
+		if err := result.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid table: %w", err)
+		}
+	}
+
+	return result, nil
+}
+
+// trimRecordValues removes leading and trailing white space from every value
+// of table. Column types need no update because inference ignores the white
+// space around values.
+func trimRecordValues(table *TableData) {
+	for _, record := range table.Records {
+		for i, value := range record {
+			record[i] = strings.TrimSpace(value)
+		}
+	}
+}
+
+// applyColumnTypeOverrides replaces inferred column types with the overrides.
+func applyColumnTypeOverrides(table *TableData, overrides map[string]ColumnType) {
+	if len(overrides) == 0 {
+		return
+	}
+	for i, header := range table.Headers {
+		if colType, ok := overrides[header]; ok && i < len(table.ColumnTypes) {
+			table.ColumnTypes[i] = colType
+		}
+	}
+}
+
+// inferColumnTypes infers the column types of records with o.Inference, or
+// returns TypeText for every column when o.DisableTypeInference is set.
+func (o ParseOptions) inferColumnTypes(headers []string, records [][]string) []ColumnType {
+	if !o.DisableTypeInference {
+		return o.Inference.inferColumnTypes(headers, records)
+	}
+	columnTypes := make([]ColumnType, len(headers))
+	for i := range columnTypes {
+		columnTypes[i] = TypeText
+	}
+	return columnTypes
+}
+
+// reachedMaxRows reports whether n records already satisfy the MaxRows limit.
+func (o ParseOptions) reachedMaxRows(n int) bool {
+	return o.MaxRows > 0 && n >= o.MaxRows
+}
+
+// ltsvSeparators returns the LTSV field and label separators, applying the
+// defaults for empty options.
+func (o ParseOptions) ltsvSeparators() (string, string, error) {
+	fieldSep := cmp.Or(o.LTSVFieldSeparator, defaultLTSVFieldSeparator)
+	labelSep := cmp.Or(o.LTSVLabelSeparator, defaultLTSVLabelSeparator)
+	if strings.ContainsAny(fieldSep+labelSep, "\r\n") {
+		return "", "", errors.New("LTSV separators cannot contain line breaks")
+	}
+	if fieldSep == labelSep {
+		return "", "", fmt.Errorf("LTSV field and label separators must differ, both are %q", fieldSep)
+	}
+	return fieldSep, labelSep, nil
+}
+
+// resolveDuplicateKey returns the value to keep for an LTSV label that has
+// already been seen on the line with value prev.
+func (o ParseOptions) resolveDuplicateKey(prev, value string) string {
+	switch o.LTSVDuplicateKeys {
+	case DuplicateKeyFirst:
+		return prev
+	case DuplicateKeyJoin:
+		return prev + cmp.Or(o.LTSVJoinSeparator, defaultLTSVJoinSeparator) + value
+	default:
+		return value
+	}
+}
+
+// isTextFileType reports whether the base file type is a line-oriented text format.
+func isTextFileType(baseType FileType) bool {
+	return baseType == CSV || baseType == TSV || baseType == LTSV
+}
@@ -0,0 +1,721 @@
+package fileparser
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestParseWithOptions_MaxRows(t *testing.T) {
+	t.Parallel()
+
+	t.Run("limits CSV records", func(t *testing.T) {
+		t.Parallel()
+
+		input := "id,name\n1,a\n2,b\n3,c\n4,d"
+
+		result, err := ParseWithOptions(strings.NewReader(input), CSV, ParseOptions{MaxRows: 2})
+
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{{"1", "a"}, {"2", "b"}}, result.Records)
+	})
+
+	t.Run("infers types from the rows read only", func(t *testing.T) {
+		t.Parallel()
+
+		input := "value\n1\n2\nnot-a-number\nnot-a-number"
+
+		result, err := ParseWithOptions(strings.NewReader(input), CSV, ParseOptions{MaxRows: 2})
+
+		require.NoError(t, err)
+		assert.Equal(t, TypeInteger, result.ColumnTypes[0])
+	})
+
+	t.Run("zero means unlimited", func(t *testing.T) {
+		t.Parallel()
+
+		input := "id\n1\n2\n3"
+
+		result, err := ParseWithOptions(strings.NewReader(input), CSV, ParseOptions{})
+
+		require.NoError(t, err)
+		assert.Len(t, result.Records, 3)
+	})
+
+	t.Run("limits LTSV records", func(t *testing.T) {
+		t.Parallel()
+
+		input := "a:1\tb:x\na:2\tb:y\na:3\tc:z\n"
+
+		result, err := ParseWithOptions(strings.NewReader(input), LTSV, ParseOptions{MaxRows: 2})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a", "b"}, result.Headers)
+		assert.Len(t, result.Records, 2)
+	})
+
+	t.Run("limits Parquet records", func(t *testing.T) {
+		t.Parallel()
+
+		f, err := os.Open(filepath.Join("testdata", "products.parquet"))
+		require.NoError(t, err)
+		defer f.Close()
+
+		result, err := ParseWithOptions(f, Parquet, ParseOptions{MaxRows: 1})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"id", "name", "price"}, result.Headers)
+		require.Len(t, result.Records, 1)
+		assert.Equal(t, "1", result.Records[0][0])
+	})
+
+	t.Run("limits XLSX records", func(t *testing.T) {
+		t.Parallel()
+
+		f := excelize.NewFile()
+		defer f.Close()
+		require.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]any{"id", "name"}))
+		require.NoError(t, f.SetSheetRow("Sheet1", "A2", &[]any{1, "a"}))
+		require.NoError(t, f.SetSheetRow("Sheet1", "A3", &[]any{2, "b"}))
+		require.NoError(t, f.SetSheetRow("Sheet1", "A4", &[]any{3, "c"}))
+		var buf bytes.Buffer
+		require.NoError(t, f.Write(&buf))
+
+		result, err := ParseWithOptions(&buf, XLSX, ParseOptions{MaxRows: 2})
+
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{{"1", "a"}, {"2", "b"}}, result.Records)
+	})
+}
+
+func TestParseWithOptions_ColumnTypeOverrides(t *testing.T) {
+	t.Parallel()
+
+	t.Run("forces zip column to text", func(t *testing.T) {
+		t.Parallel()
+
+		input := "name,zip\nAlice,01234\nBob,98765"
+
+		result, err := ParseWithOptions(strings.NewReader(input), CSV, ParseOptions{
+			ColumnTypeOverrides: map[string]ColumnType{"zip": TypeText},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, []ColumnType{TypeText, TypeText}, result.ColumnTypes)
+		assert.Equal(t, "01234", result.Records[0][1])
+		assert.Equal(t, "01234", ParseValue(result.Records[0][1], result.ColumnTypes[1]))
+	})
+
+	t.Run("inference applies without override", func(t *testing.T) {
+		t.Parallel()
+
+		input := "name,zip\nAlice,01234\nBob,98765"
+
+		result, err := ParseWithOptions(strings.NewReader(input), CSV, ParseOptions{})
+
+		require.NoError(t, err)
+		assert.Equal(t, TypeInteger, result.ColumnTypes[1])
+	})
+
+	t.Run("ignores unknown column names", func(t *testing.T) {
+		t.Parallel()
+
+		input := "id\n1"
+
+		result, err := ParseWithOptions(strings.NewReader(input), CSV, ParseOptions{
+			ColumnTypeOverrides: map[string]ColumnType{"missing": TypeReal},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, []ColumnType{TypeInteger}, result.ColumnTypes)
+	})
+}
+
+func TestParseWithOptions_DisableTypeInference(t *testing.T) {
+	t.Parallel()
+
+	input := "zip,phone,amount,active\n01234,0312345678,10,true\n98765,0898765432,20,false"
+
+	t.Run("gives every column TypeText", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := ParseWithOptions(strings.NewReader(input), CSV, ParseOptions{DisableTypeInference: true})
+
+		require.NoError(t, err)
+		assert.Equal(t, []ColumnType{TypeText, TypeText, TypeText, TypeText}, result.ColumnTypes)
+		assert.Equal(t, "01234", ParseValue(result.Records[0][0], result.ColumnTypes[0]))
+		assert.Equal(t, "0312345678", ParseValue(result.Records[0][1], result.ColumnTypes[1]))
+	})
+
+	t.Run("still applies column type overrides", func(t *testing.T) {
+		t.Parallel()
+
+		opts := ParseOptions{
+			DisableTypeInference: true,
+			ColumnTypeOverrides:  map[string]ColumnType{"amount": TypeInteger},
+		}
+		result, err := ParseWithOptions(strings.NewReader(input), CSV, opts)
+
+		require.NoError(t, err)
+		assert.Equal(t, []ColumnType{TypeText, TypeText, TypeInteger, TypeText}, result.ColumnTypes)
+	})
+
+	t.Run("applies to every format", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := ParseWithOptions(strings.NewReader("id:1\tname:a\n"), LTSV, ParseOptions{DisableTypeInference: true})
+
+		require.NoError(t, err)
+		assert.Equal(t, []ColumnType{TypeText, TypeText}, result.ColumnTypes)
+	})
+}
+
+func TestParseWithOptions_InferenceAllowedTypes(t *testing.T) {
+	t.Parallel()
+
+	input := "name,price,count\nApple,1.99,3\nBanana,0.50,12"
+
+	result, err := ParseWithOptions(strings.NewReader(input), CSV, ParseOptions{
+		Inference: InferenceConfig{AllowedTypes: []ColumnType{TypeText, TypeInteger}},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []ColumnType{TypeText, TypeText, TypeInteger}, result.ColumnTypes)
+}
+
+func TestParseWithOptions_AutoDecompress(t *testing.T) {
+	t.Parallel()
+
+	t.Run("decompresses input passed as a base type", func(t *testing.T) {
+		t.Parallel()
+
+		for _, path := range []string{"testdata/sample.csv.gz", "testdata/sample.csv.z", "testdata/sample.csv.lz4", "testdata/sample.csv.s2"} {
+			t.Run(path, func(t *testing.T) {
+				t.Parallel()
+
+				f, err := os.Open(path)
+				require.NoError(t, err)
+				defer f.Close()
+
+				result, err := ParseWithOptions(f, CSV, ParseOptions{AutoDecompress: true})
+				require.NoError(t, err)
+				assert.NotEmpty(t, result.Records)
+			})
+		}
+	})
+
+	t.Run("detects a different compression than the file type", func(t *testing.T) {
+		t.Parallel()
+
+		f, err := os.Open("testdata/users.csv.zst")
+		require.NoError(t, err)
+		defer f.Close()
+
+		result, err := ParseWithOptions(f, CSVGZ, ParseOptions{AutoDecompress: true})
+		require.NoError(t, err)
+		assert.NotEmpty(t, result.Records)
+	})
+
+	t.Run("reads uncompressed input labeled as compressed", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := ParseWithOptions(strings.NewReader("id,name\n1,Alice"), CSVGZ, ParseOptions{AutoDecompress: true})
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{{"1", "Alice"}}, result.Records)
+	})
+
+	t.Run("reads plain text that resembles a zlib header", func(t *testing.T) {
+		t.Parallel()
+
+		for _, tc := range []struct {
+			input    string
+			fileType FileType
+		}{
+			{"H,value\n1,2\n", CSV},
+			{"80,90\n1,2\n", CSV},
+			{"X\tY\n1\t2\n", TSV},
+		} {
+			result, err := ParseWithOptions(strings.NewReader(tc.input), tc.fileType, ParseOptions{AutoDecompress: true})
+			require.NoError(t, err, tc.input)
+			assert.Len(t, result.Records, 1, tc.input)
+		}
+	})
+
+	t.Run("keeps brotli from the file type", func(t *testing.T) {
+		t.Parallel()
+
+		f, err := os.Open("testdata/sample.csv.br")
+		require.NoError(t, err)
+		defer f.Close()
+
+		result, err := ParseWithOptions(f, CSVBR, ParseOptions{AutoDecompress: true})
+		require.NoError(t, err)
+		assert.NotEmpty(t, result.Records)
+	})
+
+	t.Run("fails without the option", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParseWithOptions(strings.NewReader("id,name\n1,Alice"), CSVGZ, ParseOptions{})
+		require.Error(t, err)
+	})
+}
+
+func TestParseWithOptions_LTSVDuplicateKeys(t *testing.T) {
+	t.Parallel()
+
+	input := "host:a\ttag:x\ttag:y\ttag:z\nhost:b\ttag:w"
+
+	tests := []struct {
+		name string
+		opts ParseOptions
+		want [][]string
+	}{
+		{
+			name: "keeps the last value by default",
+			opts: ParseOptions{},
+			want: [][]string{{"a", "z"}, {"b", "w"}},
+		},
+		{
+			name: "keeps the first value",
+			opts: ParseOptions{LTSVDuplicateKeys: DuplicateKeyFirst},
+			want: [][]string{{"a", "x"}, {"b", "w"}},
+		},
+		{
+			name: "joins values with a comma",
+			opts: ParseOptions{LTSVDuplicateKeys: DuplicateKeyJoin},
+			want: [][]string{{"a", "x,y,z"}, {"b", "w"}},
+		},
+		{
+			name: "joins values with a custom separator",
+			opts: ParseOptions{LTSVDuplicateKeys: DuplicateKeyJoin, LTSVJoinSeparator: "|"},
+			want: [][]string{{"a", "x|y|z"}, {"b", "w"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := ParseWithOptions(strings.NewReader(input), LTSV, tt.opts)
+
+			require.NoError(t, err)
+			assert.Equal(t, []string{"host", "tag"}, result.Headers)
+			assert.Equal(t, tt.want, result.Records)
+		})
+	}
+
+	t.Run("joins tag:a and tag:b into a,b", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := ParseWithOptions(strings.NewReader("tag:a\ttag:b"), LTSV, ParseOptions{
+			LTSVDuplicateKeys: DuplicateKeyJoin,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{{"a,b"}}, result.Records)
+	})
+
+	t.Run("fails on a repeated label with DuplicateKeyError", func(t *testing.T) {
+		t.Parallel()
+
+		input := "a:1\tb:2\n\na:3\ta:4"
+
+		_, err := ParseWithOptions(strings.NewReader(input), LTSV, ParseOptions{
+			LTSVDuplicateKeys: DuplicateKeyError,
+		})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `duplicate LTSV label "a" in record 2`)
+	})
+}
+
+func TestParseWithOptions_LTSVSeparators(t *testing.T) {
+	t.Parallel()
+
+	t.Run("parses multi-character field and label separators", func(t *testing.T) {
+		t.Parallel()
+
+		input := "time => 2024-01-15T10:00:00Z | status => 200 | path => /a|b\n" +
+			"time => 2024-01-15T10:00:01Z | status => 404 | agent => curl"
+
+		result, err := ParseWithOptions(strings.NewReader(input), LTSV, ParseOptions{
+			LTSVFieldSeparator: " | ",
+			LTSVLabelSeparator: " => ",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"time", "status", "path", "agent"}, result.Headers)
+		assert.Equal(t, [][]string{
+			{"2024-01-15T10:00:00Z", "200", "/a|b", ""},
+			{"2024-01-15T10:00:01Z", "404", "", "curl"},
+		}, result.Records)
+		assert.Equal(t, []ColumnType{TypeDatetime, TypeInteger, TypeText, TypeText}, result.ColumnTypes)
+	})
+
+	t.Run("parses space- and comma-separated pairs", func(t *testing.T) {
+		t.Parallel()
+
+		for _, sep := range []string{" ", ","} {
+			input := strings.Join([]string{"host=10.0.0.1", "status=200", "uri=/index.html"}, sep)
+
+			result, err := ParseWithOptions(strings.NewReader(input), LTSV, ParseOptions{
+				LTSVFieldSeparator: sep,
+				LTSVLabelSeparator: "=",
+			})
+
+			require.NoError(t, err)
+			assert.Equal(t, []string{"host", "status", "uri"}, result.Headers)
+			assert.Equal(t, [][]string{{"10.0.0.1", "200", "/index.html"}}, result.Records)
+		}
+	})
+
+	t.Run("keeps colons in values with the default separators", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := ParseWithOptions(strings.NewReader("url:http://example.com\tport:80"), LTSV, ParseOptions{})
+
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{{"http://example.com", "80"}}, result.Records)
+	})
+
+	t.Run("rejects identical separators", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParseWithOptions(strings.NewReader("a=1"), LTSV, ParseOptions{
+			LTSVFieldSeparator: "=",
+			LTSVLabelSeparator: "=",
+		})
+
+		require.Error(t, err)
+	})
+}
+
+func TestParseWithOptions_Strict(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name     string
+		input    string
+		fileType FileType
+	}{
+		{name: "CSV", input: "id,name\n1,Alice\n2,Bob", fileType: CSV},
+		{name: "LTSV with varying keys", input: "id:1\tname:Alice\nid:2\tcity:Tokyo", fileType: LTSV},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := ParseWithOptions(strings.NewReader(tc.input), tc.fileType, ParseOptions{Strict: true})
+
+			require.NoError(t, err)
+			assert.NoError(t, result.Validate())
+		})
+	}
+}
+
+func TestParseWithOptions_FieldsPerRecord(t *testing.T) {
+	t.Parallel()
+
+	input := "id,name,city\n1,Alice\n2,Bob,Tokyo,extra\n3,Carol,Osaka"
+
+	t.Run("rejects ragged rows by default", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParseWithOptions(strings.NewReader(input), CSV, ParseOptions{})
+
+		require.Error(t, err)
+	})
+
+	t.Run("pads and truncates ragged rows when variable fields are allowed", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := ParseWithOptions(strings.NewReader(input), CSV, ParseOptions{FieldsPerRecord: -1})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"id", "name", "city"}, result.Headers)
+		assert.Equal(t, [][]string{
+			{"1", "Alice", ""},
+			{"2", "Bob", "Tokyo"},
+			{"3", "Carol", "Osaka"},
+		}, result.Records)
+		assert.NoError(t, result.Validate())
+	})
+
+	t.Run("pads TSV rows without a header line", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := ParseWithOptions(strings.NewReader("1\t2\t3\n4\t5"), TSV, ParseOptions{
+			FieldsPerRecord: -1,
+			HeaderDetection: HeaderNever,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{{"1", "2", "3"}, {"4", "5", ""}}, result.Records)
+	})
+
+	t.Run("enforces a fixed field count", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParseWithOptions(strings.NewReader("a,b\n1,2"), CSV, ParseOptions{FieldsPerRecord: 3})
+
+		require.Error(t, err)
+	})
+}
+
+func TestParseWithOptions_SkipEmptyLines(t *testing.T) {
+	t.Parallel()
+
+	t.Run("drops whitespace-only lines", func(t *testing.T) {
+		t.Parallel()
+
+		input := "id,name\n1,Alice\n   \n\t\n2,Bob\n \n"
+
+		result, err := ParseWithOptions(strings.NewReader(input), CSV, ParseOptions{SkipEmptyLines: true})
+
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{{"1", "Alice"}, {"2", "Bob"}}, result.Records)
+	})
+
+	t.Run("fails on whitespace-only lines when disabled", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParseWithOptions(strings.NewReader("id,name\n1,Alice\n  \n2,Bob"), CSV, ParseOptions{})
+
+		require.Error(t, err)
+	})
+
+	t.Run("does not produce a record for a trailing blank line", func(t *testing.T) {
+		t.Parallel()
+
+		for _, opts := range []ParseOptions{{}, {SkipEmptyLines: true}} {
+			result, err := ParseWithOptions(strings.NewReader("a\tb\n1\t2\n\n"), TSV, opts)
+
+			require.NoError(t, err)
+			assert.Equal(t, [][]string{{"1", "2"}}, result.Records)
+		}
+	})
+
+	t.Run("keeps blank lines inside quoted fields", func(t *testing.T) {
+		t.Parallel()
+
+		input := "id,note\n1,\"first\n  \nthird \"\"quoted\"\"\"\n  \n2,plain\n"
+
+		result, err := ParseWithOptions(strings.NewReader(input), CSV, ParseOptions{SkipEmptyLines: true})
+
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{
+			{"1", "first\n  \nthird \"quoted\""},
+			{"2", "plain"},
+		}, result.Records)
+	})
+
+	t.Run("drops blank lines after a stray quote with the Excel dialect", func(t *testing.T) {
+		t.Parallel()
+
+		input := "id,size\r1,5\" wide\r  \r2,big\r"
+
+		result, err := ParseWithOptions(strings.NewReader(input), CSV, ParseOptions{SkipEmptyLines: true, CSVDialect: CSVDialectExcel})
+
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{{"1", "5\" wide"}, {"2", "big"}}, result.Records)
+	})
+}
+
+func TestParseWithOptions_DropTrailingEmptyColumn(t *testing.T) {
+	t.Parallel()
+
+	t.Run("drops the column created by a trailing delimiter", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := ParseWithOptions(strings.NewReader("a,b,\n1,2,\n"), CSV, ParseOptions{DropTrailingEmptyColumn: true})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a", "b"}, result.Headers)
+		assert.Equal(t, [][]string{{"1", "2"}}, result.Records)
+		assert.Equal(t, []ColumnType{TypeInteger, TypeInteger}, result.ColumnTypes)
+	})
+
+	t.Run("keeps the empty column when disabled", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := ParseWithOptions(strings.NewReader("a,b,\n1,2,\n"), CSV, ParseOptions{})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a", "b", ""}, result.Headers)
+	})
+
+	t.Run("keeps a trailing column with a name", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := ParseWithOptions(strings.NewReader("a\tb\n1\t\n"), TSV, ParseOptions{DropTrailingEmptyColumn: true})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a", "b"}, result.Headers)
+		assert.Equal(t, [][]string{{"1", ""}}, result.Records)
+	})
+
+	t.Run("drops the column from data rows without a header", func(t *testing.T) {
+		t.Parallel()
+
+		opts := ParseOptions{DropTrailingEmptyColumn: true, HeaderDetection: HeaderNever}
+		result, err := ParseWithOptions(strings.NewReader("1,2,\n3,4,\n"), CSV, opts)
+
+		require.NoError(t, err)
+		assert.Len(t, result.Headers, 2)
+		assert.Equal(t, [][]string{{"1", "2"}, {"3", "4"}}, result.Records)
+	})
+}
+
+func TestParseWithOptions_TrimSpace(t *testing.T) {
+	t.Parallel()
+
+	t.Run("trims CSV and TSV values", func(t *testing.T) {
+		t.Parallel()
+
+		for fileType, input := range map[FileType]string{
+			CSV: "id,name\n 42 ,  Alice\n7,Bob \n",
+			TSV: "id\tname\n 42 \t  Alice\n7\tBob \n",
+		} {
+			result, err := ParseWithOptions(strings.NewReader(input), fileType, ParseOptions{TrimSpace: true})
+
+			require.NoError(t, err)
+			assert.Equal(t, [][]string{{"42", "Alice"}, {"7", "Bob"}}, result.Records, fileType.String())
+			assert.Equal(t, []ColumnType{TypeInteger, TypeText}, result.ColumnTypes, fileType.String())
+		}
+	})
+
+	t.Run("keeps CSV whitespace by default", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := ParseWithOptions(strings.NewReader("id\n 42 \n"), CSV, ParseOptions{})
+
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{{" 42 "}}, result.Records)
+	})
+
+	t.Run("trims XLSX values", func(t *testing.T) {
+		t.Parallel()
+
+		f := excelize.NewFile()
+		defer f.Close()
+		require.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]any{"name"}))
+		require.NoError(t, f.SetSheetRow("Sheet1", "A2", &[]any{"  Alice  "}))
+		var buf bytes.Buffer
+		require.NoError(t, f.Write(&buf))
+
+		result, err := ParseWithOptions(&buf, XLSX, ParseOptions{TrimSpace: true})
+
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{{"Alice"}}, result.Records)
+	})
+}
+
+func TestParseWithOptions_Zstd(t *testing.T) {
+	t.Parallel()
+
+	enc, err := zstd.NewWriter(nil)
+	require.NoError(t, err)
+	data := enc.EncodeAll([]byte("id,name\n1,Alice\n2,Bob\n"), nil)
+	require.NoError(t, enc.Close())
+
+	t.Run("decodes with the given concurrency and window size", func(t *testing.T) {
+		t.Parallel()
+
+		for _, zstdOpts := range []ZstdOptions{{}, {Concurrency: 1}, {Concurrency: 8, MaxWindowSize: 1 << 20}} {
+			result, err := ParseWithOptions(bytes.NewReader(data), CSVZSTD, ParseOptions{Zstd: zstdOpts})
+
+			require.NoError(t, err)
+			assert.Equal(t, [][]string{{"1", "Alice"}, {"2", "Bob"}}, result.Records)
+		}
+	})
+
+	t.Run("returns error for invalid options", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParseWithOptions(bytes.NewReader(data), CSVZSTD, ParseOptions{Zstd: ZstdOptions{Concurrency: -1}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid zstd concurrency -1")
+
+		_, err = ParseWithOptions(bytes.NewReader(data), CSVZSTD, ParseOptions{Zstd: ZstdOptions{MaxWindowSize: 10}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to create zstd reader")
+	})
+
+	t.Run("returns error for invalid zstd input", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParseWithOptions(strings.NewReader("not zstd"), CSVZSTD, ParseOptions{Zstd: ZstdOptions{Concurrency: 1}})
+
+		require.Error(t, err)
+	})
+}
+
+func TestParseWithOptions_InferenceThresholds(t *testing.T) {
+	t.Parallel()
+
+	t.Run("applies the configured confidence", func(t *testing.T) {
+		t.Parallel()
+
+		input := "value\n1\n2\n3\n4\n5\n6\n7\n8\n9\nn/a\n"
+		opts := ParseOptions{Inference: InferenceConfig{Confidence: 0.95}}
+
+		result, err := ParseWithOptions(strings.NewReader(input), CSV, opts)
+
+		require.NoError(t, err)
+		assert.Equal(t, []ColumnType{TypeText}, result.ColumnTypes)
+	})
+
+	t.Run("returns error for an invalid confidence", func(t *testing.T) {
+		t.Parallel()
+
+		opts := ParseOptions{Inference: InferenceConfig{Confidence: 2}}
+
+		_, err := ParseWithOptions(strings.NewReader("a\n1\n"), CSV, opts)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid inference confidence")
+	})
+}
+
+func TestParseWithOptions_PrimaryKey(t *testing.T) {
+	t.Parallel()
+
+	t.Run("sets the primary key of the table", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := ParseWithOptions(strings.NewReader("id,name\n1,Alice\n2,Bob"), CSV, ParseOptions{PrimaryKey: []string{"id"}})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"id"}, result.PrimaryKey)
+	})
+
+	t.Run("returns error for an unknown column", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParseWithOptions(strings.NewReader("id,name\n1,Alice"), CSV, ParseOptions{PrimaryKey: []string{"code"}})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `primary key column "code" not found`)
+	})
+
+	t.Run("rejects duplicate keys in strict mode", func(t *testing.T) {
+		t.Parallel()
+
+		input := "id,name\n1,Alice\n1,Bob"
+
+		_, err := ParseWithOptions(strings.NewReader(input), CSV, ParseOptions{PrimaryKey: []string{"id"}, Strict: true})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "same primary key")
+
+		result, err := ParseWithOptions(strings.NewReader(input), CSV, ParseOptions{PrimaryKey: []string{"id"}})
+		require.NoError(t, err)
+		assert.Len(t, result.Records, 2)
+	})
+}
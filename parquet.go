@@ -1,15 +1,24 @@
 package fileparser
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"math"
+	"math/big"
+	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/apache/arrow/go/v18/arrow"
 	"github.com/apache/arrow/go/v18/arrow/array"
+	"github.com/apache/arrow/go/v18/arrow/memory"
+	"github.com/apache/arrow/go/v18/parquet"
 	pqfile "github.com/apache/arrow/go/v18/parquet/file"
+	"github.com/apache/arrow/go/v18/parquet/metadata"
 	"github.com/apache/arrow/go/v18/parquet/pqarrow"
 )
 
@@ -68,84 +77,162 @@ func (b *bytesReaderAt) Seek(offset int64, whence int) (int64, error) {
 	return newOffset, nil
 }
 
-// parseParquet parses Parquet data from reader.
-func parseParquet(reader io.Reader) (*TableData, error) {
+// readAll reads the remaining data of reader into memory. Data that is
+// already in memory, as with ParseBytes, is returned without copying.
+func readAll(reader io.Reader) ([]byte, error) {
+	if b, ok := reader.(*bytesReaderAt); ok {
+		if b.offset >= int64(len(b.data)) {
+			return []byte{}, nil
+		}
+		data := b.data[b.offset:]
+		b.offset = int64(len(b.data))
+		return data, nil
+	}
+	return io.ReadAll(reader)
+}
+
+// parquetSource returns random access to the Parquet data of reader. A
+// reader that already supports random access, such as *os.File, is used
+// directly when it is positioned at its start, so the Parquet reader seeks
+// within it instead of the data being read into memory. Other readers are
+// read into memory.
+func parquetSource(reader io.Reader) (parquet.ReaderAtSeeker, error) {
+	if rs, ok := reader.(parquet.ReaderAtSeeker); ok {
+		if pos, err := rs.Seek(0, io.SeekCurrent); err == nil && pos == 0 {
+			return checkParquetSource(rs)
+		}
+	}
+
 	// Read all data into memory (Parquet requires random access)
-	data, err := io.ReadAll(reader)
+	data, err := readAll(reader)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read parquet data: %w", err)
 	}
-
 	if len(data) == 0 {
-		return nil, errors.New("empty parquet file")
+		return nil, withKind(errors.New("empty parquet file"), ErrEmptyData)
+	}
+	if err := checkBinaryInput(data, parquetMagic, "Parquet"); err != nil {
+		return nil, fmt.Errorf("failed to create parquet reader: %w", err)
+	}
+	return &bytesReaderAt{data: data}, nil
+}
+
+// checkParquetSource checks that rs is not empty and does not look like text,
+// reading only its leading bytes.
+func checkParquetSource(rs parquet.ReaderAtSeeker) (parquet.ReaderAtSeeker, error) {
+	size, err := rs.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read parquet data: %w", err)
+	}
+	if _, err := rs.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("failed to read parquet data: %w", err)
+	}
+	if size == 0 {
+		return nil, withKind(errors.New("empty parquet file"), ErrEmptyData)
 	}
 
-	// Create a bytes reader for the parquet data
-	bytesReader := &bytesReaderAt{data: data}
+	// One byte more than looksLikeText samples, so it can tell a cut-off
+	// character from invalid data
+	head := make([]byte, min(size, textSniffSize+1))
+	if _, err := rs.ReadAt(head, 0); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to read parquet data: %w", err)
+	}
+	if err := checkBinaryInput(head, parquetMagic, "Parquet"); err != nil {
+		return nil, fmt.Errorf("failed to create parquet reader: %w", err)
+	}
+
+	// Hide Close so that closing the Parquet reader leaves the caller's file open
+	return struct{ parquet.ReaderAtSeeker }{rs}, nil
+}
+
+// parquetMagic is the magic number at the start and end of every Parquet file.
+var parquetMagic = []byte("PAR1")
+
+// parquetBatchSize is the number of rows read per record batch.
+const parquetBatchSize = 64 * 1024
 
-	// Create parquet file reader from bytes
-	pqReader, err := pqfile.NewParquetReader(bytesReader)
+// parseParquet parses Parquet data from reader.
+// Nested list, struct, and map columns are flattened to JSON strings.
+func parseParquet(reader io.Reader, opts ParseOptions) (*TableData, error) {
+	source, err := parquetSource(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	pqReader, err := pqfile.NewParquetReader(source)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create parquet reader: %w", err)
 	}
 	defer pqReader.Close()
 
-	// Create arrow file reader
-	arrowReader, err := pqarrow.NewFileReader(pqReader, pqarrow.ArrowReadProperties{}, nil)
+	// Create arrow file reader. Nested columns need an allocator to build
+	// their offset and validity buffers.
+	arrowReader, err := pqarrow.NewFileReader(pqReader, pqarrow.ArrowReadProperties{BatchSize: parquetBatchSize}, memory.DefaultAllocator)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create arrow reader: %w", err)
 	}
 
-	// Read all record batches using the table reader approach
-	table, err := arrowReader.ReadTable(context.Background())
+	// Only decode the requested columns
+	leafIndices, err := parquetColumnIndices(arrowReader, opts.Columns)
+	if err != nil {
+		return nil, err
+	}
+
+	// Optionally restrict reading to a single row group
+	rowGroups, err := parquetRowGroups(pqReader, opts.RowGroup)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read table: %w", err)
+		return nil, err
 	}
-	defer table.Release()
+
+	// Read record batches lazily so that MaxRows can stop early
+	recordReader, err := arrowReader.GetRecordReader(context.Background(), leafIndices, rowGroups)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create record reader: %w", err)
+	}
+	defer recordReader.Release()
 
 	// Extract headers from schema
-	schema := table.Schema()
+	schema := recordReader.Schema()
 	headers := make([]string, schema.NumFields())
 	for i, field := range schema.Fields() {
 		headers[i] = field.Name
 	}
 
-	if table.NumRows() == 0 {
-		return &TableData{
-			Headers:     headers,
-			Records:     [][]string{},
-			ColumnTypes: make([]ColumnType, len(headers)),
-		}, nil
+	// Map output columns to batch columns; projected columns follow the requested order
+	columnOrder := make([]int, len(headers))
+	for i := range columnOrder {
+		columnOrder[i] = i
+	}
+	if len(opts.Columns) > 0 {
+		for i, name := range opts.Columns {
+			columnOrder[i] = schema.FieldIndices(name)[0]
+		}
+		headers = slices.Clone(opts.Columns)
 	}
 
-	// Read data by converting table to record batches
-	tableReader := array.NewTableReader(table, 0)
-	defer tableReader.Release()
-
-	var records [][]string
-	for tableReader.Next() {
-		batch := tableReader.Record()
+	records := make([][]string, 0)
+	for !opts.reachedMaxRows(len(records)) && recordReader.Next() {
+		batch := recordReader.Record()
 
 		// Convert each row in the batch
 		numRows := batch.NumRows()
-		for i := range numRows {
-			row := make([]string, batch.NumCols())
-			for j, col := range batch.Columns() {
-				row[j] = extractValueFromArrowArray(col, i)
+		for i := int64(0); i < numRows && !opts.reachedMaxRows(len(records)); i++ {
+			row := make([]string, len(columnOrder))
+			for j, colIdx := range columnOrder {
+				row[j] = extractValueFromArrowArray(batch.Column(colIdx), i)
 			}
 			records = append(records, row)
 		}
-
-		// Release the batch to free memory immediately
-		batch.Release()
 	}
 
-	if err := tableReader.Err(); err != nil {
+	if err := recordReader.Err(); err != nil && !errors.Is(err, io.EOF) {
 		return nil, fmt.Errorf("error reading table records: %w", err)
 	}
 
-	// Infer column types from the string records
-	columnTypes := inferColumnTypes(headers, records)
+	// Infer column types from the string records; boolean columns were read
+	// as "true" and "false"
+	opts.Inference.InferBooleans = true
+	columnTypes := opts.inferColumnTypes(headers, records)
 
 	return &TableData{
 		Headers:     headers,
@@ -154,6 +241,233 @@ func parseParquet(reader io.Reader) (*TableData, error) {
 	}, nil
 }
 
+// parquetRowGroups returns the row group indices selected by the one-based
+// rowGroup option, or nil to read every row group.
+func parquetRowGroups(pqReader *pqfile.Reader, rowGroup int) ([]int, error) {
+	if rowGroup == 0 {
+		return nil, nil
+	}
+	if numRowGroups := pqReader.NumRowGroups(); rowGroup < 0 || rowGroup > numRowGroups {
+		return nil, fmt.Errorf("row group %d out of range (file has %d row groups)", rowGroup, numRowGroups)
+	}
+	return []int{rowGroup - 1}, nil
+}
+
+// ParquetNumRowGroups returns the number of row groups in the Parquet data of
+// the given size readable from r. Only the footer metadata is read, so callers
+// can fan out over row groups with ParseOptions.RowGroup.
+//
+// Example:
+//
+//	f, _ := os.Open("huge.parquet")
+//	info, _ := f.Stat()
+//	n, err := fileparser.ParquetNumRowGroups(f, info.Size())
+//	for i := 1; i <= n; i++ {
+//	    // parse row group i in its own goroutine with ParseOptions{RowGroup: i}
+//	}
+func ParquetNumRowGroups(r io.ReaderAt, size int64) (int, error) {
+	if r == nil {
+		return 0, errors.New("reader cannot be nil")
+	}
+	if size <= 0 {
+		return 0, withKind(errors.New("empty parquet file"), ErrEmptyData)
+	}
+
+	pqReader, err := pqfile.NewParquetReader(io.NewSectionReader(r, 0, size))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create parquet reader: %w", err)
+	}
+	defer pqReader.Close()
+
+	return pqReader.NumRowGroups(), nil
+}
+
+// parquetColumnIndices returns the leaf column indices to read for the named
+// top-level columns, or nil to read every column. Nested columns expand to all
+// of their leaves.
+func parquetColumnIndices(arrowReader *pqarrow.FileReader, columns []string) ([]int, error) {
+	if len(columns) == 0 {
+		return nil, nil
+	}
+	if err := validateColumnNames(columns); err != nil {
+		return nil, err
+	}
+
+	schema, err := arrowReader.Schema()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read parquet schema: %w", err)
+	}
+
+	fieldIndices := make([]int, 0, len(columns))
+	for _, name := range columns {
+		indices := schema.FieldIndices(name)
+		if len(indices) == 0 {
+			available := make([]string, schema.NumFields())
+			for i, field := range schema.Fields() {
+				available[i] = field.Name
+			}
+			return nil, fmt.Errorf("column %q not found in parquet schema (available columns: %s)",
+				name, strings.Join(available, ", "))
+		}
+		fieldIndices = append(fieldIndices, indices[0])
+	}
+
+	leafIndices, err := arrowReader.Manifest.GetFieldIndices(fieldIndices)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve parquet columns: %w", err)
+	}
+	return leafIndices, nil
+}
+
+// ColStats holds the statistics of a Parquet column aggregated over all row groups.
+type ColStats struct {
+	// Min is the smallest value in the column, or nil if unknown.
+	// Values use the same Go types as ParseValue: int64 for integer columns,
+	// float64 for floating point columns, string for text columns, plus bool
+	// for boolean columns.
+	Min any
+	// Max is the largest value in the column, or nil if unknown.
+	Max any
+	// NullCount is the number of null values in the column.
+	NullCount int64
+	// HasMinMax reports whether every row group recorded min/max statistics.
+	// When false, Min and Max must not be used to skip data.
+	HasMinMax bool
+}
+
+// ParquetStatistics reads per-column min/max/null-count statistics from the
+// footer metadata of Parquet data without decoding any rows. Consumers can use
+// them for predicate pushdown, e.g. to skip files that cannot contain a value,
+// before calling Parse.
+//
+// The returned map is keyed by column path; nested fields are joined with ".".
+// Statistics of the row groups are merged, so Min and Max cover the whole file.
+func ParquetStatistics(reader io.Reader) (map[string]ColStats, error) {
+	if reader == nil {
+		return nil, errors.New("reader cannot be nil")
+	}
+
+	source, err := parquetSource(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	pqReader, err := pqfile.NewParquetReader(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create parquet reader: %w", err)
+	}
+	defer pqReader.Close()
+
+	fileMeta := pqReader.MetaData()
+	result := make(map[string]ColStats, fileMeta.Schema.NumColumns())
+	for col := range fileMeta.Schema.NumColumns() {
+		descr := fileMeta.Schema.Column(col)
+		merged := metadata.NewStatistics(descr, nil)
+		hasMinMax := pqReader.NumRowGroups() > 0
+
+		for rg := range pqReader.NumRowGroups() {
+			chunk, err := fileMeta.RowGroup(rg).ColumnChunk(col)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read column chunk metadata: %w", err)
+			}
+			stats, err := chunk.Statistics()
+			if err != nil {
+				return nil, fmt.Errorf("failed to read column statistics: %w", err)
+			}
+			if stats == nil || !stats.HasMinMax() {
+				hasMinMax = false
+			}
+			if stats != nil {
+				merged.Merge(stats)
+			}
+		}
+
+		colStats := ColStats{NullCount: merged.NullCount()}
+		if hasMinMax && merged.HasMinMax() {
+			colStats.Min, colStats.Max = statisticsMinMax(merged)
+			colStats.HasMinMax = colStats.Min != nil
+		}
+		result[descr.Path()] = colStats
+	}
+
+	return result, nil
+}
+
+// ColumnSchema describes a top-level column of a Parquet file.
+type ColumnSchema struct {
+	// Name is the name of the column.
+	Name string
+	// ArrowType is the Arrow type the column is read as, e.g. "int64",
+	// "utf8", or "list<element: int32, nullable>".
+	ArrowType string
+	// Nullable reports whether the column may hold nulls.
+	Nullable bool
+}
+
+// ParquetSchema returns the columns of Parquet data, read from the footer
+// metadata without decoding any rows, e.g. to preview the columns of a file
+// before parsing it. When reader is an io.ReaderAt and io.Seeker, such as an
+// *os.File, only the footer is read; other readers are read into memory.
+//
+// Example:
+//
+//	f, _ := os.Open("data.parquet")
+//	defer f.Close()
+//	columns, err := fileparser.ParquetSchema(f)
+func ParquetSchema(reader io.Reader) ([]ColumnSchema, error) {
+	if reader == nil {
+		return nil, errors.New("reader cannot be nil")
+	}
+
+	source, err := parquetSource(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	pqReader, err := pqfile.NewParquetReader(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create parquet reader: %w", err)
+	}
+	defer pqReader.Close()
+
+	fileMeta := pqReader.MetaData()
+	schema, err := pqarrow.FromParquet(fileMeta.Schema, &pqarrow.ArrowReadProperties{}, fileMeta.KeyValueMetadata())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read parquet schema: %w", err)
+	}
+
+	columns := make([]ColumnSchema, schema.NumFields())
+	for i, field := range schema.Fields() {
+		columns[i] = ColumnSchema{
+			Name:      field.Name,
+			ArrowType: field.Type.String(),
+			Nullable:  field.Nullable,
+		}
+	}
+	return columns, nil
+}
+
+// statisticsMinMax converts typed Parquet statistics to Go values.
+// It returns nil for physical types without a natural Go representation.
+func statisticsMinMax(stats metadata.TypedStatistics) (minValue, maxValue any) {
+	switch s := stats.(type) {
+	case *metadata.BooleanStatistics:
+		return s.Min(), s.Max()
+	case *metadata.Int32Statistics:
+		return int64(s.Min()), int64(s.Max())
+	case *metadata.Int64Statistics:
+		return s.Min(), s.Max()
+	case *metadata.Float32Statistics:
+		return float64(s.Min()), float64(s.Max())
+	case *metadata.Float64Statistics:
+		return s.Min(), s.Max()
+	case *metadata.ByteArrayStatistics:
+		return string(s.Min()), string(s.Max())
+	default:
+		return nil, nil
+	}
+}
+
 // extractValueFromArrowArray extracts a value from an Arrow array at the given index.
 func extractValueFromArrowArray(arr arrow.Array, index int64) string {
 	if arr.IsNull(int(index)) {
@@ -186,15 +500,20 @@ func extractValueFromArrowArray(arr arrow.Array, index int64) string {
 		return strconv.FormatUint(a.Value(int(index)), 10)
 
 	case *array.Float32:
-		return fmt.Sprintf("%g", a.Value(int(index)))
+		return formatFloat(float64(a.Value(int(index))), 32)
 	case *array.Float64:
-		return fmt.Sprintf("%g", a.Value(int(index)))
+		return formatFloat(a.Value(int(index)), 64)
 
 	case *array.String:
 		return a.Value(int(index))
 	case *array.Binary:
 		return string(a.Value(int(index)))
 
+	case *array.Decimal128:
+		return formatDecimal(a.Value(int(index)).BigInt(), decimalScale(a.DataType()))
+	case *array.Decimal256:
+		return formatDecimal(a.Value(int(index)).BigInt(), decimalScale(a.DataType()))
+
 	case *array.Date32:
 		days := a.Value(int(index))
 		return fmt.Sprintf("%d", days)
@@ -206,7 +525,139 @@ func extractValueFromArrowArray(arr arrow.Array, index int64) string {
 		ts := a.Value(int(index))
 		return fmt.Sprintf("%d", ts)
 
+	case *array.Map, *array.Struct, array.ListLike:
+		var sb strings.Builder
+		writeNestedJSON(&sb, arr, int(index))
+		return sb.String()
+
 	default:
 		return fmt.Sprintf("%v", arr.GetOneForMarshal(int(index)))
 	}
 }
+
+// formatFloat formats a floating-point value the way it is usually written in
+// CSV, e.g. "1000000" rather than "1e+06", using the fewest digits that
+// round-trip at the given bit size. Values below 1e-6 or from 1e21 in
+// magnitude use exponent notation to keep them short.
+func formatFloat(f float64, bitSize int) string {
+	if abs := math.Abs(f); abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		return strconv.FormatFloat(f, 'g', -1, bitSize)
+	}
+	return strconv.FormatFloat(f, 'f', -1, bitSize)
+}
+
+// writeNestedJSON writes the value of a list, struct, or map column at index
+// as compact JSON. Lists become arrays, structs become objects with fields in
+// schema order, and maps become objects with keys in stored order. Numbers
+// and booleans are written as JSON literals; all other leaf values are written
+// as strings formatted like top-level columns.
+func writeNestedJSON(sb *strings.Builder, arr arrow.Array, index int) {
+	if arr.IsNull(index) {
+		sb.WriteString("null")
+		return
+	}
+
+	switch a := arr.(type) {
+	case *array.Map:
+		start, end := a.ValueOffsets(index)
+		sb.WriteByte('{')
+		for i := start; i < end; i++ {
+			if i > start {
+				sb.WriteByte(',')
+			}
+			sb.WriteString(quoteJSON(extractValueFromArrowArray(a.Keys(), i)))
+			sb.WriteByte(':')
+			writeNestedJSON(sb, a.Items(), int(i))
+		}
+		sb.WriteByte('}')
+
+	case *array.Struct:
+		structType, ok := a.DataType().(*arrow.StructType)
+		if !ok {
+			sb.WriteString("null")
+			return
+		}
+		sb.WriteByte('{')
+		for j, field := range structType.Fields() {
+			if j > 0 {
+				sb.WriteByte(',')
+			}
+			sb.WriteString(quoteJSON(field.Name))
+			sb.WriteByte(':')
+			writeNestedJSON(sb, a.Field(j), index)
+		}
+		sb.WriteByte('}')
+
+	case array.ListLike:
+		start, end := a.ValueOffsets(index)
+		sb.WriteByte('[')
+		for i := start; i < end; i++ {
+			if i > start {
+				sb.WriteByte(',')
+			}
+			writeNestedJSON(sb, a.ListValues(), int(i))
+		}
+		sb.WriteByte(']')
+
+	case *array.Boolean, *array.Int8, *array.Int16, *array.Int32, *array.Int64,
+		*array.Uint8, *array.Uint16, *array.Uint32, *array.Uint64,
+		*array.Decimal128, *array.Decimal256:
+		sb.WriteString(extractValueFromArrowArray(arr, int64(index)))
+
+	case *array.Float32, *array.Float64:
+		value := extractValueFromArrowArray(arr, int64(index))
+		if f, err := strconv.ParseFloat(value, 64); err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+			// NaN and infinities have no JSON literal
+			sb.WriteString(quoteJSON(value))
+			return
+		}
+		sb.WriteString(value)
+
+	default:
+		sb.WriteString(quoteJSON(extractValueFromArrowArray(arr, int64(index))))
+	}
+}
+
+// quoteJSON returns s as a JSON string literal without HTML escaping.
+func quoteJSON(s string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(s); err != nil {
+		return strconv.Quote(s)
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// decimalScale returns the scale of a DECIMAL data type, or 0 for other types.
+func decimalScale(dt arrow.DataType) int32 {
+	if decimalType, ok := dt.(arrow.DecimalType); ok {
+		return decimalType.GetScale()
+	}
+	return 0
+}
+
+// formatDecimal formats the unscaled value of a DECIMAL as a fixed-point
+// string with scale fractional digits, e.g. 123456 with scale 2 is "1234.56".
+// The conversion is exact for any precision.
+func formatDecimal(unscaled *big.Int, scale int32) string {
+	if scale <= 0 {
+		digits := unscaled.String()
+		if unscaled.Sign() == 0 {
+			return digits
+		}
+		return digits + strings.Repeat("0", int(-scale))
+	}
+
+	digits := new(big.Int).Abs(unscaled).String()
+	if pad := int(scale) + 1 - len(digits); pad > 0 {
+		digits = strings.Repeat("0", pad) + digits
+	}
+	point := len(digits) - int(scale)
+
+	sign := ""
+	if unscaled.Sign() < 0 {
+		sign = "-"
+	}
+	return sign + digits[:point] + "." + digits[point:]
+}
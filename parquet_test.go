@@ -3,12 +3,17 @@ package fileparser
 import (
 	"bytes"
 	"io"
+	"math"
+	"math/big"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/apache/arrow/go/v18/arrow"
 	"github.com/apache/arrow/go/v18/arrow/array"
+	"github.com/apache/arrow/go/v18/arrow/decimal128"
+	"github.com/apache/arrow/go/v18/arrow/decimal256"
 	"github.com/apache/arrow/go/v18/arrow/memory"
 	"github.com/apache/arrow/go/v18/parquet"
 	"github.com/apache/arrow/go/v18/parquet/pqarrow"
@@ -31,7 +36,7 @@ func TestParseParquet(t *testing.T) {
 		require.NoError(t, err)
 		defer f.Close()
 
-		result, err := parseParquet(f)
+		result, err := parseParquet(f, ParseOptions{})
 
 		require.NoError(t, err)
 		assert.Equal(t, []string{"id", "name", "price"}, result.Headers)
@@ -46,7 +51,7 @@ func TestParseParquet(t *testing.T) {
 
 		reader := bytes.NewReader([]byte{})
 
-		_, err := parseParquet(reader)
+		_, err := parseParquet(reader, ParseOptions{})
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "empty parquet file")
@@ -57,7 +62,7 @@ func TestParseParquet(t *testing.T) {
 
 		reader := bytes.NewReader([]byte("not a parquet file"))
 
-		_, err := parseParquet(reader)
+		_, err := parseParquet(reader, ParseOptions{})
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to create parquet reader")
@@ -291,6 +296,22 @@ func TestExtractValueFromArrowArray(t *testing.T) {
 		assert.Equal(t, "2.71", extractValueFromArrowArray(arr, 1))
 	})
 
+	t.Run("formats float64 values without exponent like CSV", func(t *testing.T) {
+		t.Parallel()
+
+		builder := array.NewFloat64Builder(pool)
+		defer builder.Release()
+		builder.AppendValues([]float64{1000000, 1234567.5, 0.0001, 1e-7, 1e21}, nil)
+		arr := builder.NewArray()
+		defer arr.Release()
+
+		assert.Equal(t, "1000000", extractValueFromArrowArray(arr, 0))
+		assert.Equal(t, "1234567.5", extractValueFromArrowArray(arr, 1))
+		assert.Equal(t, "0.0001", extractValueFromArrowArray(arr, 2))
+		assert.Equal(t, "1e-07", extractValueFromArrowArray(arr, 3))
+		assert.Equal(t, "1e+21", extractValueFromArrowArray(arr, 4))
+	})
+
 	t.Run("extracts boolean value", func(t *testing.T) {
 		t.Parallel()
 
@@ -471,6 +492,228 @@ func TestExtractValueFromArrowArray(t *testing.T) {
 		assert.Equal(t, "1641024000000", extractValueFromArrowArray(arr, 0))
 		assert.Equal(t, "1641110400000", extractValueFromArrowArray(arr, 1))
 	})
+
+	t.Run("extracts decimal128 value with scale", func(t *testing.T) {
+		t.Parallel()
+
+		builder := array.NewDecimal128Builder(pool, &arrow.Decimal128Type{Precision: 10, Scale: 2})
+		defer builder.Release()
+		builder.AppendValues([]decimal128.Num{
+			decimal128.FromI64(123456),
+			decimal128.FromI64(-5),
+			decimal128.FromI64(0),
+		}, nil)
+		arr := builder.NewArray()
+		defer arr.Release()
+
+		assert.Equal(t, "1234.56", extractValueFromArrowArray(arr, 0))
+		assert.Equal(t, "-0.05", extractValueFromArrowArray(arr, 1))
+		assert.Equal(t, "0.00", extractValueFromArrowArray(arr, 2))
+	})
+
+	t.Run("extracts decimal256 value without precision loss", func(t *testing.T) {
+		t.Parallel()
+
+		unscaled, ok := new(big.Int).SetString("123456789012345678901234567890123456789012345", 10)
+		require.True(t, ok)
+		builder := array.NewDecimal256Builder(pool, &arrow.Decimal256Type{Precision: 50, Scale: 5})
+		defer builder.Release()
+		builder.Append(decimal256.FromBigInt(unscaled))
+		arr := builder.NewArray()
+		defer arr.Release()
+
+		assert.Equal(t, "1234567890123456789012345678901234567890.12345", extractValueFromArrowArray(arr, 0))
+	})
+}
+
+func TestExtractValueFromArrowArray_Nested(t *testing.T) {
+	t.Parallel()
+
+	pool := memory.NewGoAllocator()
+
+	t.Run("serializes list as JSON array", func(t *testing.T) {
+		t.Parallel()
+
+		builder := array.NewListBuilder(pool, arrow.PrimitiveTypes.Int64)
+		defer builder.Release()
+		values := builder.ValueBuilder().(*array.Int64Builder)
+		builder.Append(true)
+		values.AppendValues([]int64{1, 2, 3}, nil)
+		builder.Append(true)
+		builder.AppendNull()
+		arr := builder.NewArray()
+		defer arr.Release()
+
+		assert.Equal(t, "[1,2,3]", extractValueFromArrowArray(arr, 0))
+		assert.Equal(t, "[]", extractValueFromArrowArray(arr, 1))
+		assert.Equal(t, "", extractValueFromArrowArray(arr, 2))
+	})
+
+	t.Run("serializes struct as JSON object in field order", func(t *testing.T) {
+		t.Parallel()
+
+		builder := array.NewStructBuilder(pool, arrow.StructOf(
+			arrow.Field{Name: "b", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+			arrow.Field{Name: "a", Type: arrow.BinaryTypes.String, Nullable: true},
+		))
+		defer builder.Release()
+		builder.Append(true)
+		builder.FieldBuilder(0).(*array.Int64Builder).Append(1)
+		builder.FieldBuilder(1).(*array.StringBuilder).Append(`say "hi" <b>`)
+		builder.Append(true)
+		builder.FieldBuilder(0).(*array.Int64Builder).AppendNull()
+		builder.FieldBuilder(1).(*array.StringBuilder).Append("x")
+		arr := builder.NewArray()
+		defer arr.Release()
+
+		assert.Equal(t, `{"b":1,"a":"say \"hi\" <b>"}`, extractValueFromArrowArray(arr, 0))
+		assert.Equal(t, `{"b":null,"a":"x"}`, extractValueFromArrowArray(arr, 1))
+	})
+
+	t.Run("serializes map as JSON object", func(t *testing.T) {
+		t.Parallel()
+
+		builder := array.NewMapBuilder(pool, arrow.BinaryTypes.String, arrow.PrimitiveTypes.Float64, false)
+		defer builder.Release()
+		keys := builder.KeyBuilder().(*array.StringBuilder)
+		items := builder.ItemBuilder().(*array.Float64Builder)
+		builder.Append(true)
+		keys.AppendValues([]string{"z", "a"}, nil)
+		items.AppendValues([]float64{1.5, math.NaN()}, nil)
+		arr := builder.NewArray()
+		defer arr.Release()
+
+		assert.Equal(t, `{"z":1.5,"a":"NaN"}`, extractValueFromArrowArray(arr, 0))
+	})
+
+	t.Run("serializes list of structs", func(t *testing.T) {
+		t.Parallel()
+
+		builder := array.NewListBuilder(pool, arrow.StructOf(
+			arrow.Field{Name: "ok", Type: arrow.FixedWidthTypes.Boolean},
+		))
+		defer builder.Release()
+		structs := builder.ValueBuilder().(*array.StructBuilder)
+		builder.Append(true)
+		structs.Append(true)
+		structs.FieldBuilder(0).(*array.BooleanBuilder).Append(true)
+		structs.Append(true)
+		structs.FieldBuilder(0).(*array.BooleanBuilder).Append(false)
+		arr := builder.NewArray()
+		defer arr.Release()
+
+		assert.Equal(t, `[{"ok":true},{"ok":false}]`, extractValueFromArrowArray(arr, 0))
+	})
+}
+
+func TestParseParquet_NestedColumns(t *testing.T) {
+	t.Parallel()
+
+	pool := memory.NewGoAllocator()
+	listType := arrow.ListOf(arrow.PrimitiveTypes.Int64)
+	structType := arrow.StructOf(
+		arrow.Field{Name: "city", Type: arrow.BinaryTypes.String, Nullable: true},
+		arrow.Field{Name: "zip", Type: arrow.PrimitiveTypes.Int32, Nullable: true},
+	)
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "tags", Type: listType, Nullable: true},
+		{Name: "address", Type: structType, Nullable: true},
+	}, nil)
+
+	ids := array.NewInt64Builder(pool)
+	defer ids.Release()
+	ids.AppendValues([]int64{1, 2}, nil)
+
+	tags := array.NewListBuilder(pool, arrow.PrimitiveTypes.Int64)
+	defer tags.Release()
+	tags.Append(true)
+	tags.ValueBuilder().(*array.Int64Builder).AppendValues([]int64{1, 2, 3}, nil)
+	tags.AppendNull()
+
+	address := array.NewStructBuilder(pool, structType)
+	defer address.Release()
+	address.Append(true)
+	address.FieldBuilder(0).(*array.StringBuilder).Append("Tokyo")
+	address.FieldBuilder(1).(*array.Int32Builder).Append(100)
+	address.Append(true)
+	address.FieldBuilder(0).(*array.StringBuilder).Append("Osaka")
+	address.FieldBuilder(1).(*array.Int32Builder).AppendNull()
+
+	columns := []arrow.Array{ids.NewArray(), tags.NewArray(), address.NewArray()}
+	for _, column := range columns {
+		defer column.Release()
+	}
+	record := array.NewRecord(schema, columns, 2)
+	defer record.Release()
+	table := array.NewTableFromRecords(schema, []arrow.Record{record})
+	defer table.Release()
+
+	var buf bytes.Buffer
+	require.NoError(t, pqarrow.WriteTable(table, &buf, 1024, parquet.NewWriterProperties(), pqarrow.DefaultWriterProps()))
+
+	result, err := parseParquet(bytes.NewReader(buf.Bytes()), ParseOptions{})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"id", "tags", "address"}, result.Headers)
+	assert.Equal(t, [][]string{
+		{"1", "[1,2,3]", `{"city":"Tokyo","zip":100}`},
+		{"2", "", `{"city":"Osaka","zip":null}`},
+	}, result.Records)
+}
+
+func TestFormatDecimal(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		unscaled int64
+		scale    int32
+		want     string
+	}{
+		{name: "positive with scale", unscaled: 123456, scale: 2, want: "1234.56"},
+		{name: "negative with scale", unscaled: -123456, scale: 2, want: "-1234.56"},
+		{name: "value smaller than one", unscaled: 7, scale: 3, want: "0.007"},
+		{name: "negative value smaller than one", unscaled: -7, scale: 3, want: "-0.007"},
+		{name: "zero keeps scale", unscaled: 0, scale: 2, want: "0.00"},
+		{name: "zero scale", unscaled: 42, scale: 0, want: "42"},
+		{name: "negative scale", unscaled: 42, scale: -2, want: "4200"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, formatDecimal(big.NewInt(tt.unscaled), tt.scale))
+		})
+	}
+}
+
+func TestParseParquet_Decimal(t *testing.T) {
+	t.Parallel()
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "price", Type: &arrow.Decimal128Type{Precision: 10, Scale: 2}, Nullable: true},
+	}, nil)
+	pool := memory.NewGoAllocator()
+	builder := array.NewDecimal128Builder(pool, &arrow.Decimal128Type{Precision: 10, Scale: 2})
+	defer builder.Release()
+	builder.AppendValues([]decimal128.Num{decimal128.FromI64(123456), decimal128.FromI64(-199)}, nil)
+	builder.AppendNull()
+	arr := builder.NewArray()
+	defer arr.Release()
+	record := array.NewRecord(schema, []arrow.Array{arr}, 3)
+	defer record.Release()
+	table := array.NewTableFromRecords(schema, []arrow.Record{record})
+	defer table.Release()
+
+	var buf bytes.Buffer
+	require.NoError(t, pqarrow.WriteTable(table, &buf, 1024, parquet.NewWriterProperties(), pqarrow.DefaultWriterProps()))
+
+	result, err := parseParquet(bytes.NewReader(buf.Bytes()), ParseOptions{})
+
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"1234.56"}, {"-1.99"}, {""}}, result.Records)
 }
 
 func TestParseParquet_WithGeneratedData(t *testing.T) {
@@ -517,7 +760,7 @@ func TestParseParquet_WithGeneratedData(t *testing.T) {
 		require.NoError(t, err)
 
 		// Parse the parquet data
-		result, err := parseParquet(bytes.NewReader(buf.Bytes()))
+		result, err := parseParquet(bytes.NewReader(buf.Bytes()), ParseOptions{})
 
 		require.NoError(t, err)
 		assert.Equal(t, []string{"col1", "col2"}, result.Headers)
@@ -576,7 +819,7 @@ func TestParseParquet_WithGeneratedData(t *testing.T) {
 		err := pqarrow.WriteTable(table, &buf, 1024, props, arrProps)
 		require.NoError(t, err)
 
-		result, err := parseParquet(bytes.NewReader(buf.Bytes()))
+		result, err := parseParquet(bytes.NewReader(buf.Bytes()), ParseOptions{})
 
 		require.NoError(t, err)
 		assert.Equal(t, []string{"int_col", "str_col", "float_col", "bool_col"}, result.Headers)
@@ -619,7 +862,7 @@ func TestParseParquet_WithGeneratedData(t *testing.T) {
 		err := pqarrow.WriteTable(table, &buf, 1024, props, arrProps)
 		require.NoError(t, err)
 
-		result, err := parseParquet(bytes.NewReader(buf.Bytes()))
+		result, err := parseParquet(bytes.NewReader(buf.Bytes()), ParseOptions{})
 
 		require.NoError(t, err)
 		assert.Equal(t, 3, len(result.Records))
@@ -651,3 +894,378 @@ func TestParse_Parquet(t *testing.T) {
 		assert.Equal(t, 3, len(result.Records))
 	})
 }
+
+func TestParquetStatistics(t *testing.T) {
+	t.Parallel()
+
+	t.Run("reports min and max of numeric columns", func(t *testing.T) {
+		t.Parallel()
+
+		f, err := os.Open(filepath.Join("testdata", "products.parquet"))
+		require.NoError(t, err)
+		defer f.Close()
+
+		stats, err := ParquetStatistics(f)
+
+		require.NoError(t, err)
+		require.Contains(t, stats, "id")
+		assert.True(t, stats["id"].HasMinMax)
+		assert.Equal(t, int64(1), stats["id"].Min)
+		assert.Equal(t, int64(3), stats["id"].Max)
+		assert.Equal(t, int64(0), stats["id"].NullCount)
+		assert.InDelta(t, 29.99, stats["price"].Min, 1e-9)
+		assert.InDelta(t, 999.99, stats["price"].Max, 1e-9)
+		assert.Equal(t, "Keyboard", stats["name"].Min)
+		assert.Equal(t, "Mouse", stats["name"].Max)
+	})
+
+	t.Run("merges statistics across row groups", func(t *testing.T) {
+		t.Parallel()
+
+		schema := arrow.NewSchema([]arrow.Field{
+			{Name: "value", Type: arrow.PrimitiveTypes.Int32, Nullable: true},
+		}, nil)
+
+		pool := memory.NewGoAllocator()
+		builder := array.NewInt32Builder(pool)
+		defer builder.Release()
+		builder.AppendValues([]int32{5, -7, 0, 42, 3}, []bool{true, true, false, true, true})
+		arr := builder.NewArray()
+		defer arr.Release()
+
+		record := array.NewRecord(schema, []arrow.Array{arr}, 5)
+		defer record.Release()
+		table := array.NewTableFromRecords(schema, []arrow.Record{record})
+		defer table.Release()
+
+		var buf bytes.Buffer
+		// A chunk size of 2 rows produces three row groups
+		err := pqarrow.WriteTable(table, &buf, 2, parquet.NewWriterProperties(), pqarrow.DefaultWriterProps())
+		require.NoError(t, err)
+
+		stats, err := ParquetStatistics(bytes.NewReader(buf.Bytes()))
+
+		require.NoError(t, err)
+		assert.Equal(t, ColStats{Min: int64(-7), Max: int64(42), NullCount: 1, HasMinMax: true}, stats["value"])
+	})
+
+	t.Run("returns error for nil reader", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParquetStatistics(nil)
+		require.Error(t, err)
+	})
+
+	t.Run("returns error for empty data", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParquetStatistics(bytes.NewReader(nil))
+		require.Error(t, err)
+	})
+}
+
+func TestParquetSchema(t *testing.T) {
+	t.Parallel()
+
+	t.Run("reads column names, types, and nullability", func(t *testing.T) {
+		t.Parallel()
+
+		schema := arrow.NewSchema([]arrow.Field{
+			{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+			{Name: "name", Type: arrow.BinaryTypes.String, Nullable: true},
+			{Name: "tags", Type: arrow.ListOf(arrow.PrimitiveTypes.Int32), Nullable: true},
+		}, nil)
+
+		builder := array.NewRecordBuilder(memory.NewGoAllocator(), schema)
+		defer builder.Release()
+		builder.Field(0).(*array.Int64Builder).Append(1)
+		builder.Field(1).(*array.StringBuilder).Append("Laptop")
+		tags := builder.Field(2).(*array.ListBuilder)
+		tags.Append(true)
+		tags.ValueBuilder().(*array.Int32Builder).Append(7)
+		record := builder.NewRecord()
+		defer record.Release()
+		table := array.NewTableFromRecords(schema, []arrow.Record{record})
+		defer table.Release()
+
+		var buf bytes.Buffer
+		require.NoError(t, pqarrow.WriteTable(table, &buf, 1, parquet.NewWriterProperties(), pqarrow.DefaultWriterProps()))
+
+		columns, err := ParquetSchema(bytes.NewReader(buf.Bytes()))
+
+		require.NoError(t, err)
+		require.Len(t, columns, 3)
+		assert.Equal(t, ColumnSchema{Name: "id", ArrowType: "int64", Nullable: false}, columns[0])
+		assert.Equal(t, ColumnSchema{Name: "name", ArrowType: "utf8", Nullable: true}, columns[1])
+		assert.Equal(t, "tags", columns[2].Name)
+		assert.True(t, strings.HasPrefix(columns[2].ArrowType, "list<"), columns[2].ArrowType)
+	})
+
+	t.Run("reads the footer of a file", func(t *testing.T) {
+		t.Parallel()
+
+		f, err := os.Open(filepath.Join("testdata", "products.parquet"))
+		require.NoError(t, err)
+		defer f.Close()
+
+		columns, err := ParquetSchema(f)
+
+		require.NoError(t, err)
+		names := make([]string, len(columns))
+		for i, column := range columns {
+			names[i] = column.Name
+		}
+		assert.Contains(t, names, "id")
+		assert.Contains(t, names, "price")
+	})
+
+	t.Run("returns error for nil reader and empty data", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParquetSchema(nil)
+		require.Error(t, err)
+
+		_, err = ParquetSchema(bytes.NewReader(nil))
+		require.ErrorIs(t, err, ErrEmptyData)
+	})
+}
+
+func TestParseParquet_Columns(t *testing.T) {
+	t.Parallel()
+
+	t.Run("reads only requested columns in requested order", func(t *testing.T) {
+		t.Parallel()
+
+		f, err := os.Open(filepath.Join("testdata", "products.parquet"))
+		require.NoError(t, err)
+		defer f.Close()
+
+		result, err := parseParquet(f, ParseOptions{Columns: []string{"price", "id"}})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"price", "id"}, result.Headers)
+		assert.Equal(t, [][]string{{"999.99", "1"}, {"29.99", "2"}, {"79.99", "3"}}, result.Records)
+		assert.Equal(t, []ColumnType{TypeReal, TypeInteger}, result.ColumnTypes)
+	})
+
+	t.Run("combines with MaxRows", func(t *testing.T) {
+		t.Parallel()
+
+		f, err := os.Open(filepath.Join("testdata", "products.parquet"))
+		require.NoError(t, err)
+		defer f.Close()
+
+		result, err := ParseWithOptions(f, Parquet, ParseOptions{Columns: []string{"name"}, MaxRows: 2})
+
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{{"Laptop"}, {"Mouse"}}, result.Records)
+	})
+
+	t.Run("returns error for unknown column", func(t *testing.T) {
+		t.Parallel()
+
+		f, err := os.Open(filepath.Join("testdata", "products.parquet"))
+		require.NoError(t, err)
+		defer f.Close()
+
+		_, err = parseParquet(f, ParseOptions{Columns: []string{"id", "missing"}})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `column "missing" not found`)
+		assert.Contains(t, err.Error(), "id, name, price")
+	})
+
+	t.Run("returns error for duplicate column", func(t *testing.T) {
+		t.Parallel()
+
+		f, err := os.Open(filepath.Join("testdata", "products.parquet"))
+		require.NoError(t, err)
+		defer f.Close()
+
+		_, err = parseParquet(f, ParseOptions{Columns: []string{"id", "id"}})
+
+		require.Error(t, err)
+	})
+}
+
+func TestParseParquet_TextInput(t *testing.T) {
+	t.Parallel()
+
+	t.Run("CSV bytes yield guidance error", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := parseParquet(bytes.NewReader([]byte("id,name\n1,Alice\n")), ParseOptions{})
+
+		require.ErrorIs(t, err, ErrNotBinaryFormat)
+		assert.Contains(t, err.Error(), "does not look like Parquet")
+		assert.Contains(t, err.Error(), "it may be CSV")
+	})
+
+	t.Run("BOM-prefixed TSV yields guidance error", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParquetStatistics(bytes.NewReader([]byte("\ufeffid\tname\n1\tAlice\n")))
+
+		require.ErrorIs(t, err, ErrNotBinaryFormat)
+		assert.Contains(t, err.Error(), "it may be TSV")
+	})
+
+	t.Run("corrupt binary data keeps the reader error", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := parseParquet(bytes.NewReader([]byte{0x00, 0x01, 0x02, 0xff}), ParseOptions{})
+
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrNotBinaryFormat)
+	})
+}
+
+func TestParquetRowGroups(t *testing.T) {
+	t.Parallel()
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+	}, nil)
+	pool := memory.NewGoAllocator()
+	builder := array.NewInt64Builder(pool)
+	defer builder.Release()
+	builder.AppendValues([]int64{1, 2, 3, 4, 5}, nil)
+	arr := builder.NewArray()
+	defer arr.Release()
+	record := array.NewRecord(schema, []arrow.Array{arr}, 5)
+	defer record.Release()
+	table := array.NewTableFromRecords(schema, []arrow.Record{record})
+	defer table.Release()
+
+	var buf bytes.Buffer
+	// A chunk size of 2 rows produces row groups {1,2}, {3,4}, {5}
+	require.NoError(t, pqarrow.WriteTable(table, &buf, 2, parquet.NewWriterProperties(), pqarrow.DefaultWriterProps()))
+	data := buf.Bytes()
+
+	t.Run("counts row groups", func(t *testing.T) {
+		t.Parallel()
+
+		n, err := ParquetNumRowGroups(bytes.NewReader(data), int64(len(data)))
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, n)
+	})
+
+	t.Run("reads a single row group", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := ParseWithOptions(bytes.NewReader(data), Parquet, ParseOptions{RowGroup: 2})
+
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{{"3"}, {"4"}}, result.Records)
+	})
+
+	t.Run("reads each row group concurrently", func(t *testing.T) {
+		t.Parallel()
+
+		n, err := ParquetNumRowGroups(bytes.NewReader(data), int64(len(data)))
+		require.NoError(t, err)
+
+		results := make([][][]string, n)
+		errs := make(chan error, n)
+		for i := range n {
+			go func() {
+				result, err := ParseWithOptions(bytes.NewReader(data), Parquet, ParseOptions{RowGroup: i + 1})
+				if err == nil {
+					results[i] = result.Records
+				}
+				errs <- err
+			}()
+		}
+		for range n {
+			require.NoError(t, <-errs)
+		}
+		assert.Equal(t, [][][]string{{{"1"}, {"2"}}, {{"3"}, {"4"}}, {{"5"}}}, results)
+	})
+
+	t.Run("zero reads all row groups", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := ParseWithOptions(bytes.NewReader(data), Parquet, ParseOptions{})
+
+		require.NoError(t, err)
+		assert.Len(t, result.Records, 5)
+	})
+
+	t.Run("returns error for out of range row group", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParseWithOptions(bytes.NewReader(data), Parquet, ParseOptions{RowGroup: 4})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "row group 4 out of range")
+
+		_, err = ParseWithOptions(bytes.NewReader(data), Parquet, ParseOptions{RowGroup: -1})
+		require.Error(t, err)
+	})
+
+	t.Run("returns error for invalid input", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParquetNumRowGroups(nil, 10)
+		require.Error(t, err)
+
+		_, err = ParquetNumRowGroups(bytes.NewReader(nil), 0)
+		require.Error(t, err)
+
+		garbage := []byte("not a parquet file")
+		_, err = ParquetNumRowGroups(bytes.NewReader(garbage), int64(len(garbage)))
+		require.Error(t, err)
+	})
+}
+
+func TestParseParquet_ReaderAtSeeker(t *testing.T) {
+	t.Parallel()
+
+	t.Run("reads a file in place and leaves it open", func(t *testing.T) {
+		t.Parallel()
+
+		f, err := os.Open(filepath.Join("testdata", "products.parquet"))
+		require.NoError(t, err)
+		defer f.Close()
+
+		result, err := Parse(f, Parquet)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"id", "name", "price"}, result.Headers)
+		assert.Len(t, result.Records, 3)
+		_, err = f.Seek(0, io.SeekStart)
+		assert.NoError(t, err, "the caller's file must stay open")
+	})
+
+	t.Run("reads from the current position of a seeked reader", func(t *testing.T) {
+		t.Parallel()
+
+		data, err := os.ReadFile(filepath.Join("testdata", "products.parquet"))
+		require.NoError(t, err)
+		reader := bytes.NewReader(append([]byte("junk"), data...))
+		_, err = reader.Seek(4, io.SeekStart)
+		require.NoError(t, err)
+
+		result, err := Parse(reader, Parquet)
+
+		require.NoError(t, err)
+		assert.Len(t, result.Records, 3)
+	})
+
+	t.Run("rejects text input", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Parse(bytes.NewReader([]byte("id,name\n1,Alice\n")), Parquet)
+
+		require.ErrorIs(t, err, ErrNotBinaryFormat)
+	})
+
+	t.Run("returns error for an empty file", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Parse(bytes.NewReader(nil), Parquet)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "empty parquet file")
+	})
+}
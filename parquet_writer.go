@@ -0,0 +1,196 @@
+package fileparser
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/apache/arrow/go/v18/arrow"
+	"github.com/apache/arrow/go/v18/arrow/array"
+	"github.com/apache/arrow/go/v18/arrow/memory"
+	"github.com/apache/arrow/go/v18/parquet"
+	"github.com/apache/arrow/go/v18/parquet/compress"
+	"github.com/apache/arrow/go/v18/parquet/pqarrow"
+)
+
+// ParquetCodec is the compression codec of Parquet output.
+type ParquetCodec int
+
+const (
+	// ParquetCodecSnappy compresses Parquet pages with Snappy, the default of
+	// common Parquet writers such as pyarrow.
+	ParquetCodecSnappy ParquetCodec = iota
+	// ParquetCodecUncompressed writes Parquet pages without compression.
+	ParquetCodecUncompressed
+	// ParquetCodecGzip compresses Parquet pages with gzip.
+	ParquetCodecGzip
+	// ParquetCodecZstd compresses Parquet pages with Zstandard.
+	ParquetCodecZstd
+)
+
+// String returns the name of the codec.
+func (c ParquetCodec) String() string {
+	switch c {
+	case ParquetCodecSnappy:
+		return "snappy"
+	case ParquetCodecUncompressed:
+		return "uncompressed"
+	case ParquetCodecGzip:
+		return "gzip"
+	case ParquetCodecZstd:
+		return "zstd"
+	default:
+		return "unknown"
+	}
+}
+
+// Compression levels accepted by WriteOptions.ParquetCompressionLevel and
+// WriteOptions.CompressionLevel
+const (
+	minGzipLevel = 1
+	maxGzipLevel = 9
+	minZstdLevel = 1
+	maxZstdLevel = 22
+)
+
+// parquetWriterProperties returns the Parquet writer properties for opts.
+// An out of range compression level is an error rather than being clamped.
+func parquetWriterProperties(opts WriteOptions) (*parquet.WriterProperties, error) {
+	level := opts.ParquetCompressionLevel
+
+	var codec compress.Compression
+	switch opts.ParquetCodec {
+	case ParquetCodecSnappy:
+		codec = compress.Codecs.Snappy
+	case ParquetCodecUncompressed:
+		codec = compress.Codecs.Uncompressed
+	case ParquetCodecGzip:
+		codec = compress.Codecs.Gzip
+		if level != 0 && (level < minGzipLevel || level > maxGzipLevel) {
+			return nil, fmt.Errorf("invalid gzip compression level %d: must be between %d and %d", level, minGzipLevel, maxGzipLevel)
+		}
+	case ParquetCodecZstd:
+		codec = compress.Codecs.Zstd
+		if level != 0 && (level < minZstdLevel || level > maxZstdLevel) {
+			return nil, fmt.Errorf("invalid zstd compression level %d: must be between %d and %d", level, minZstdLevel, maxZstdLevel)
+		}
+	default:
+		return nil, fmt.Errorf("unknown Parquet codec %d", opts.ParquetCodec)
+	}
+
+	props := []parquet.WriterProperty{parquet.WithCompression(codec)}
+	if level != 0 {
+		if opts.ParquetCodec != ParquetCodecGzip && opts.ParquetCodec != ParquetCodecZstd {
+			return nil, fmt.Errorf("compression level is not supported for the %s codec", opts.ParquetCodec)
+		}
+		props = append(props, parquet.WithCompressionLevel(level))
+	}
+	return parquet.NewWriterProperties(props...), nil
+}
+
+// parquetColumnType returns the Arrow type a column of colType is written as.
+// Datetime columns are written as strings to keep their original format.
+func parquetColumnType(colType ColumnType) arrow.DataType {
+	switch colType {
+	case TypeInteger:
+		return arrow.PrimitiveTypes.Int64
+	case TypeReal:
+		return arrow.PrimitiveTypes.Float64
+	case TypeBoolean:
+		return arrow.FixedWidthTypes.Boolean
+	default:
+		return arrow.BinaryTypes.String
+	}
+}
+
+// writeParquet writes table to w as a Parquet file with one row group.
+// Integer, real, and boolean columns are written with the matching Parquet
+// type and empty values become nulls; other columns are written as strings.
+func writeParquet(w io.Writer, table *TableData, opts WriteOptions) error {
+	if len(table.Headers) == 0 {
+		return errors.New("cannot write Parquet without columns")
+	}
+	props, err := parquetWriterProperties(opts)
+	if err != nil {
+		return err
+	}
+
+	fields := make([]arrow.Field, len(table.Headers))
+	for i, header := range table.Headers {
+		colType := TypeText
+		if i < len(table.ColumnTypes) {
+			colType = table.ColumnTypes[i]
+		}
+		fields[i] = arrow.Field{Name: header, Type: parquetColumnType(colType), Nullable: true}
+	}
+	schema := arrow.NewSchema(fields, nil)
+
+	builder := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+	defer builder.Release()
+
+	for row, record := range table.Records {
+		for i, field := range fields {
+			var value string
+			if i < len(record) {
+				value = record[i]
+			}
+			if err := appendParquetValue(builder.Field(i), value); err != nil {
+				return fmt.Errorf("failed to write Parquet: row %d, column %q: %w", row+1, field.Name, err)
+			}
+		}
+	}
+
+	rec := builder.NewRecord()
+	defer rec.Release()
+
+	// Hide Close so that the Parquet writer does not close the caller's writer
+	fw, err := pqarrow.NewFileWriter(schema, struct{ io.Writer }{w}, props, pqarrow.DefaultWriterProps())
+	if err != nil {
+		return fmt.Errorf("failed to write Parquet: %w", err)
+	}
+	if err := fw.Write(rec); err != nil {
+		_ = fw.Close()
+		return fmt.Errorf("failed to write Parquet: %w", err)
+	}
+	if err := fw.Close(); err != nil {
+		return fmt.Errorf("failed to write Parquet: %w", err)
+	}
+	return nil
+}
+
+// appendParquetValue appends value to b, converting it to the type of b.
+func appendParquetValue(b array.Builder, value string) error {
+	if sb, ok := b.(*array.StringBuilder); ok {
+		sb.Append(value)
+		return nil
+	}
+	if value == "" {
+		b.AppendNull()
+		return nil
+	}
+
+	switch builder := b.(type) {
+	case *array.Int64Builder:
+		v, ok := parseInteger(value)
+		if !ok {
+			return fmt.Errorf("invalid integer %q", value)
+		}
+		builder.Append(v)
+	case *array.Float64Builder:
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("invalid real %q", value)
+		}
+		builder.Append(v)
+	case *array.BooleanBuilder:
+		v, ok := ParseValue(value, TypeBoolean).(bool)
+		if !ok {
+			return fmt.Errorf("invalid boolean %q", value)
+		}
+		builder.Append(v)
+	default:
+		return fmt.Errorf("unsupported Parquet column type %s", b.Type())
+	}
+	return nil
+}
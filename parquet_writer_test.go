@@ -0,0 +1,95 @@
+package fileparser
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrite_Parquet(t *testing.T) {
+	t.Parallel()
+
+	table := &TableData{
+		Headers: []string{"id", "name", "price", "active"},
+		Records: [][]string{
+			{"1", "Laptop", "999.5", "true"},
+			{"2", "Mouse", "", "false"},
+		},
+		ColumnTypes: []ColumnType{TypeInteger, TypeText, TypeReal, TypeBoolean},
+	}
+
+	t.Run("round-trips through Parse", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		require.NoError(t, Write(&buf, table, Parquet, WriteOptions{}))
+
+		result, err := Parse(&buf, Parquet)
+
+		require.NoError(t, err)
+		assert.Equal(t, table.Headers, result.Headers)
+		assert.Equal(t, table.Records, result.Records)
+		assert.Equal(t, []ColumnType{TypeInteger, TypeText, TypeReal, TypeBoolean}, result.ColumnTypes)
+	})
+
+	t.Run("writes every codec and level", func(t *testing.T) {
+		t.Parallel()
+
+		for _, opts := range []WriteOptions{
+			{ParquetCodec: ParquetCodecUncompressed},
+			{ParquetCodec: ParquetCodecGzip},
+			{ParquetCodec: ParquetCodecGzip, ParquetCompressionLevel: 9},
+			{ParquetCodec: ParquetCodecZstd},
+			{ParquetCodec: ParquetCodecZstd, ParquetCompressionLevel: 19},
+		} {
+			var buf bytes.Buffer
+			require.NoError(t, Write(&buf, table, Parquet, opts), "%s level %d", opts.ParquetCodec, opts.ParquetCompressionLevel)
+
+			result, err := Parse(&buf, Parquet)
+
+			require.NoError(t, err)
+			assert.Equal(t, table.Records, result.Records)
+		}
+	})
+
+	t.Run("rejects invalid compression levels", func(t *testing.T) {
+		t.Parallel()
+
+		testCases := []struct {
+			opts    WriteOptions
+			message string
+		}{
+			{WriteOptions{ParquetCodec: ParquetCodecGzip, ParquetCompressionLevel: 10}, "invalid gzip compression level 10"},
+			{WriteOptions{ParquetCodec: ParquetCodecZstd, ParquetCompressionLevel: -1}, "invalid zstd compression level -1"},
+			{WriteOptions{ParquetCompressionLevel: 3}, "not supported for the snappy codec"},
+			{WriteOptions{ParquetCodec: ParquetCodec(99)}, "unknown Parquet codec"},
+		}
+
+		for _, tc := range testCases {
+			var buf bytes.Buffer
+			err := Write(&buf, table, Parquet, tc.opts)
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.message)
+			assert.Zero(t, buf.Len())
+		}
+	})
+
+	t.Run("returns error for values that do not match the column type", func(t *testing.T) {
+		t.Parallel()
+
+		bad := &TableData{
+			Headers:     []string{"id"},
+			Records:     [][]string{{"1"}, {"two"}},
+			ColumnTypes: []ColumnType{TypeInteger},
+		}
+
+		err := Write(&bytes.Buffer{}, bad, Parquet, WriteOptions{})
+
+		require.Error(t, err)
+		assert.True(t, strings.Contains(err.Error(), `row 2, column "id": invalid integer "two"`), err.Error())
+	})
+}
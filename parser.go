@@ -1,6 +1,6 @@
 // Package fileparser provides file parsing functionality for various tabular data formats.
 // It supports CSV, TSV, LTSV, XLSX, and Parquet files, with optional compression
-// (gzip, bzip2, xz, zstd).
+// (gzip, bzip2, xz, zstd, zlib, snappy, s2, lz4, brotli).
 //
 // This package can be used by filesql, fileprep, fileframe, or any application
 // that needs to parse tabular data files.
@@ -37,6 +37,8 @@
 package fileparser
 
 import (
+	"bufio"
+	"bytes"
 	"compress/bzip2"
 	"compress/gzip"
 	"compress/zlib"
@@ -47,6 +49,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/andybalholm/brotli"
 	"github.com/klauspost/compress/s2"
 	"github.com/klauspost/compress/snappy"
 	"github.com/klauspost/compress/zstd"
@@ -158,6 +161,59 @@ const (
 	// XLSXLZ4 represents lz4-compressed XLSX file type.
 	XLSXLZ4
 
+	// CSVBR represents brotli-compressed CSV file type.
+	CSVBR
+	// TSVBR represents brotli-compressed TSV file type.
+	TSVBR
+	// LTSVBR represents brotli-compressed LTSV file type.
+	LTSVBR
+	// ParquetBR represents brotli-compressed Parquet file type.
+	ParquetBR
+	// XLSXBR represents brotli-compressed XLSX file type.
+	XLSXBR
+
+	// XML represents XML file type.
+	XML
+	// XMLGZ represents gzip-compressed XML file type.
+	XMLGZ
+	// XMLBZ2 represents bzip2-compressed XML file type.
+	XMLBZ2
+	// XMLXZ represents xz-compressed XML file type.
+	XMLXZ
+	// XMLZSTD represents zstd-compressed XML file type.
+	XMLZSTD
+	// XMLZLIB represents zlib-compressed XML file type.
+	XMLZLIB
+	// XMLSNAPPY represents snappy-compressed XML file type.
+	XMLSNAPPY
+	// XMLS2 represents s2-compressed XML file type.
+	XMLS2
+	// XMLLZ4 represents lz4-compressed XML file type.
+	XMLLZ4
+	// XMLBR represents brotli-compressed XML file type.
+	XMLBR
+
+	// YAML represents YAML file type.
+	YAML
+	// YAMLGZ represents gzip-compressed YAML file type.
+	YAMLGZ
+	// YAMLBZ2 represents bzip2-compressed YAML file type.
+	YAMLBZ2
+	// YAMLXZ represents xz-compressed YAML file type.
+	YAMLXZ
+	// YAMLZSTD represents zstd-compressed YAML file type.
+	YAMLZSTD
+	// YAMLZLIB represents zlib-compressed YAML file type.
+	YAMLZLIB
+	// YAMLSNAPPY represents snappy-compressed YAML file type.
+	YAMLSNAPPY
+	// YAMLS2 represents s2-compressed YAML file type.
+	YAMLS2
+	// YAMLLZ4 represents lz4-compressed YAML file type.
+	YAMLLZ4
+	// YAMLBR represents brotli-compressed YAML file type.
+	YAMLBR
+
 	// Unsupported represents unsupported file type.
 	Unsupported
 )
@@ -255,6 +311,56 @@ func (ft FileType) String() string {
 		return "Parquet (lz4)"
 	case XLSXLZ4:
 		return "XLSX (lz4)"
+	case CSVBR:
+		return "CSV (brotli)"
+	case TSVBR:
+		return "TSV (brotli)"
+	case LTSVBR:
+		return "LTSV (brotli)"
+	case ParquetBR:
+		return "Parquet (brotli)"
+	case XLSXBR:
+		return "XLSX (brotli)"
+	case XML:
+		return "XML"
+	case XMLGZ:
+		return "XML (gzip)"
+	case XMLBZ2:
+		return "XML (bzip2)"
+	case XMLXZ:
+		return "XML (xz)"
+	case XMLZSTD:
+		return "XML (zstd)"
+	case XMLZLIB:
+		return "XML (zlib)"
+	case XMLSNAPPY:
+		return "XML (snappy)"
+	case XMLS2:
+		return "XML (s2)"
+	case XMLLZ4:
+		return "XML (lz4)"
+	case XMLBR:
+		return "XML (brotli)"
+	case YAML:
+		return "YAML"
+	case YAMLGZ:
+		return "YAML (gzip)"
+	case YAMLBZ2:
+		return "YAML (bzip2)"
+	case YAMLXZ:
+		return "YAML (xz)"
+	case YAMLZSTD:
+		return "YAML (zstd)"
+	case YAMLZLIB:
+		return "YAML (zlib)"
+	case YAMLSNAPPY:
+		return "YAML (snappy)"
+	case YAMLS2:
+		return "YAML (s2)"
+	case YAMLLZ4:
+		return "YAML (lz4)"
+	case YAMLBR:
+		return "YAML (brotli)"
 	default:
 		return "Unsupported"
 	}
@@ -272,6 +378,8 @@ const (
 	TypeReal
 	// TypeDatetime represents datetime column type.
 	TypeDatetime
+	// TypeBoolean represents boolean column type holding true or false values.
+	TypeBoolean
 )
 
 // String returns the string representation of ColumnType.
@@ -285,6 +393,8 @@ func (ct ColumnType) String() string {
 		return "REAL"
 	case TypeDatetime:
 		return "DATETIME"
+	case TypeBoolean:
+		return "BOOLEAN"
 	default:
 		return "TEXT"
 	}
@@ -299,6 +409,12 @@ type TableData struct {
 	// ColumnTypes contains the inferred types for each column.
 	// The length matches Headers.
 	ColumnTypes []ColumnType
+	// PrimaryKey optionally names the columns whose values together identify
+	// a record. Validate checks that no two records share a key, and
+	// DistinctBy uses it when called without columns. It is nil unless set
+	// by ParseOptions.PrimaryKey, a caller, or a converter such as the ach
+	// package.
+	PrimaryKey []string
 }
 
 // Parse reads data from an io.Reader and returns parsed results.
@@ -309,40 +425,47 @@ type TableData struct {
 //	f, _ := os.Open("data.csv.gz")
 //	defer f.Close()
 //	result, err := fileparser.Parse(f, fileparser.CSVGZ)
-func Parse(reader io.Reader, fileType FileType) (result *TableData, err error) {
+func Parse(reader io.Reader, fileType FileType) (*TableData, error) {
+	return ParseWithOptions(reader, fileType, ParseOptions{})
+}
+
+// ParseBytes parses data that is already in memory, e.g. a response body
+// read from HTTP or object storage. It is equivalent to Parse with a
+// bytes.Reader, except that uncompressed Parquet and XLSX data is used in
+// place instead of being copied into a second buffer. data must not be
+// modified while ParseBytes runs.
+//
+// Example:
+//
+//	body, _ := io.ReadAll(resp.Body)
+//	result, err := fileparser.ParseBytes(body, fileparser.Parquet)
+func ParseBytes(data []byte, fileType FileType) (*TableData, error) {
+	return Parse(&bytesReaderAt{data: data}, fileType)
+}
+
+// ParseTee parses data like Parse and also returns the exact bytes read from reader.
+// For compressed file types the returned bytes are the compressed input.
+// This allows validating an upload and storing the original bytes without
+// reading the input twice.
+func ParseTee(reader io.Reader, fileType FileType) (*TableData, []byte, error) {
 	if reader == nil {
-		return nil, errors.New("reader cannot be nil")
+		return nil, nil, errors.New("reader cannot be nil")
 	}
 
-	// Handle decompression
-	decompressedReader, closeFunc, decompErr := createDecompressedReader(reader, fileType)
-	if decompErr != nil {
-		return nil, fmt.Errorf("failed to decompress: %w", decompErr)
-	}
-	if closeFunc != nil {
-		defer func() {
-			if closeErr := closeFunc(); closeErr != nil && err == nil {
-				err = fmt.Errorf("failed to close decompressor: %w", closeErr)
-			}
-		}()
+	var buf bytes.Buffer
+	teeReader := io.TeeReader(reader, &buf)
+
+	result, err := Parse(teeReader, fileType)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	// Parse based on base file type
-	baseType := BaseFileType(fileType)
-	switch baseType {
-	case CSV:
-		return parseDelimited(decompressedReader, ',', "CSV")
-	case TSV:
-		return parseDelimited(decompressedReader, '\t', "TSV")
-	case LTSV:
-		return parseLTSV(decompressedReader)
-	case Parquet:
-		return parseParquet(decompressedReader)
-	case XLSX:
-		return parseXLSX(decompressedReader)
-	default:
-		return nil, errors.New("unsupported file type")
+	// Consume any trailing bytes the parser did not need
+	if _, err := io.Copy(io.Discard, teeReader); err != nil {
+		return nil, nil, fmt.Errorf("failed to read remaining data: %w", err)
 	}
+
+	return result, buf.Bytes(), nil
 }
 
 // File extensions
@@ -352,6 +475,9 @@ const (
 	ExtLTSV    = ".ltsv"
 	ExtParquet = ".parquet"
 	ExtXLSX    = ".xlsx"
+	ExtYAML    = ".yaml"
+	ExtYML     = ".yml"
+	ExtXML     = ".xml"
 	ExtGZ      = ".gz"
 	ExtBZ2     = ".bz2"
 	ExtXZ      = ".xz"
@@ -360,6 +486,7 @@ const (
 	ExtSNAPPY  = ".snappy"
 	ExtS2      = ".s2"
 	ExtLZ4     = ".lz4"
+	ExtBR      = ".br"
 )
 
 // Compression type identifiers
@@ -372,40 +499,61 @@ const (
 	compSNAPPY = "snappy"
 	compS2     = "s2"
 	compLZ4    = "lz4"
+	compBR     = "br"
 )
 
+// compressionExtensions maps compression file extensions to compression
+// type identifiers.
+var compressionExtensions = map[string]string{
+	ExtGZ:     compGZ,
+	ExtBZ2:    compBZ2,
+	ExtXZ:     compXZ,
+	ExtZSTD:   compZSTD,
+	ExtZLIB:   compZLIB,
+	ExtSNAPPY: compSNAPPY,
+	ExtS2:     compS2,
+	ExtLZ4:    compLZ4,
+	ExtBR:     compBR,
+}
+
+// DetectCompressionLayers returns the compression extensions at the end of
+// path, outermost first, e.g. [".gz", ".bz2"] for "data.csv.bz2.gz". This is
+// the order in which the layers must be decompressed. The extensions are
+// lower-cased, and the result is empty for an uncompressed path.
+//
+// Parse decompresses only the outermost layer, so a file with several layers
+// must have the outer ones removed by the caller before it is parsed as the
+// type returned by DetectFileType.
+func DetectCompressionLayers(path string) []string {
+	var layers []string
+	for {
+		ext := strings.ToLower(filepath.Ext(path))
+		if _, ok := compressionExtensions[ext]; !ok {
+			return layers
+		}
+		layers = append(layers, ext)
+		path = path[:len(path)-len(ext)]
+	}
+}
+
 // DetectFileType detects file type from path extension, including compression variants.
+//
+// When path has several compression extensions, e.g. "data.csv.gz.gz", the
+// format is taken from the extension before all of them and the compression
+// from the outermost one, so "data.csv.gz.gz" is CSVGZ. Parse applies only
+// that single layer of decompression; see DetectCompressionLayers to find
+// the remaining ones. Archives such as "archive.tar.gz" are Unsupported.
 func DetectFileType(path string) FileType {
 	basePath := path
 	var compressionType string
 
 	// Remove compression extensions
-	lowerPath := strings.ToLower(path)
-	switch {
-	case strings.HasSuffix(lowerPath, ExtGZ):
-		basePath = path[:len(path)-len(ExtGZ)]
-		compressionType = compGZ
-	case strings.HasSuffix(lowerPath, ExtBZ2):
-		basePath = path[:len(path)-len(ExtBZ2)]
-		compressionType = compBZ2
-	case strings.HasSuffix(lowerPath, ExtXZ):
-		basePath = path[:len(path)-len(ExtXZ)]
-		compressionType = compXZ
-	case strings.HasSuffix(lowerPath, ExtZSTD):
-		basePath = path[:len(path)-len(ExtZSTD)]
-		compressionType = compZSTD
-	case strings.HasSuffix(lowerPath, ExtZLIB):
-		basePath = path[:len(path)-len(ExtZLIB)]
-		compressionType = compZLIB
-	case strings.HasSuffix(lowerPath, ExtSNAPPY):
-		basePath = path[:len(path)-len(ExtSNAPPY)]
-		compressionType = compSNAPPY
-	case strings.HasSuffix(lowerPath, ExtS2):
-		basePath = path[:len(path)-len(ExtS2)]
-		compressionType = compS2
-	case strings.HasSuffix(lowerPath, ExtLZ4):
-		basePath = path[:len(path)-len(ExtLZ4)]
-		compressionType = compLZ4
+	layers := DetectCompressionLayers(path)
+	if len(layers) > 0 {
+		compressionType = compressionExtensions[layers[0]]
+	}
+	for _, layer := range layers {
+		basePath = basePath[:len(basePath)-len(layer)]
 	}
 
 	ext := strings.ToLower(filepath.Ext(basePath))
@@ -428,6 +576,8 @@ func DetectFileType(path string) FileType {
 			return CSVS2
 		case compLZ4:
 			return CSVLZ4
+		case compBR:
+			return CSVBR
 		default:
 			return CSV
 		}
@@ -449,6 +599,8 @@ func DetectFileType(path string) FileType {
 			return TSVS2
 		case compLZ4:
 			return TSVLZ4
+		case compBR:
+			return TSVBR
 		default:
 			return TSV
 		}
@@ -470,6 +622,8 @@ func DetectFileType(path string) FileType {
 			return LTSVS2
 		case compLZ4:
 			return LTSVLZ4
+		case compBR:
+			return LTSVBR
 		default:
 			return LTSV
 		}
@@ -491,6 +645,8 @@ func DetectFileType(path string) FileType {
 			return ParquetS2
 		case compLZ4:
 			return ParquetLZ4
+		case compBR:
+			return ParquetBR
 		default:
 			return Parquet
 		}
@@ -512,22 +668,89 @@ func DetectFileType(path string) FileType {
 			return XLSXS2
 		case compLZ4:
 			return XLSXLZ4
+		case compBR:
+			return XLSXBR
 		default:
 			return XLSX
 		}
+	case ExtXML:
+		switch compressionType {
+		case compGZ:
+			return XMLGZ
+		case compBZ2:
+			return XMLBZ2
+		case compXZ:
+			return XMLXZ
+		case compZSTD:
+			return XMLZSTD
+		case compZLIB:
+			return XMLZLIB
+		case compSNAPPY:
+			return XMLSNAPPY
+		case compS2:
+			return XMLS2
+		case compLZ4:
+			return XMLLZ4
+		case compBR:
+			return XMLBR
+		default:
+			return XML
+		}
+	case ExtYAML, ExtYML:
+		switch compressionType {
+		case compGZ:
+			return YAMLGZ
+		case compBZ2:
+			return YAMLBZ2
+		case compXZ:
+			return YAMLXZ
+		case compZSTD:
+			return YAMLZSTD
+		case compZLIB:
+			return YAMLZLIB
+		case compSNAPPY:
+			return YAMLSNAPPY
+		case compS2:
+			return YAMLS2
+		case compLZ4:
+			return YAMLLZ4
+		case compBR:
+			return YAMLBR
+		default:
+			return YAML
+		}
 	default:
 		return Unsupported
 	}
 }
 
+// DetectFileTypeParts detects the base file type and the compression of path
+// separately, e.g. (CSV, "gz", true) for "data.csv.gz". compression is one of
+// "gz", "bz2", "xz", "zstd", "zlib", "snappy", "s2", "lz4", or "br", and is
+// empty for an uncompressed path. As in DetectFileType, only the outermost
+// compression layer is reported. ok is false, with base Unsupported and an
+// empty compression, when the file type is not supported.
+func DetectFileTypeParts(path string) (base FileType, compression string, ok bool) {
+	ft := DetectFileType(path)
+	if ft == Unsupported {
+		return Unsupported, "", false
+	}
+	if layers := DetectCompressionLayers(path); len(layers) > 0 {
+		compression = compressionExtensions[layers[0]]
+	}
+	return BaseFileType(ft), compression, true
+}
+
 // IsCompressed returns true if the file type is compressed.
 func IsCompressed(ft FileType) bool {
 	switch ft {
-	case CSVGZ, CSVBZ2, CSVXZ, CSVZSTD, CSVZLIB, CSVSNAPPY, CSVS2, CSVLZ4,
-		TSVGZ, TSVBZ2, TSVXZ, TSVZSTD, TSVZLIB, TSVSNAPPY, TSVS2, TSVLZ4,
-		LTSVGZ, LTSVBZ2, LTSVXZ, LTSVZSTD, LTSVZLIB, LTSVSNAPPY, LTSVS2, LTSVLZ4,
-		ParquetGZ, ParquetBZ2, ParquetXZ, ParquetZSTD, ParquetZLIB, ParquetSNAPPY, ParquetS2, ParquetLZ4,
-		XLSXGZ, XLSXBZ2, XLSXXZ, XLSXZSTD, XLSXZLIB, XLSXSNAPPY, XLSXS2, XLSXLZ4:
+	case CSVGZ, CSVBZ2, CSVXZ, CSVZSTD, CSVZLIB, CSVSNAPPY, CSVS2, CSVLZ4, CSVBR,
+		TSVGZ, TSVBZ2, TSVXZ, TSVZSTD, TSVZLIB, TSVSNAPPY, TSVS2, TSVLZ4, TSVBR,
+		LTSVGZ, LTSVBZ2, LTSVXZ, LTSVZSTD, LTSVZLIB, LTSVSNAPPY, LTSVS2, LTSVLZ4, LTSVBR,
+		ParquetGZ, ParquetBZ2, ParquetXZ, ParquetZSTD, ParquetZLIB, ParquetSNAPPY, ParquetS2, ParquetLZ4, ParquetBR,
+		XLSXGZ, XLSXBZ2, XLSXXZ, XLSXZSTD, XLSXZLIB, XLSXSNAPPY, XLSXS2, XLSXLZ4, XLSXBR,
+		XMLGZ, XMLBZ2, XMLXZ, XMLZSTD, XMLZLIB, XMLSNAPPY, XMLS2, XMLLZ4, XMLBR,
+		YAMLGZ, YAMLBZ2, YAMLXZ, YAMLZSTD, YAMLZLIB, YAMLSNAPPY, YAMLS2, YAMLLZ4, YAMLBR:
 		return true
 	default:
 		return false
@@ -537,16 +760,20 @@ func IsCompressed(ft FileType) bool {
 // BaseFileType returns the base file type without compression.
 func BaseFileType(ft FileType) FileType {
 	switch ft {
-	case CSV, CSVGZ, CSVBZ2, CSVXZ, CSVZSTD, CSVZLIB, CSVSNAPPY, CSVS2, CSVLZ4:
+	case CSV, CSVGZ, CSVBZ2, CSVXZ, CSVZSTD, CSVZLIB, CSVSNAPPY, CSVS2, CSVLZ4, CSVBR:
 		return CSV
-	case TSV, TSVGZ, TSVBZ2, TSVXZ, TSVZSTD, TSVZLIB, TSVSNAPPY, TSVS2, TSVLZ4:
+	case TSV, TSVGZ, TSVBZ2, TSVXZ, TSVZSTD, TSVZLIB, TSVSNAPPY, TSVS2, TSVLZ4, TSVBR:
 		return TSV
-	case LTSV, LTSVGZ, LTSVBZ2, LTSVXZ, LTSVZSTD, LTSVZLIB, LTSVSNAPPY, LTSVS2, LTSVLZ4:
+	case LTSV, LTSVGZ, LTSVBZ2, LTSVXZ, LTSVZSTD, LTSVZLIB, LTSVSNAPPY, LTSVS2, LTSVLZ4, LTSVBR:
 		return LTSV
-	case Parquet, ParquetGZ, ParquetBZ2, ParquetXZ, ParquetZSTD, ParquetZLIB, ParquetSNAPPY, ParquetS2, ParquetLZ4:
+	case Parquet, ParquetGZ, ParquetBZ2, ParquetXZ, ParquetZSTD, ParquetZLIB, ParquetSNAPPY, ParquetS2, ParquetLZ4, ParquetBR:
 		return Parquet
-	case XLSX, XLSXGZ, XLSXBZ2, XLSXXZ, XLSXZSTD, XLSXZLIB, XLSXSNAPPY, XLSXS2, XLSXLZ4:
+	case XLSX, XLSXGZ, XLSXBZ2, XLSXXZ, XLSXZSTD, XLSXZLIB, XLSXSNAPPY, XLSXS2, XLSXLZ4, XLSXBR:
 		return XLSX
+	case XML, XMLGZ, XMLBZ2, XMLXZ, XMLZSTD, XMLZLIB, XMLSNAPPY, XMLS2, XMLLZ4, XMLBR:
+		return XML
+	case YAML, YAMLGZ, YAMLBZ2, YAMLXZ, YAMLZSTD, YAMLZLIB, YAMLSNAPPY, YAMLS2, YAMLLZ4, YAMLBR:
+		return YAML
 	default:
 		return Unsupported
 	}
@@ -554,51 +781,72 @@ func BaseFileType(ft FileType) FileType {
 
 // createDecompressedReader wraps the reader with appropriate decompression.
 func createDecompressedReader(reader io.Reader, fileType FileType) (io.Reader, func() error, error) {
+	return createDecompressedReaderWithOptions(reader, fileType, ZstdOptions{})
+}
+
+// createDecompressedReaderWithOptions wraps the reader with appropriate
+// decompression, configuring zstd decoders with zstdOpts.
+func createDecompressedReaderWithOptions(reader io.Reader, fileType FileType, zstdOpts ZstdOptions) (io.Reader, func() error, error) {
 	switch fileType {
-	case CSVGZ, TSVGZ, LTSVGZ, XLSXGZ, ParquetGZ:
+	case CSVGZ, TSVGZ, LTSVGZ, XLSXGZ, ParquetGZ, XMLGZ, YAMLGZ:
+		// gzip.Reader is in multistream mode by default, so concatenated
+		// members such as `cat a.csv.gz b.csv.gz` are read as one stream
 		gzReader, err := gzip.NewReader(reader)
 		if err != nil {
 			return nil, nil, fmt.Errorf("failed to create gzip reader: %w", err)
 		}
 		return gzReader, func() error { return gzReader.Close() }, nil
 
-	case CSVBZ2, TSVBZ2, LTSVBZ2, XLSXBZ2, ParquetBZ2:
+	case CSVBZ2, TSVBZ2, LTSVBZ2, XLSXBZ2, ParquetBZ2, XMLBZ2, YAMLBZ2:
 		bz2Reader := bzip2.NewReader(reader)
 		return bz2Reader, nil, nil
 
-	case CSVXZ, TSVXZ, LTSVXZ, XLSXXZ, ParquetXZ:
+	case CSVXZ, TSVXZ, LTSVXZ, XLSXXZ, ParquetXZ, XMLXZ, YAMLXZ:
 		xzReader, err := xz.NewReader(reader)
 		if err != nil {
 			return nil, nil, fmt.Errorf("failed to create xz reader: %w", err)
 		}
 		return xzReader, nil, nil
 
-	case CSVZSTD, TSVZSTD, LTSVZSTD, XLSXZSTD, ParquetZSTD:
-		decoder, err := zstd.NewReader(reader)
+	case CSVZSTD, TSVZSTD, LTSVZSTD, XLSXZSTD, ParquetZSTD, XMLZSTD, YAMLZSTD:
+		decoderOpts, err := zstdOpts.decoderOptions()
 		if err != nil {
+			return nil, nil, err
+		}
+		decoder, err := zstd.NewReader(reader, decoderOpts...)
+		if err != nil {
+			// NewReader returns a decoder that must be closed when only
+			// reading the frame header failed
+			if decoder != nil {
+				decoder.Close()
+			}
 			return nil, nil, fmt.Errorf("failed to create zstd reader: %w", err)
 		}
 		return decoder, func() error { decoder.Close(); return nil }, nil
 
-	case CSVZLIB, TSVZLIB, LTSVZLIB, XLSXZLIB, ParquetZLIB:
+	case CSVZLIB, TSVZLIB, LTSVZLIB, XLSXZLIB, ParquetZLIB, XMLZLIB, YAMLZLIB:
 		zlibReader, err := zlib.NewReader(reader)
 		if err != nil {
 			return nil, nil, fmt.Errorf("failed to create zlib reader: %w", err)
 		}
 		return zlibReader, func() error { return zlibReader.Close() }, nil
 
-	case CSVSNAPPY, TSVSNAPPY, LTSVSNAPPY, XLSXSNAPPY, ParquetSNAPPY:
+	case CSVSNAPPY, TSVSNAPPY, LTSVSNAPPY, XLSXSNAPPY, ParquetSNAPPY, XMLSNAPPY, YAMLSNAPPY:
 		snappyReader := snappy.NewReader(reader)
 		return snappyReader, nil, nil
 
-	case CSVS2, TSVS2, LTSVS2, XLSXS2, ParquetS2:
+	case CSVS2, TSVS2, LTSVS2, XLSXS2, ParquetS2, XMLS2, YAMLS2:
 		s2Reader := s2.NewReader(reader)
 		return s2Reader, nil, nil
 
-	case CSVLZ4, TSVLZ4, LTSVLZ4, XLSXLZ4, ParquetLZ4:
+	case CSVLZ4, TSVLZ4, LTSVLZ4, XLSXLZ4, ParquetLZ4, XMLLZ4, YAMLLZ4:
 		lz4Reader := lz4.NewReader(reader)
 		return lz4Reader, nil, nil
 
+	case CSVBR, TSVBR, LTSVBR, XLSXBR, ParquetBR, XMLBR, YAMLBR:
+		brReader := brotli.NewReader(reader)
+		return brReader, nil, nil
+
 	default:
 		// No compression
 		return reader, nil, nil
@@ -606,103 +854,142 @@ func createDecompressedReader(reader io.Reader, fileType FileType) (io.Reader, f
 }
 
 // parseDelimited parses CSV or TSV data.
-func parseDelimited(reader io.Reader, delimiter rune, fileTypeName string) (*TableData, error) {
+// It also reports whether the first line was used as the header, which depends
+// on opts.HeaderDetection.
+func parseDelimited(reader io.Reader, delimiter rune, fileTypeName string, opts ParseOptions) (*TableData, bool, error) {
+	reader = newBOMAwareReader(reader)
+	if opts.CSVDialect != CSVDialectDefault {
+		reader = newDialectReader(reader, delimiter, opts.CSVDialect)
+	}
+	if opts.SkipEmptyLines {
+		reader = newBlankLineFilter(reader, delimiter)
+	}
 	csvReader := csv.NewReader(reader)
 	csvReader.Comma = delimiter
+	csvReader.FieldsPerRecord = opts.FieldsPerRecord
+	csvReader.LazyQuotes = opts.CSVDialect == CSVDialectExcel
 
-	records, err := csvReader.ReadAll()
+	headers, err := csvReader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, false, withKind(fmt.Errorf("empty %s data", fileTypeName), ErrEmptyData)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to read %s: %w", fileTypeName, err)
+		return nil, false, delimitedReadError(fileTypeName, err)
 	}
 
-	if len(records) == 0 {
-		return nil, fmt.Errorf("empty %s data", fileTypeName)
+	dropTrailing := opts.DropTrailingEmptyColumn && len(headers) > 1 && strings.TrimSpace(headers[len(headers)-1]) == ""
+	if dropTrailing {
+		headers = headers[:len(headers)-1]
 	}
 
-	headers := records[0]
-	if err := validateColumnNames(headers); err != nil {
-		return nil, err
+	if opts.HeaderDetection == HeaderAlways {
+		if err := validateColumnNames(headers); err != nil {
+			return nil, false, err
+		}
+	}
+
+	dataRecords := make([][]string, 0)
+	for !opts.reachedMaxRows(len(dataRecords)) {
+		record, err := csvReader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, false, delimitedReadError(fileTypeName, err)
+		}
+		if dropTrailing && len(record) == len(headers)+1 {
+			record = record[:len(headers)]
+		}
+		dataRecords = append(dataRecords, record)
+	}
+
+	hasHeader := true
+	if opts.HeaderDetection != HeaderAlways {
+		headers, dataRecords, hasHeader = resolveHeader(opts.HeaderDetection, opts.Inference, headers, dataRecords, opts.MaxRows)
+		if err := validateColumnNames(headers); err != nil {
+			return nil, false, err
+		}
 	}
 
-	dataRecords := make([][]string, 0, len(records)-1)
-	for i := 1; i < len(records); i++ {
-		dataRecords = append(dataRecords, records[i])
+	if opts.FieldsPerRecord < 0 {
+		for i, record := range dataRecords {
+			dataRecords[i] = fitRecord(record, len(headers))
+		}
 	}
 
 	// Infer column types
-	columnTypes := inferColumnTypes(headers, dataRecords)
+	columnTypes := opts.inferColumnTypes(headers, dataRecords)
 
 	return &TableData{
 		Headers:     headers,
 		Records:     dataRecords,
 		ColumnTypes: columnTypes,
-	}, nil
+	}, hasHeader, nil
 }
 
 // parseLTSV parses LTSV (Labeled Tab-Separated Values) data.
 // Column order is preserved as first-seen order for deterministic output.
-func parseLTSV(reader io.Reader) (*TableData, error) {
-	content, err := io.ReadAll(reader)
+func parseLTSV(reader io.Reader, opts ParseOptions) (*TableData, error) {
+	fieldSep, labelSep, err := opts.ltsvSeparators()
 	if err != nil {
-		return nil, fmt.Errorf("failed to read LTSV: %w", err)
-	}
-
-	lines := strings.Split(string(content), "\n")
-	if len(lines) == 0 {
-		return nil, errors.New("empty LTSV data")
+		return nil, err
 	}
+	bufReader := bufio.NewReader(newBOMAwareReader(reader))
 
 	// Use slice to preserve first-seen order
 	var headers []string
 	headerSeen := make(map[string]bool)
 	var parsedRecords []map[string]string
 
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
+	for !opts.reachedMaxRows(len(parsedRecords)) {
+		line, readErr := bufReader.ReadString('\n')
+		if readErr != nil && !errors.Is(readErr, io.EOF) {
+			return nil, fmt.Errorf("failed to read LTSV: %w", readErr)
 		}
 
-		recordMap := make(map[string]string)
-		pairs := strings.Split(line, "\t")
-		for _, pair := range pairs {
-			kv := strings.SplitN(pair, ":", 2)
-			if len(kv) == 2 {
-				key := strings.TrimSpace(kv[0])
-				value := strings.TrimSpace(kv[1])
-				recordMap[key] = value
-				// Track headers in first-seen order
-				if !headerSeen[key] {
-					headerSeen[key] = true
-					headers = append(headers, key)
+		line = strings.TrimSpace(line)
+		if line != "" {
+			recordMap := make(map[string]string)
+			for rest, more := line, true; more; {
+				var pair string
+				pair, rest, more = strings.Cut(rest, fieldSep)
+				rawKey, rawValue, ok := strings.Cut(pair, labelSep)
+				if ok {
+					key := strings.TrimSpace(rawKey)
+					value := strings.TrimSpace(rawValue)
+					if prev, dup := recordMap[key]; dup {
+						if opts.LTSVDuplicateKeys == DuplicateKeyError {
+							return nil, fmt.Errorf("duplicate LTSV label %q in record %d", key, len(parsedRecords)+1)
+						}
+						value = opts.resolveDuplicateKey(prev, value)
+					}
+					recordMap[key] = value
+					// Track headers in first-seen order
+					if !headerSeen[key] {
+						headerSeen[key] = true
+						headers = append(headers, key)
+					}
 				}
 			}
+			if len(recordMap) > 0 {
+				parsedRecords = append(parsedRecords, recordMap)
+			}
 		}
-		if len(recordMap) > 0 {
-			parsedRecords = append(parsedRecords, recordMap)
+
+		if errors.Is(readErr, io.EOF) {
+			break
 		}
 	}
 
 	if len(parsedRecords) == 0 {
-		return nil, errors.New("no valid LTSV records found")
+		return nil, withKind(errors.New("no valid LTSV records found"), ErrEmptyData)
 	}
 
 	// Convert to records using first-seen header order
-	records := make([][]string, 0, len(parsedRecords))
-	for _, recordMap := range parsedRecords {
-		row := make([]string, len(headers))
-		for i, key := range headers {
-			if val, exists := recordMap[key]; exists {
-				row[i] = val
-			} else {
-				row[i] = ""
-			}
-		}
-		records = append(records, row)
-	}
+	records := recordsFromMaps(headers, parsedRecords)
 
 	// Infer column types
-	columnTypes := inferColumnTypes(headers, records)
+	columnTypes := opts.inferColumnTypes(headers, records)
 
 	return &TableData{
 		Headers:     headers,
@@ -711,12 +998,36 @@ func parseLTSV(reader io.Reader) (*TableData, error) {
 	}, nil
 }
 
+// recordsFromMaps converts records keyed by column name to rows ordered by
+// headers. Columns missing from a record are left empty.
+func recordsFromMaps(headers []string, recordMaps []map[string]string) [][]string {
+	records := make([][]string, 0, len(recordMaps))
+	for _, recordMap := range recordMaps {
+		row := make([]string, len(headers))
+		for i, key := range headers {
+			row[i] = recordMap[key]
+		}
+		records = append(records, row)
+	}
+	return records
+}
+
+// fitRecord pads record with empty strings or truncates it to n fields.
+func fitRecord(record []string, n int) []string {
+	if len(record) == n {
+		return record
+	}
+	fitted := make([]string, n)
+	copy(fitted, record)
+	return fitted
+}
+
 // validateColumnNames checks for duplicate column names.
 func validateColumnNames(columns []string) error {
 	seen := make(map[string]bool, len(columns))
 	for _, col := range columns {
 		if seen[col] {
-			return fmt.Errorf("duplicate column name: %s", col)
+			return fmt.Errorf("%w: %s", ErrDuplicateColumn, col)
 		}
 		seen[col] = true
 	}
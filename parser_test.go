@@ -1,6 +1,9 @@
 package fileparser
 
 import (
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
 	"os"
 	"path/filepath"
 	"strings"
@@ -121,6 +124,20 @@ func TestParse_LTSV(t *testing.T) {
 		assert.Contains(t, result.Headers, "path")
 	})
 
+	t.Run("infers types of access log values like CSV", func(t *testing.T) {
+		t.Parallel()
+
+		input := "time:[10/Oct/2000:13:55:36 -0700]\tstatus:200\tcached:true\tsent:2024-01-15T10:00:00Z\n" +
+			"time:[10/Oct/2000:13:55:37 -0700]\tstatus:404\tcached:false\tsent:2024-01-15T10:00:01Z\n"
+
+		result, err := Parse(strings.NewReader(input), LTSV)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"time", "status", "cached", "sent"}, result.Headers)
+		assert.Equal(t, "[10/Oct/2000:13:55:36 -0700]", result.Records[0][0])
+		assert.Equal(t, []ColumnType{TypeDatetime, TypeInteger, TypeText, TypeDatetime}, result.ColumnTypes)
+	})
+
 	t.Run("returns error for empty LTSV", func(t *testing.T) {
 		t.Parallel()
 
@@ -229,6 +246,7 @@ func TestBaseFileType(t *testing.T) {
 		{CSVSNAPPY, CSV},
 		{CSVS2, CSV},
 		{CSVLZ4, CSV},
+		{CSVBR, CSV},
 		// TSV variants
 		{TSV, TSV},
 		{TSVGZ, TSV},
@@ -239,6 +257,7 @@ func TestBaseFileType(t *testing.T) {
 		{TSVSNAPPY, TSV},
 		{TSVS2, TSV},
 		{TSVLZ4, TSV},
+		{TSVBR, TSV},
 		// LTSV variants
 		{LTSV, LTSV},
 		{LTSVGZ, LTSV},
@@ -249,6 +268,7 @@ func TestBaseFileType(t *testing.T) {
 		{LTSVSNAPPY, LTSV},
 		{LTSVS2, LTSV},
 		{LTSVLZ4, LTSV},
+		{LTSVBR, LTSV},
 		// Parquet variants
 		{Parquet, Parquet},
 		{ParquetGZ, Parquet},
@@ -259,6 +279,7 @@ func TestBaseFileType(t *testing.T) {
 		{ParquetSNAPPY, Parquet},
 		{ParquetS2, Parquet},
 		{ParquetLZ4, Parquet},
+		{ParquetBR, Parquet},
 		// XLSX variants
 		{XLSX, XLSX},
 		{XLSXGZ, XLSX},
@@ -269,6 +290,31 @@ func TestBaseFileType(t *testing.T) {
 		{XLSXSNAPPY, XLSX},
 		{XLSXS2, XLSX},
 		{XLSXLZ4, XLSX},
+		{XLSXBR, XLSX},
+		// XML
+		{XML, XML},
+		{XMLGZ, XML},
+		{XMLBZ2, XML},
+		{XMLXZ, XML},
+		{XMLZSTD, XML},
+		{XMLZLIB, XML},
+		{XMLSNAPPY, XML},
+		{XMLS2, XML},
+		{XMLLZ4, XML},
+		{XMLBR, XML},
+
+		// YAML
+		{YAML, YAML},
+		{YAMLGZ, YAML},
+		{YAMLBZ2, YAML},
+		{YAMLXZ, YAML},
+		{YAMLZSTD, YAML},
+		{YAMLZLIB, YAML},
+		{YAMLSNAPPY, YAML},
+		{YAMLS2, YAML},
+		{YAMLLZ4, YAML},
+		{YAMLBR, YAML},
+
 		// Unsupported
 		{Unsupported, Unsupported},
 	}
@@ -295,6 +341,7 @@ func TestColumnType_String(t *testing.T) {
 		{TypeInteger, "INTEGER"},
 		{TypeReal, "REAL"},
 		{TypeDatetime, "DATETIME"},
+		{TypeBoolean, "BOOLEAN"},
 	}
 
 	for _, tc := range testCases {
@@ -330,6 +377,7 @@ func TestFileType_String(t *testing.T) {
 		{CSVSNAPPY, "CSV (snappy)"},
 		{CSVS2, "CSV (s2)"},
 		{CSVLZ4, "CSV (lz4)"},
+		{CSVBR, "CSV (brotli)"},
 		// TSV compressed
 		{TSVGZ, "TSV (gzip)"},
 		{TSVBZ2, "TSV (bzip2)"},
@@ -339,6 +387,7 @@ func TestFileType_String(t *testing.T) {
 		{TSVSNAPPY, "TSV (snappy)"},
 		{TSVS2, "TSV (s2)"},
 		{TSVLZ4, "TSV (lz4)"},
+		{TSVBR, "TSV (brotli)"},
 		// LTSV compressed
 		{LTSVGZ, "LTSV (gzip)"},
 		{LTSVBZ2, "LTSV (bzip2)"},
@@ -348,6 +397,7 @@ func TestFileType_String(t *testing.T) {
 		{LTSVSNAPPY, "LTSV (snappy)"},
 		{LTSVS2, "LTSV (s2)"},
 		{LTSVLZ4, "LTSV (lz4)"},
+		{LTSVBR, "LTSV (brotli)"},
 		// Parquet compressed
 		{ParquetGZ, "Parquet (gzip)"},
 		{ParquetBZ2, "Parquet (bzip2)"},
@@ -357,6 +407,7 @@ func TestFileType_String(t *testing.T) {
 		{ParquetSNAPPY, "Parquet (snappy)"},
 		{ParquetS2, "Parquet (s2)"},
 		{ParquetLZ4, "Parquet (lz4)"},
+		{ParquetBR, "Parquet (brotli)"},
 		// XLSX compressed
 		{XLSXGZ, "XLSX (gzip)"},
 		{XLSXBZ2, "XLSX (bzip2)"},
@@ -366,6 +417,31 @@ func TestFileType_String(t *testing.T) {
 		{XLSXSNAPPY, "XLSX (snappy)"},
 		{XLSXS2, "XLSX (s2)"},
 		{XLSXLZ4, "XLSX (lz4)"},
+		{XLSXBR, "XLSX (brotli)"},
+		// XML
+		{XML, "XML"},
+		{XMLGZ, "XML (gzip)"},
+		{XMLBZ2, "XML (bzip2)"},
+		{XMLXZ, "XML (xz)"},
+		{XMLZSTD, "XML (zstd)"},
+		{XMLZLIB, "XML (zlib)"},
+		{XMLSNAPPY, "XML (snappy)"},
+		{XMLS2, "XML (s2)"},
+		{XMLLZ4, "XML (lz4)"},
+		{XMLBR, "XML (brotli)"},
+
+		// YAML
+		{YAML, "YAML"},
+		{YAMLGZ, "YAML (gzip)"},
+		{YAMLBZ2, "YAML (bzip2)"},
+		{YAMLXZ, "YAML (xz)"},
+		{YAMLZSTD, "YAML (zstd)"},
+		{YAMLZLIB, "YAML (zlib)"},
+		{YAMLSNAPPY, "YAML (snappy)"},
+		{YAMLS2, "YAML (s2)"},
+		{YAMLLZ4, "YAML (lz4)"},
+		{YAMLBR, "YAML (brotli)"},
+
 		// Unsupported
 		{Unsupported, "Unsupported"},
 		{FileType(999), "Unsupported"},
@@ -451,6 +527,12 @@ func TestDetectFileType(t *testing.T) {
 		{"data.ltsv.lz4", LTSVLZ4},
 		{"data.parquet.lz4", ParquetLZ4},
 		{"data.xlsx.lz4", XLSXLZ4},
+		// Brotli compressed
+		{"data.csv.br", CSVBR},
+		{"data.tsv.br", TSVBR},
+		{"data.ltsv.br", LTSVBR},
+		{"data.parquet.br", ParquetBR},
+		{"data.xlsx.br", XLSXBR},
 
 		// Case insensitive
 		{"DATA.CSV", CSV},
@@ -461,11 +543,43 @@ func TestDetectFileType(t *testing.T) {
 		{"/path/to/data.csv", CSV},
 		{"./relative/path/data.tsv.gz", TSVGZ},
 
+		// XML
+		{"data.xml", XML},
+		{"data.xml.gz", XMLGZ},
+		{"data.xml.bz2", XMLBZ2},
+		{"data.xml.xz", XMLXZ},
+		{"data.xml.zst", XMLZSTD},
+		{"data.xml.z", XMLZLIB},
+		{"data.xml.snappy", XMLSNAPPY},
+		{"data.xml.s2", XMLS2},
+		{"data.xml.lz4", XMLLZ4},
+		{"data.xml.br", XMLBR},
+
+		// YAML
+		{"data.yaml", YAML},
+		{"data.yml", YAML},
+		{"data.YML.GZ", YAMLGZ},
+		{"data.yaml.gz", YAMLGZ},
+		{"data.yaml.bz2", YAMLBZ2},
+		{"data.yaml.xz", YAMLXZ},
+		{"data.yaml.zst", YAMLZSTD},
+		{"data.yaml.z", YAMLZLIB},
+		{"data.yaml.snappy", YAMLSNAPPY},
+		{"data.yaml.s2", YAMLS2},
+		{"data.yaml.lz4", YAMLLZ4},
+		{"data.yaml.br", YAMLBR},
+
 		// Unsupported
 		{"data.txt", Unsupported},
 		{"data.json", Unsupported},
 		{"noextension", Unsupported},
 		{"", Unsupported},
+
+		// Several compression layers use the outermost one
+		{"data.csv.gz.gz", CSVGZ},
+		{"data.tsv.bz2.gz", TSVGZ},
+		{"data.XML.GZ.ZST", XMLZSTD},
+		{"archive.tar.gz", Unsupported},
 	}
 
 	for _, tc := range testCases {
@@ -479,6 +593,103 @@ func TestDetectFileType(t *testing.T) {
 	}
 }
 
+func TestDetectCompressionLayers(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		path     string
+		expected []string
+	}{
+		{"data.csv", nil},
+		{"data.csv.gz", []string{ExtGZ}},
+		{"data.csv.gz.gz", []string{ExtGZ, ExtGZ}},
+		{"data.csv.bz2.zst", []string{ExtZSTD, ExtBZ2}},
+		{"DATA.TSV.Z.BR", []string{ExtBR, ExtZLIB}},
+		{"archive.tar.gz", []string{ExtGZ}},
+		{"", nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, DetectCompressionLayers(tc.path))
+		})
+	}
+}
+
+func TestDetectFileTypeParts(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		path        string
+		base        FileType
+		compression string
+		ok          bool
+	}{
+		{"data.csv", CSV, "", true},
+		{"data.csv.gz", CSV, "gz", true},
+		{"logs/access.LTSV.ZST", LTSV, "zstd", true},
+		{"book.xlsx.bz2", XLSX, "bz2", true},
+		{"data.parquet.br", Parquet, "br", true},
+		{"data.csv.bz2.gz", CSV, "gz", true},
+		{"archive.tar.gz", Unsupported, "", false},
+		{"data.json", Unsupported, "", false},
+		{"", Unsupported, "", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			t.Parallel()
+
+			base, compression, ok := DetectFileTypeParts(tc.path)
+
+			assert.Equal(t, tc.base, base)
+			assert.Equal(t, tc.compression, compression)
+			assert.Equal(t, tc.ok, ok)
+		})
+	}
+}
+
+func TestParse_DelimitedErrorLine(t *testing.T) {
+	t.Parallel()
+
+	t.Run("reports the line of a record with the wrong number of fields", func(t *testing.T) {
+		t.Parallel()
+
+		input := "id,name\n1,Alice\n2,Bob\n3\n"
+
+		_, err := Parse(strings.NewReader(input), CSV)
+
+		require.Error(t, err)
+		assert.Equal(t, "failed to read CSV: CSV parse error at line 4: wrong number of fields", err.Error())
+		require.ErrorIs(t, err, csv.ErrFieldCount)
+
+		var parseErr *ParseError
+		require.ErrorAs(t, err, &parseErr)
+		assert.Equal(t, 4, parseErr.Line)
+	})
+
+	t.Run("reports the line of a malformed quote", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Parse(strings.NewReader("a\tb\n1\t\"x\"y\n"), TSV)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "TSV parse error at line 2")
+		require.ErrorIs(t, err, csv.ErrQuote)
+	})
+
+	t.Run("reports the line when streaming", func(t *testing.T) {
+		t.Parallel()
+
+		err := ParseStream(strings.NewReader("a,b\n1,2\n3\n"), CSV, func(_, _ []string) error { return nil })
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "CSV parse error at line 3: wrong number of fields")
+	})
+}
+
 func TestCreateDecompressedReader_NoCompression(t *testing.T) {
 	t.Parallel()
 
@@ -498,6 +709,42 @@ func TestCreateDecompressedReader_NoCompression(t *testing.T) {
 	}
 }
 
+func TestParse_GzipMultistream(t *testing.T) {
+	t.Parallel()
+
+	// Concatenated gzip members, as produced by `cat a.csv.gz b.csv.gz`
+	var buf bytes.Buffer
+	for _, part := range []string{"id,name\n1,Alice\n", "2,Bob\n3,Carol\n"} {
+		gz := gzip.NewWriter(&buf)
+		_, err := gz.Write([]byte(part))
+		require.NoError(t, err)
+		require.NoError(t, gz.Close())
+	}
+	data := buf.Bytes()
+
+	t.Run("Parse reads every member", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := Parse(bytes.NewReader(data), CSVGZ)
+
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{{"1", "Alice"}, {"2", "Bob"}, {"3", "Carol"}}, result.Records)
+	})
+
+	t.Run("ParseStream reads every member", func(t *testing.T) {
+		t.Parallel()
+
+		var ids []string
+		err := ParseStream(bytes.NewReader(data), CSVGZ, func(record, _ []string) error {
+			ids = append(ids, record[0])
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"1", "2", "3"}, ids)
+	})
+}
+
 func TestCreateDecompressedReader_InvalidGzip(t *testing.T) {
 	t.Parallel()
 
@@ -668,6 +915,20 @@ func TestParse_NewCompressionFormats(t *testing.T) {
 		assert.Equal(t, 3, len(result.Records))
 	})
 
+	t.Run("parses sample.csv.br", func(t *testing.T) {
+		t.Parallel()
+
+		f, err := os.Open(filepath.Join(testdataDir, "sample.csv.br"))
+		require.NoError(t, err)
+		defer f.Close()
+
+		result, err := Parse(f, CSVBR)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"id", "name", "age", "email"}, result.Headers)
+		assert.Equal(t, 3, len(result.Records))
+	})
+
 	// TSV compression tests
 	t.Run("parses products.tsv.z (zlib)", func(t *testing.T) {
 		t.Parallel()
@@ -824,19 +1085,31 @@ func TestParse_InvalidLZ4(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestParse_InvalidBrotli(t *testing.T) {
+	t.Parallel()
+
+	input := strings.NewReader("not brotli data")
+
+	_, err := Parse(input, CSVBR)
+
+	assert.Error(t, err)
+}
+
 func TestIsCompressed(t *testing.T) {
 	t.Parallel()
 
 	compressedTypes := []FileType{
-		CSVGZ, CSVBZ2, CSVXZ, CSVZSTD, CSVZLIB, CSVSNAPPY, CSVS2, CSVLZ4,
-		TSVGZ, TSVBZ2, TSVXZ, TSVZSTD, TSVZLIB, TSVSNAPPY, TSVS2, TSVLZ4,
-		LTSVGZ, LTSVBZ2, LTSVXZ, LTSVZSTD, LTSVZLIB, LTSVSNAPPY, LTSVS2, LTSVLZ4,
-		ParquetGZ, ParquetBZ2, ParquetXZ, ParquetZSTD, ParquetZLIB, ParquetSNAPPY, ParquetS2, ParquetLZ4,
-		XLSXGZ, XLSXBZ2, XLSXXZ, XLSXZSTD, XLSXZLIB, XLSXSNAPPY, XLSXS2, XLSXLZ4,
+		CSVGZ, CSVBZ2, CSVXZ, CSVZSTD, CSVZLIB, CSVSNAPPY, CSVS2, CSVLZ4, CSVBR,
+		TSVGZ, TSVBZ2, TSVXZ, TSVZSTD, TSVZLIB, TSVSNAPPY, TSVS2, TSVLZ4, TSVBR,
+		LTSVGZ, LTSVBZ2, LTSVXZ, LTSVZSTD, LTSVZLIB, LTSVSNAPPY, LTSVS2, LTSVLZ4, LTSVBR,
+		ParquetGZ, ParquetBZ2, ParquetXZ, ParquetZSTD, ParquetZLIB, ParquetSNAPPY, ParquetS2, ParquetLZ4, ParquetBR,
+		XLSXGZ, XLSXBZ2, XLSXXZ, XLSXZSTD, XLSXZLIB, XLSXSNAPPY, XLSXS2, XLSXLZ4, XLSXBR,
+		XMLGZ, XMLBZ2, XMLXZ, XMLZSTD, XMLZLIB, XMLSNAPPY, XMLS2, XMLLZ4, XMLBR,
+		YAMLGZ, YAMLBZ2, YAMLXZ, YAMLZSTD, YAMLZLIB, YAMLSNAPPY, YAMLS2, YAMLLZ4, YAMLBR,
 	}
 
 	uncompressedTypes := []FileType{
-		CSV, TSV, LTSV, Parquet, XLSX, Unsupported,
+		CSV, TSV, LTSV, Parquet, XLSX, XML, YAML, Unsupported,
 	}
 
 	for _, ft := range compressedTypes {
@@ -853,3 +1126,117 @@ func TestIsCompressed(t *testing.T) {
 		})
 	}
 }
+
+func TestParseTee(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns parsed table and input bytes", func(t *testing.T) {
+		t.Parallel()
+
+		input := "name,age\nAlice,30\nBob,25\n"
+
+		result, raw, err := ParseTee(strings.NewReader(input), CSV)
+
+		require.NoError(t, err)
+		assert.Equal(t, []byte(input), raw)
+		assert.Equal(t, []string{"name", "age"}, result.Headers)
+		assert.Len(t, result.Records, 2)
+	})
+
+	t.Run("returns compressed bytes for compressed input", func(t *testing.T) {
+		t.Parallel()
+
+		data, err := os.ReadFile(filepath.Join("testdata", "sample.csv.gz"))
+		require.NoError(t, err)
+
+		result, raw, err := ParseTee(bytes.NewReader(data), CSVGZ)
+
+		require.NoError(t, err)
+		assert.Equal(t, data, raw)
+		assert.Len(t, result.Records, 3)
+	})
+
+	t.Run("returns error for nil reader", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, err := ParseTee(nil, CSV)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestParseBytes(t *testing.T) {
+	t.Parallel()
+
+	t.Run("parses CSV", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := ParseBytes([]byte("id,name\n1,Alice\n"), CSV)
+
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{{"1", "Alice"}}, result.Records)
+	})
+
+	t.Run("parses Parquet", func(t *testing.T) {
+		t.Parallel()
+
+		table := &TableData{
+			Headers:     []string{"id"},
+			Records:     [][]string{{"1"}, {"2"}},
+			ColumnTypes: []ColumnType{TypeInteger},
+		}
+		var buf bytes.Buffer
+		require.NoError(t, Write(&buf, table, Parquet, WriteOptions{}))
+
+		result, err := ParseBytes(buf.Bytes(), Parquet)
+
+		require.NoError(t, err)
+		assert.Equal(t, table.Records, result.Records)
+	})
+
+	t.Run("parses gzip-compressed data", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		require.NoError(t, Write(&buf, &TableData{Headers: []string{"a"}, Records: [][]string{{"x"}}}, CSVGZ, WriteOptions{}))
+
+		result, err := ParseBytes(buf.Bytes(), CSVGZ)
+
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{{"x"}}, result.Records)
+	})
+
+	t.Run("returns error for empty Parquet data", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParseBytes(nil, Parquet)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "empty parquet file")
+	})
+}
+
+func Test_readAll(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns in-memory data without copying", func(t *testing.T) {
+		t.Parallel()
+
+		data := []byte("PAR1 data")
+
+		got, err := readAll(&bytesReaderAt{data: data, offset: 5})
+
+		require.NoError(t, err)
+		assert.Equal(t, []byte("data"), got)
+		assert.Same(t, &data[5], &got[0])
+	})
+
+	t.Run("reads other readers fully", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := readAll(strings.NewReader("abc"))
+
+		require.NoError(t, err)
+		assert.Equal(t, []byte("abc"), got)
+	})
+}
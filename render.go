@@ -0,0 +1,208 @@
+package fileparser
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/text/width"
+)
+
+// RenderOptions configures TableData.RenderTable.
+type RenderOptions struct {
+	// MaxColumnWidth truncates cell values wider than this many terminal
+	// columns, marking the cut with "…". Zero or a negative value means no limit.
+	MaxColumnWidth int
+
+	// MaxRows limits the number of records printed. When records are omitted,
+	// a "(N more rows)" line follows the table. Zero or a negative value means
+	// no limit.
+	MaxRows int
+
+	// Unicode draws borders with box-drawing characters instead of ASCII.
+	Unicode bool
+}
+
+// tableBorder holds the characters used to draw a table.
+type tableBorder struct {
+	horizontal, vertical               string
+	topLeft, topMid, topRight          string
+	midLeft, midMid, midRight          string
+	bottomLeft, bottomMid, bottomRight string
+}
+
+var (
+	asciiBorder = tableBorder{
+		horizontal: "-", vertical: "|",
+		topLeft: "+", topMid: "+", topRight: "+",
+		midLeft: "+", midMid: "+", midRight: "+",
+		bottomLeft: "+", bottomMid: "+", bottomRight: "+",
+	}
+	unicodeBorder = tableBorder{
+		horizontal: "─", vertical: "│",
+		topLeft: "┌", topMid: "┬", topRight: "┐",
+		midLeft: "├", midMid: "┼", midRight: "┤",
+		bottomLeft: "└", bottomMid: "┴", bottomRight: "┘",
+	}
+)
+
+// RenderTable writes the table to w as an aligned, bordered text table for
+// terminal display. Column widths are computed from the headers and printed
+// records; East Asian wide characters count as two columns. Columns whose
+// ColumnTypes entry is TypeInteger or TypeReal are right-aligned, all others
+// are left-aligned.
+//
+// Example:
+//
+//	result, _ := fileparser.Parse(f, fileparser.CSV)
+//	err := result.RenderTable(os.Stdout, fileparser.RenderOptions{MaxRows: 20})
+//
+// Output:
+//
+//	+----+-------+
+//	| id | name  |
+//	+----+-------+
+//	|  1 | Alice |
+//	+----+-------+
+func (t *TableData) RenderTable(w io.Writer, opts RenderOptions) error {
+	if w == nil {
+		return errors.New("writer cannot be nil")
+	}
+
+	border := asciiBorder
+	if opts.Unicode {
+		border = unicodeBorder
+	}
+
+	records := t.Records
+	if opts.MaxRows > 0 && len(records) > opts.MaxRows {
+		records = records[:opts.MaxRows]
+	}
+
+	headers := make([]string, len(t.Headers))
+	for i, header := range t.Headers {
+		headers[i] = truncateDisplay(header, opts.MaxColumnWidth)
+	}
+	cells := make([][]string, len(records))
+	for i, record := range records {
+		cells[i] = make([]string, len(headers))
+		for j := range headers {
+			if j < len(record) {
+				cells[i][j] = truncateDisplay(sanitizeCell(record[j]), opts.MaxColumnWidth)
+			}
+		}
+	}
+
+	widths := make([]int, len(headers))
+	for j, header := range headers {
+		widths[j] = displayWidth(header)
+	}
+	for _, row := range cells {
+		for j, cell := range row {
+			widths[j] = max(widths[j], displayWidth(cell))
+		}
+	}
+
+	rightAlign := make([]bool, len(headers))
+	for j := range headers {
+		if j < len(t.ColumnTypes) {
+			rightAlign[j] = t.ColumnTypes[j] == TypeInteger || t.ColumnTypes[j] == TypeReal
+		}
+	}
+
+	var sb strings.Builder
+	writeBorderLine(&sb, widths, border.topLeft, border.topMid, border.topRight, border.horizontal)
+	writeRow(&sb, headers, widths, make([]bool, len(headers)), border.vertical)
+	writeBorderLine(&sb, widths, border.midLeft, border.midMid, border.midRight, border.horizontal)
+	for _, row := range cells {
+		writeRow(&sb, row, widths, rightAlign, border.vertical)
+	}
+	writeBorderLine(&sb, widths, border.bottomLeft, border.bottomMid, border.bottomRight, border.horizontal)
+	if omitted := len(t.Records) - len(records); omitted > 0 {
+		fmt.Fprintf(&sb, "(%d more rows)\n", omitted)
+	}
+
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return fmt.Errorf("failed to render table: %w", err)
+	}
+	return nil
+}
+
+// writeBorderLine writes a horizontal border line such as "+----+------+".
+func writeBorderLine(w *strings.Builder, widths []int, left, mid, right, horizontal string) {
+	w.WriteString(left)
+	for j, colWidth := range widths {
+		if j > 0 {
+			w.WriteString(mid)
+		}
+		w.WriteString(strings.Repeat(horizontal, colWidth+2))
+	}
+	w.WriteString(right)
+	w.WriteString("\n")
+}
+
+// writeRow writes one table row with each cell padded to its column width.
+func writeRow(w *strings.Builder, cells []string, widths []int, rightAlign []bool, vertical string) {
+	w.WriteString(vertical)
+	for j, cell := range cells {
+		padding := strings.Repeat(" ", widths[j]-displayWidth(cell))
+		w.WriteString(" ")
+		if rightAlign[j] {
+			w.WriteString(padding)
+			w.WriteString(cell)
+		} else {
+			w.WriteString(cell)
+			w.WriteString(padding)
+		}
+		w.WriteString(" ")
+		w.WriteString(vertical)
+	}
+	w.WriteString("\n")
+}
+
+// sanitizeCell replaces line breaks and tabs so a value stays on one line.
+func sanitizeCell(value string) string {
+	return strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ", "\t", " ").Replace(value)
+}
+
+// displayWidth returns the number of terminal columns needed to print s.
+func displayWidth(s string) int {
+	n := 0
+	for _, r := range s {
+		n += runeWidth(r)
+	}
+	return n
+}
+
+// runeWidth returns the number of terminal columns needed to print r.
+func runeWidth(r rune) int {
+	switch width.LookupRune(r).Kind() {
+	case width.EastAsianWide, width.EastAsianFullwidth:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// truncateDisplay shortens s to at most maxWidth terminal columns, ending it
+// with "…" when characters are removed. A maxWidth of zero or less disables
+// truncation.
+func truncateDisplay(s string, maxWidth int) string {
+	if maxWidth <= 0 || displayWidth(s) <= maxWidth {
+		return s
+	}
+
+	var sb strings.Builder
+	used := 0
+	for _, r := range s {
+		rw := runeWidth(r)
+		if used+rw > maxWidth-1 {
+			break
+		}
+		sb.WriteRune(r)
+		used += rw
+	}
+	sb.WriteString("…")
+	return sb.String()
+}
@@ -0,0 +1,123 @@
+package fileparser
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTableData_RenderTable(t *testing.T) {
+	t.Parallel()
+
+	newTable := func() *TableData {
+		return &TableData{
+			Headers: []string{"id", "name", "price"},
+			Records: [][]string{
+				{"1", "Laptop", "999.99"},
+				{"22", "Mouse", "29.9"},
+				{"333", "Keyboard", "5"},
+			},
+			ColumnTypes: []ColumnType{TypeInteger, TypeText, TypeReal},
+		}
+	}
+
+	t.Run("aligns mixed numeric and text columns", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		err := newTable().RenderTable(&buf, RenderOptions{})
+
+		require.NoError(t, err)
+		want := "" +
+			"+-----+----------+--------+\n" +
+			"| id  | name     | price  |\n" +
+			"+-----+----------+--------+\n" +
+			"|   1 | Laptop   | 999.99 |\n" +
+			"|  22 | Mouse    |   29.9 |\n" +
+			"| 333 | Keyboard |      5 |\n" +
+			"+-----+----------+--------+\n"
+		assert.Equal(t, want, buf.String())
+	})
+
+	t.Run("draws unicode borders", func(t *testing.T) {
+		t.Parallel()
+
+		table := &TableData{
+			Headers:     []string{"a"},
+			Records:     [][]string{{"x"}},
+			ColumnTypes: []ColumnType{TypeText},
+		}
+
+		var buf bytes.Buffer
+		err := table.RenderTable(&buf, RenderOptions{Unicode: true})
+
+		require.NoError(t, err)
+		assert.Equal(t, "┌───┐\n│ a │\n├───┤\n│ x │\n└───┘\n", buf.String())
+	})
+
+	t.Run("truncates wide values", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		err := newTable().RenderTable(&buf, RenderOptions{MaxColumnWidth: 5})
+
+		require.NoError(t, err)
+		assert.Contains(t, buf.String(), "| Lapt… |")
+		assert.Contains(t, buf.String(), "| Keyb… |")
+		assert.Contains(t, buf.String(), "| 999.… |")
+	})
+
+	t.Run("limits rows", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		err := newTable().RenderTable(&buf, RenderOptions{MaxRows: 1})
+
+		require.NoError(t, err)
+		assert.Contains(t, buf.String(), "Laptop")
+		assert.NotContains(t, buf.String(), "Mouse")
+		assert.Contains(t, buf.String(), "(2 more rows)\n")
+	})
+
+	t.Run("counts wide characters as two columns", func(t *testing.T) {
+		t.Parallel()
+
+		table := &TableData{
+			Headers:     []string{"name"},
+			Records:     [][]string{{"東京"}, {"ab"}},
+			ColumnTypes: []ColumnType{TypeText},
+		}
+
+		var buf bytes.Buffer
+		err := table.RenderTable(&buf, RenderOptions{})
+
+		require.NoError(t, err)
+		assert.Equal(t, "+------+\n| name |\n+------+\n| 東京 |\n| ab   |\n+------+\n", buf.String())
+	})
+
+	t.Run("returns write error", func(t *testing.T) {
+		t.Parallel()
+
+		err := newTable().RenderTable(errWriter{}, RenderOptions{})
+
+		require.Error(t, err)
+	})
+
+	t.Run("returns error for nil writer", func(t *testing.T) {
+		t.Parallel()
+
+		err := newTable().RenderTable(nil, RenderOptions{})
+
+		require.Error(t, err)
+	})
+}
+
+// errWriter is an io.Writer that always fails.
+type errWriter struct{}
+
+func (errWriter) Write([]byte) (int, error) {
+	return 0, errors.New("write failed")
+}
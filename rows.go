@@ -0,0 +1,193 @@
+package fileparser
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TableRows is a cursor over the records of a TableData, modeled after
+// database/sql.Rows. Call Next to advance to each record and Scan to copy its
+// values into typed variables.
+type TableRows struct {
+	table *TableData
+	next  int
+	row   []string
+}
+
+// Rows returns a cursor over the records of the table. The cursor starts
+// before the first record.
+//
+// Example:
+//
+//	rows := table.Rows()
+//	for rows.Next() {
+//	    var (
+//	        id   int64
+//	        name string
+//	    )
+//	    if err := rows.Scan(&id, &name); err != nil {
+//	        return err
+//	    }
+//	}
+func (t *TableData) Rows() *TableRows {
+	return &TableRows{table: t}
+}
+
+// Columns returns the column names of the table.
+func (r *TableRows) Columns() []string {
+	return slices.Clone(r.table.Headers)
+}
+
+// Next advances the cursor to the next record and reports whether there is
+// one.
+func (r *TableRows) Next() bool {
+	if r.next >= len(r.table.Records) {
+		r.row = nil
+		return false
+	}
+	r.row = r.table.Records[r.next]
+	r.next++
+	return true
+}
+
+// Scan copies the values of the current record into dest, one destination
+// per column in order. Values are converted with ParseValue according to the
+// column types, and the destinations may be:
+//
+//   - *string: the value as stored
+//   - *int64, *float64, *bool: the number or boolean the value holds
+//   - *time.Time: the value parsed as a datetime
+//   - *any: the result of ParseValue, which is nil for an empty value
+//
+// An error is returned if the value cannot be converted to the destination
+// type. Empty values can only be scanned into *string and *any.
+func (r *TableRows) Scan(dest ...any) error {
+	if r.row == nil {
+		return errors.New("no current record: call Next before Scan")
+	}
+	if len(dest) != len(r.table.Headers) {
+		return fmt.Errorf("expected %d destination arguments in Scan, not %d", len(r.table.Headers), len(dest))
+	}
+
+	for i, d := range dest {
+		value := cellAt(r.row, i)
+		if err := scanValue(d, value, columnTypeAt(r.table.ColumnTypes, i)); err != nil {
+			return fmt.Errorf("column %q: %w", r.table.Headers[i], err)
+		}
+	}
+	return nil
+}
+
+// TypedRecords returns the records of the table with every value converted
+// according to its column type: int64 for integers, float64 for reals, bool
+// for booleans, time.Time for datetimes, and string for text. Empty values
+// become nil, and values that cannot be converted stay strings, as with
+// ParseValue. Use TypedRow to convert one record at a time instead of
+// holding a typed copy of the whole table.
+//
+// Example:
+//
+//	for _, row := range table.TypedRecords() {
+//	    amount, _ := row[2].(float64)
+//	    total += amount
+//	}
+func (t *TableData) TypedRecords() [][]any {
+	records := make([][]any, len(t.Records))
+	for i := range t.Records {
+		records[i] = t.TypedRow(i)
+	}
+	return records
+}
+
+// TypedRow returns the record at index i converted like TypedRecords, with
+// one value per column. It returns nil if i is out of range.
+func (t *TableData) TypedRow(i int) []any {
+	if i < 0 || i >= len(t.Records) {
+		return nil
+	}
+	record := t.Records[i]
+	row := make([]any, len(t.Headers))
+	for j := range row {
+		row[j] = typedValue(cellAt(record, j), columnTypeAt(t.ColumnTypes, j))
+	}
+	return row
+}
+
+// typedValue converts value with ParseValue, also parsing datetime values
+// into time.Time.
+func typedValue(value string, colType ColumnType) any {
+	parsed := ParseValue(value, colType)
+	if s, ok := parsed.(string); ok && colType == TypeDatetime {
+		if tm, ok := parseDatetime(s); ok {
+			return tm
+		}
+	}
+	return parsed
+}
+
+// scanValue stores value, a cell of a column of type colType, in dest.
+func scanValue(dest any, value string, colType ColumnType) error {
+	switch d := dest.(type) {
+	case *string:
+		*d = value
+		return nil
+	case *any:
+		*d = ParseValue(value, colType)
+		return nil
+	}
+
+	parsed := ParseValue(value, colType)
+	if parsed == nil {
+		return fmt.Errorf("cannot scan empty value into %T", dest)
+	}
+	trimmed := strings.TrimSpace(value)
+
+	switch d := dest.(type) {
+	case *int64:
+		if n, ok := parsed.(int64); ok {
+			*d = n
+			return nil
+		}
+		n, err := strconv.ParseInt(trimmed, 10, 64)
+		if err != nil {
+			return fmt.Errorf("cannot scan %q into *int64", value)
+		}
+		*d = n
+	case *float64:
+		switch v := parsed.(type) {
+		case float64:
+			*d = v
+		case int64:
+			*d = float64(v)
+		default:
+			f, err := strconv.ParseFloat(trimmed, 64)
+			if err != nil {
+				return fmt.Errorf("cannot scan %q into *float64", value)
+			}
+			*d = f
+		}
+	case *bool:
+		if b, ok := parsed.(bool); ok {
+			*d = b
+			return nil
+		}
+		b, err := strconv.ParseBool(trimmed)
+		if err != nil {
+			return fmt.Errorf("cannot scan %q into *bool", value)
+		}
+		*d = b
+	case *time.Time:
+		tm, ok := parseDatetime(trimmed)
+		if !ok {
+			return fmt.Errorf("cannot scan %q into *time.Time", value)
+		}
+		*d = tm
+	default:
+		return fmt.Errorf("unsupported Scan destination %T", dest)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package fileparser
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTableData_Rows(t *testing.T) {
+	t.Parallel()
+
+	t.Run("scans typed values record by record", func(t *testing.T) {
+		t.Parallel()
+
+		input := "id,name,score,joined,active\n" +
+			"1,Alice,85.5,2024-01-15,true\n" +
+			"2,Bob,92,2024-02-01,false\n"
+		table, err := Parse(strings.NewReader(input), CSV)
+		require.NoError(t, err)
+
+		rows := table.Rows()
+		assert.Equal(t, []string{"id", "name", "score", "joined", "active"}, rows.Columns())
+
+		type row struct {
+			id     int64
+			name   string
+			score  float64
+			joined time.Time
+			active bool
+		}
+		var got []row
+		for rows.Next() {
+			var r row
+			require.NoError(t, rows.Scan(&r.id, &r.name, &r.score, &r.joined, &r.active))
+			got = append(got, r)
+		}
+
+		assert.Equal(t, []row{
+			{id: 1, name: "Alice", score: 85.5, joined: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), active: true},
+			{id: 2, name: "Bob", score: 92, joined: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), active: false},
+		}, got)
+		assert.False(t, rows.Next())
+	})
+
+	t.Run("scans parsed values into any", func(t *testing.T) {
+		t.Parallel()
+
+		table := &TableData{
+			Headers:     []string{"n", "s"},
+			Records:     [][]string{{"", "x"}, {"7", "y"}},
+			ColumnTypes: []ColumnType{TypeInteger, TypeText},
+		}
+
+		rows := table.Rows()
+		var n, s any
+
+		require.True(t, rows.Next())
+		require.NoError(t, rows.Scan(&n, &s))
+		assert.Nil(t, n)
+		assert.Equal(t, "x", s)
+
+		require.True(t, rows.Next())
+		require.NoError(t, rows.Scan(&n, &s))
+		assert.Equal(t, int64(7), n)
+	})
+
+	t.Run("returns errors for invalid scans", func(t *testing.T) {
+		t.Parallel()
+
+		table := &TableData{
+			Headers:     []string{"n", "s"},
+			Records:     [][]string{{"", "abc"}},
+			ColumnTypes: []ColumnType{TypeInteger, TypeText},
+		}
+
+		rows := table.Rows()
+		var (
+			n int64
+			s string
+			f float64
+		)
+
+		require.ErrorContains(t, rows.Scan(&n, &s), "call Next before Scan")
+		require.True(t, rows.Next())
+		require.ErrorContains(t, rows.Scan(&n), "expected 2 destination arguments")
+		require.ErrorContains(t, rows.Scan(&n, &s), `column "n": cannot scan empty value into *int64`)
+		require.ErrorContains(t, rows.Scan(&s, &f), `column "s": cannot scan "abc" into *float64`)
+		require.ErrorContains(t, rows.Scan(&s, &[]byte{}), "unsupported Scan destination")
+	})
+}
+
+func TestTableData_TypedRecords(t *testing.T) {
+	t.Parallel()
+
+	table := &TableData{
+		Headers: []string{"id", "name", "price", "active", "created_at"},
+		Records: [][]string{
+			{"1", "007", "999.5", "true", "2024-01-15 10:30:00"},
+			{"2", "Mouse", "", "false", "unknown"},
+			{"three", "Cable"},
+		},
+		ColumnTypes: []ColumnType{TypeInteger, TypeText, TypeReal, TypeBoolean, TypeDatetime},
+	}
+
+	t.Run("converts every value by its column type", func(t *testing.T) {
+		t.Parallel()
+
+		records := table.TypedRecords()
+
+		require.Len(t, records, 3)
+		assert.Equal(t, []any{int64(1), "007", 999.5, true, time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)}, records[0])
+		assert.Equal(t, []any{int64(2), "Mouse", nil, false, "unknown"}, records[1])
+		assert.Equal(t, []any{"three", "Cable", nil, nil, nil}, records[2])
+	})
+
+	t.Run("converts a single row", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, table.TypedRecords()[1], table.TypedRow(1))
+		assert.Nil(t, table.TypedRow(-1))
+		assert.Nil(t, table.TypedRow(3))
+	})
+}
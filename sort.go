@@ -0,0 +1,154 @@
+package fileparser
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SortKey names a column to sort by and its direction.
+type SortKey struct {
+	// Column is the header name of the column.
+	Column string
+	// Desc sorts the column in descending order instead of ascending.
+	Desc bool
+}
+
+// SortBy sorts the records in ascending order of the named columns. Later
+// columns break ties in earlier ones, and records that compare equal keep
+// their original order. See SortByKeys for how values are compared.
+//
+// Example:
+//
+//	err := table.SortBy("department", "id")
+func (t *TableData) SortBy(columns ...string) error {
+	keys := make([]SortKey, len(columns))
+	for i, column := range columns {
+		keys[i] = SortKey{Column: column}
+	}
+	return t.SortByKeys(keys...)
+}
+
+// SortByKeys sorts the records by the given keys, each ascending or
+// descending. The sort is stable, so the result is deterministic.
+//
+// Values are compared according to the column's type: numerically for
+// TypeInteger and TypeReal, chronologically for TypeDatetime, false before
+// true for TypeBoolean, and lexically for TypeText. Empty values sort before
+// all others, and values that do not parse as the column's type sort after
+// those that do. An error is returned if a column does not exist; the records
+// are then left unchanged.
+//
+// Example:
+//
+//	err := table.SortByKeys(
+//	    fileparser.SortKey{Column: "amount", Desc: true},
+//	    fileparser.SortKey{Column: "id"},
+//	)
+func (t *TableData) SortByKeys(keys ...SortKey) error {
+	indexes := make([]int, len(keys))
+	columnTypes := make([]ColumnType, len(keys))
+	for i, key := range keys {
+		idx, ok := t.ColumnIndex(key.Column)
+		if !ok {
+			return fmt.Errorf("column %q not found", key.Column)
+		}
+		indexes[i] = idx
+		columnTypes[i] = TypeText
+		if idx < len(t.ColumnTypes) {
+			columnTypes[i] = t.ColumnTypes[idx]
+		}
+	}
+
+	slices.SortStableFunc(t.Records, func(a, b []string) int {
+		for i, key := range keys {
+			c := compareCells(cellAt(a, indexes[i]), cellAt(b, indexes[i]), columnTypes[i])
+			if key.Desc {
+				c = -c
+			}
+			if c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+	return nil
+}
+
+// cellAt returns record[idx], or an empty string if the record is too short.
+func cellAt(record []string, idx int) string {
+	if idx < len(record) {
+		return record[idx]
+	}
+	return ""
+}
+
+// compareCells compares two values of a column of type colType.
+func compareCells(a, b string, colType ColumnType) int {
+	a, b = strings.TrimSpace(a), strings.TrimSpace(b)
+	if a == "" || b == "" {
+		return cmp.Compare(len(a), len(b))
+	}
+
+	switch colType {
+	case TypeInteger:
+		x, errA := strconv.ParseInt(a, 10, 64)
+		y, errB := strconv.ParseInt(b, 10, 64)
+		if errA == nil && errB == nil {
+			return cmp.Compare(x, y)
+		}
+		return compareParsed(a, b, parseFloat, cmp.Compare[float64])
+	case TypeReal:
+		return compareParsed(a, b, parseFloat, cmp.Compare[float64])
+	case TypeDatetime:
+		return compareParsed(a, b, parseDatetime, time.Time.Compare)
+	case TypeBoolean:
+		return compareParsed(a, b, parseBool, compareBool)
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+// compareParsed compares a and b as the values returned by parse. Values that
+// parse sort before values that do not; two unparsable values compare lexically.
+func compareParsed[T any](a, b string, parse func(string) (T, bool), compare func(T, T) int) int {
+	x, okA := parse(a)
+	y, okB := parse(b)
+	switch {
+	case okA && okB:
+		return compare(x, y)
+	case okA:
+		return -1
+	case okB:
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+// parseFloat parses s as a float64.
+func parseFloat(s string) (float64, bool) {
+	f, err := strconv.ParseFloat(s, 64)
+	return f, err == nil
+}
+
+// parseBool parses s as a bool.
+func parseBool(s string) (bool, bool) {
+	b, err := strconv.ParseBool(s)
+	return b, err == nil
+}
+
+// compareBool orders false before true.
+func compareBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case !a:
+		return -1
+	default:
+		return 1
+	}
+}
@@ -0,0 +1,102 @@
+package fileparser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTableData_SortBy(t *testing.T) {
+	t.Parallel()
+
+	t.Run("sorts numeric columns numerically", func(t *testing.T) {
+		t.Parallel()
+
+		table, err := Parse(strings.NewReader("id,score\n10,1.5\n9,10.25\n100,-2"), CSV)
+		require.NoError(t, err)
+
+		require.NoError(t, table.SortBy("id"))
+		assert.Equal(t, [][]string{{"9", "10.25"}, {"10", "1.5"}, {"100", "-2"}}, table.Records)
+
+		require.NoError(t, table.SortBy("score"))
+		assert.Equal(t, [][]string{{"100", "-2"}, {"10", "1.5"}, {"9", "10.25"}}, table.Records)
+	})
+
+	t.Run("sorts datetime columns chronologically", func(t *testing.T) {
+		t.Parallel()
+
+		table := &TableData{
+			Headers:     []string{"date"},
+			Records:     [][]string{{"Mar 5, 2024"}, {"Jan 20, 2024"}, {"Feb 1, 2024"}},
+			ColumnTypes: []ColumnType{TypeDatetime},
+		}
+
+		require.NoError(t, table.SortBy("date"))
+		assert.Equal(t, [][]string{{"Jan 20, 2024"}, {"Feb 1, 2024"}, {"Mar 5, 2024"}}, table.Records)
+	})
+
+	t.Run("breaks ties with later columns and keeps equal records stable", func(t *testing.T) {
+		t.Parallel()
+
+		table := &TableData{
+			Headers: []string{"dept", "name", "seq"},
+			Records: [][]string{
+				{"b", "x", "1"},
+				{"a", "y", "2"},
+				{"b", "w", "3"},
+				{"a", "y", "4"},
+			},
+			ColumnTypes: []ColumnType{TypeText, TypeText, TypeInteger},
+		}
+
+		require.NoError(t, table.SortBy("dept", "name"))
+		assert.Equal(t, [][]string{
+			{"a", "y", "2"},
+			{"a", "y", "4"},
+			{"b", "w", "3"},
+			{"b", "x", "1"},
+		}, table.Records)
+	})
+
+	t.Run("sorts descending per key", func(t *testing.T) {
+		t.Parallel()
+
+		table := &TableData{
+			Headers:     []string{"dept", "amount"},
+			Records:     [][]string{{"a", "5"}, {"b", "7"}, {"a", "20"}},
+			ColumnTypes: []ColumnType{TypeText, TypeInteger},
+		}
+
+		err := table.SortByKeys(SortKey{Column: "dept"}, SortKey{Column: "amount", Desc: true})
+
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{{"a", "20"}, {"a", "5"}, {"b", "7"}}, table.Records)
+	})
+
+	t.Run("puts empty values first and unparsable values after numbers", func(t *testing.T) {
+		t.Parallel()
+
+		table := &TableData{
+			Headers:     []string{"n"},
+			Records:     [][]string{{"n/a"}, {"3"}, {""}, {"1"}},
+			ColumnTypes: []ColumnType{TypeInteger},
+		}
+
+		require.NoError(t, table.SortBy("n"))
+		assert.Equal(t, [][]string{{""}, {"1"}, {"3"}, {"n/a"}}, table.Records)
+	})
+
+	t.Run("returns an error for an unknown column", func(t *testing.T) {
+		t.Parallel()
+
+		table := &TableData{Headers: []string{"a"}, Records: [][]string{{"2"}, {"1"}}}
+
+		err := table.SortBy("a", "missing")
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `"missing"`)
+		assert.Equal(t, [][]string{{"2"}, {"1"}}, table.Records)
+	})
+}
@@ -0,0 +1,197 @@
+package fileparser
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// SQLDialect selects the SQL flavor generated by CreateTableSQL and InsertSQL.
+type SQLDialect int
+
+const (
+	// DialectSQLite generates SQL for SQLite. This is the default.
+	DialectSQLite SQLDialect = iota
+	// DialectPostgres generates SQL for PostgreSQL.
+	DialectPostgres
+	// DialectMySQL generates SQL for MySQL and MariaDB.
+	DialectMySQL
+)
+
+// String returns the name of the dialect.
+func (d SQLDialect) String() string {
+	switch d {
+	case DialectSQLite:
+		return "SQLite"
+	case DialectPostgres:
+		return "PostgreSQL"
+	case DialectMySQL:
+		return "MySQL"
+	default:
+		return "unknown"
+	}
+}
+
+// columnType returns the SQL type used for a column of type ct.
+func (d SQLDialect) columnType(ct ColumnType) string {
+	switch ct {
+	case TypeInteger:
+		if d == DialectSQLite {
+			return "INTEGER"
+		}
+		return "BIGINT"
+	case TypeReal:
+		switch d {
+		case DialectPostgres:
+			return "DOUBLE PRECISION"
+		case DialectMySQL:
+			return "DOUBLE"
+		default:
+			return "REAL"
+		}
+	case TypeDatetime:
+		if d == DialectMySQL {
+			return "DATETIME"
+		}
+		return "TIMESTAMP"
+	case TypeBoolean:
+		return "BOOLEAN"
+	default:
+		return "TEXT"
+	}
+}
+
+// quoteIdentifier quotes a table or column name, doubling any quote
+// characters it contains.
+func (d SQLDialect) quoteIdentifier(name string) string {
+	if d == DialectMySQL {
+		return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// quoteString returns s as a string literal.
+func (d SQLDialect) quoteString(s string) string {
+	if d == DialectMySQL {
+		// MySQL treats backslashes in string literals as escape characters
+		s = strings.ReplaceAll(s, `\`, `\\`)
+	}
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// literal returns value, a cell of a column of type ct, as an SQL literal.
+// Empty values become NULL. Numbers and booleans are written unquoted when
+// they parse as the column type; every other value is quoted as a string.
+func (d SQLDialect) literal(value string, ct ColumnType) string {
+	trimmed := strings.TrimSpace(value)
+	if trimmed == "" {
+		return "NULL"
+	}
+
+	switch ct {
+	case TypeInteger:
+		if n, ok := parseInteger(trimmed); ok {
+			return strconv.FormatInt(n, 10)
+		}
+	case TypeReal:
+		if f, err := strconv.ParseFloat(trimmed, 64); err == nil && !math.IsInf(f, 0) && !math.IsNaN(f) {
+			return formatFloat(f, 64)
+		}
+	case TypeBoolean:
+		if b, err := strconv.ParseBool(trimmed); err == nil {
+			switch {
+			case d == DialectSQLite && b:
+				return "1"
+			case d == DialectSQLite:
+				return "0"
+			case b:
+				return "TRUE"
+			default:
+				return "FALSE"
+			}
+		}
+	}
+	return d.quoteString(value)
+}
+
+// CreateTableSQL returns a CREATE TABLE statement for a table named
+// tableName with one column per header, typed according to ColumnTypes for
+// the given dialect. Table and column names are quoted, so any header is a
+// valid column name.
+//
+// Example:
+//
+//	ddl := table.CreateTableSQL("sales", fileparser.DialectPostgres)
+//	// CREATE TABLE "sales" (
+//	//   "id" BIGINT,
+//	//   "amount" DOUBLE PRECISION
+//	// );
+func (t *TableData) CreateTableSQL(tableName string, dialect SQLDialect) string {
+	var sb strings.Builder
+	sb.WriteString("CREATE TABLE ")
+	sb.WriteString(dialect.quoteIdentifier(tableName))
+	sb.WriteString(" (")
+	for i, header := range t.Headers {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString("\n  ")
+		sb.WriteString(dialect.quoteIdentifier(header))
+		sb.WriteByte(' ')
+		sb.WriteString(dialect.columnType(columnTypeAt(t.ColumnTypes, i)))
+	}
+	sb.WriteString("\n);")
+	return sb.String()
+}
+
+// InsertSQL returns INSERT statements that load the records into the table
+// created by CreateTableSQL, with up to batchSize records per statement.
+// Zero or a negative batchSize puts all records into one statement. No
+// statements are returned for a table without records.
+//
+// Values are written as literals: empty values become NULL, numbers and
+// booleans that parse as the column type are unquoted, and all other values
+// are quoted and escaped for the dialect.
+//
+// Example:
+//
+//	for _, stmt := range table.InsertSQL("sales", fileparser.DialectSQLite, 500) {
+//	    if _, err := db.Exec(stmt); err != nil {
+//	        return err
+//	    }
+//	}
+func (t *TableData) InsertSQL(tableName string, dialect SQLDialect, batchSize int) []string {
+	if len(t.Records) == 0 {
+		return nil
+	}
+	if batchSize <= 0 {
+		batchSize = len(t.Records)
+	}
+
+	columns := make([]string, len(t.Headers))
+	for i, header := range t.Headers {
+		columns[i] = dialect.quoteIdentifier(header)
+	}
+	prefix := "INSERT INTO " + dialect.quoteIdentifier(tableName) + " (" + strings.Join(columns, ", ") + ") VALUES"
+
+	statements := make([]string, 0, (len(t.Records)+batchSize-1)/batchSize)
+	values := make([]string, len(t.Headers))
+	for start := 0; start < len(t.Records); start += batchSize {
+		var sb strings.Builder
+		sb.WriteString(prefix)
+		for i, record := range t.Records[start:min(start+batchSize, len(t.Records))] {
+			if i > 0 {
+				sb.WriteByte(',')
+			}
+			for j := range t.Headers {
+				values[j] = dialect.literal(cellAt(record, j), columnTypeAt(t.ColumnTypes, j))
+			}
+			sb.WriteString("\n(")
+			sb.WriteString(strings.Join(values, ", "))
+			sb.WriteByte(')')
+		}
+		sb.WriteByte(';')
+		statements = append(statements, sb.String())
+	}
+	return statements
+}
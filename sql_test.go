@@ -0,0 +1,109 @@
+package fileparser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTableData_CreateTableSQL(t *testing.T) {
+	t.Parallel()
+
+	table := &TableData{
+		Headers:     []string{"id", "amount", "paid_at", "ok", `the "note"`},
+		ColumnTypes: []ColumnType{TypeInteger, TypeReal, TypeDatetime, TypeBoolean, TypeText},
+	}
+
+	tests := []struct {
+		dialect SQLDialect
+		want    string
+	}{
+		{
+			dialect: DialectSQLite,
+			want: "CREATE TABLE \"sales\" (\n" +
+				"  \"id\" INTEGER,\n" +
+				"  \"amount\" REAL,\n" +
+				"  \"paid_at\" TIMESTAMP,\n" +
+				"  \"ok\" BOOLEAN,\n" +
+				"  \"the \"\"note\"\"\" TEXT\n" +
+				");",
+		},
+		{
+			dialect: DialectPostgres,
+			want: "CREATE TABLE \"sales\" (\n" +
+				"  \"id\" BIGINT,\n" +
+				"  \"amount\" DOUBLE PRECISION,\n" +
+				"  \"paid_at\" TIMESTAMP,\n" +
+				"  \"ok\" BOOLEAN,\n" +
+				"  \"the \"\"note\"\"\" TEXT\n" +
+				");",
+		},
+		{
+			dialect: DialectMySQL,
+			want: "CREATE TABLE `sales` (\n" +
+				"  `id` BIGINT,\n" +
+				"  `amount` DOUBLE,\n" +
+				"  `paid_at` DATETIME,\n" +
+				"  `ok` BOOLEAN,\n" +
+				"  `the \"note\"` TEXT\n" +
+				");",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dialect.String(), func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, table.CreateTableSQL("sales", tt.dialect))
+		})
+	}
+}
+
+func TestTableData_InsertSQL(t *testing.T) {
+	t.Parallel()
+
+	table := &TableData{
+		Headers: []string{"id", "score", "ok", "name"},
+		Records: [][]string{
+			{"1", "1.50", "true", "O'Brien"},
+			{"2", "", "FALSE", `C:\tmp`},
+			{"n/a", "NaN", "maybe", ""},
+		},
+		ColumnTypes: []ColumnType{TypeInteger, TypeReal, TypeBoolean, TypeText},
+	}
+
+	t.Run("batches records and writes typed literals", func(t *testing.T) {
+		t.Parallel()
+
+		got := table.InsertSQL("t", DialectSQLite, 2)
+
+		assert.Equal(t, []string{
+			"INSERT INTO \"t\" (\"id\", \"score\", \"ok\", \"name\") VALUES\n" +
+				"(1, 1.5, 1, 'O''Brien'),\n" +
+				"(2, NULL, 0, 'C:\\tmp');",
+			"INSERT INTO \"t\" (\"id\", \"score\", \"ok\", \"name\") VALUES\n" +
+				"('n/a', 'NaN', 'maybe', NULL);",
+		}, got)
+	})
+
+	t.Run("escapes for MySQL and puts all records in one statement", func(t *testing.T) {
+		t.Parallel()
+
+		got := table.InsertSQL("t", DialectMySQL, 0)
+
+		assert.Equal(t, []string{
+			"INSERT INTO `t` (`id`, `score`, `ok`, `name`) VALUES\n" +
+				"(1, 1.5, TRUE, 'O''Brien'),\n" +
+				"(2, NULL, FALSE, 'C:\\\\tmp'),\n" +
+				"('n/a', 'NaN', 'maybe', NULL);",
+		}, got)
+	})
+
+	t.Run("returns nothing for a table without records", func(t *testing.T) {
+		t.Parallel()
+
+		empty := &TableData{Headers: []string{"a"}, ColumnTypes: []ColumnType{TypeText}}
+
+		assert.Empty(t, empty.InsertSQL("t", DialectPostgres, 10))
+	})
+}
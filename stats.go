@@ -0,0 +1,96 @@
+package fileparser
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ColumnStats summarizes the values of one column, as returned by
+// TableData.ColumnStats.
+type ColumnStats struct {
+	// Name is the column name.
+	Name string
+	// Type is the column type recorded in the table.
+	Type ColumnType
+	// Count is the number of records, including those with an empty value.
+	Count int
+	// EmptyCount is the number of records whose value is empty or consists
+	// only of whitespace. Records too short to hold the column count as empty.
+	EmptyCount int
+	// DistinctCount is the number of distinct non-empty values.
+	DistinctCount int
+
+	// Numeric reports whether Min, Max, Sum, and Mean are set. It is true for
+	// TypeInteger and TypeReal columns with at least one numeric value.
+	Numeric bool
+	// Min is the smallest numeric value.
+	Min float64
+	// Max is the largest numeric value.
+	Max float64
+	// Sum is the sum of the numeric values.
+	Sum float64
+	// Mean is the arithmetic mean of the numeric values. Values that do not
+	// parse as numbers are not included.
+	Mean float64
+}
+
+// ColumnStats computes summary statistics for the column named name, such as
+// its empty and distinct value counts and, for TypeInteger and TypeReal
+// columns, the minimum, maximum, sum, and mean. An error is returned if there
+// is no such column.
+//
+// Example:
+//
+//	stats, err := table.ColumnStats("amount")
+//	if stats.Numeric {
+//	    fmt.Printf("mean %.2f over %d values\n", stats.Mean, stats.Count-stats.EmptyCount)
+//	}
+func (t *TableData) ColumnStats(name string) (ColumnStats, error) {
+	values, err := t.Column(name)
+	if err != nil {
+		return ColumnStats{}, err
+	}
+	idx, _ := t.ColumnIndex(name)
+
+	stats := ColumnStats{
+		Name:  name,
+		Type:  columnTypeAt(t.ColumnTypes, idx),
+		Count: len(values),
+	}
+	numeric := stats.Type == TypeInteger || stats.Type == TypeReal
+
+	distinct := make(map[string]struct{})
+	numericCount := 0
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			stats.EmptyCount++
+			continue
+		}
+		distinct[value] = struct{}{}
+
+		if !numeric {
+			continue
+		}
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			continue
+		}
+		if numericCount == 0 || f < stats.Min {
+			stats.Min = f
+		}
+		if numericCount == 0 || f > stats.Max {
+			stats.Max = f
+		}
+		stats.Sum += f
+		numericCount++
+	}
+	stats.DistinctCount = len(distinct)
+
+	if numericCount > 0 {
+		stats.Numeric = true
+		stats.Mean = stats.Sum / float64(numericCount)
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,72 @@
+package fileparser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTableData_ColumnStats(t *testing.T) {
+	t.Parallel()
+
+	table, err := Parse(strings.NewReader("id,amount,name\n1,10.5,Alice\n2,,Bob\n3,-2.5,Alice\n4,10.5,"), CSV)
+	require.NoError(t, err)
+
+	t.Run("reports numeric statistics for real columns", func(t *testing.T) {
+		t.Parallel()
+
+		stats, err := table.ColumnStats("amount")
+
+		require.NoError(t, err)
+		assert.Equal(t, ColumnStats{
+			Name:          "amount",
+			Type:          TypeReal,
+			Count:         4,
+			EmptyCount:    1,
+			DistinctCount: 2,
+			Numeric:       true,
+			Min:           -2.5,
+			Max:           10.5,
+			Sum:           18.5,
+			Mean:          18.5 / 3,
+		}, stats)
+	})
+
+	t.Run("reports numeric statistics for integer columns", func(t *testing.T) {
+		t.Parallel()
+
+		stats, err := table.ColumnStats("id")
+
+		require.NoError(t, err)
+		assert.True(t, stats.Numeric)
+		assert.Equal(t, 1.0, stats.Min)
+		assert.Equal(t, 4.0, stats.Max)
+		assert.Equal(t, 2.5, stats.Mean)
+		assert.Equal(t, 4, stats.DistinctCount)
+	})
+
+	t.Run("leaves numeric statistics unset for text columns", func(t *testing.T) {
+		t.Parallel()
+
+		stats, err := table.ColumnStats("name")
+
+		require.NoError(t, err)
+		assert.Equal(t, ColumnStats{
+			Name:          "name",
+			Type:          TypeText,
+			Count:         4,
+			EmptyCount:    1,
+			DistinctCount: 2,
+		}, stats)
+	})
+
+	t.Run("returns an error for an unknown column", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := table.ColumnStats("missing")
+
+		require.Error(t, err)
+	})
+}
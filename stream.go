@@ -0,0 +1,159 @@
+package fileparser
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// RowFunc is called by ParseStream for each data record.
+// headers is the header row of the file and is the same slice for every call.
+// Returning a non-nil error stops iteration.
+type RowFunc func(record []string, headers []string) error
+
+// ParseStream reads delimited data row by row and invokes fn for each data record
+// without accumulating records in memory. The first row is treated as the header.
+//
+// CSV, TSV, and XLSX (including their compressed variants) are supported.
+// If fn returns an error, iteration stops and that error is returned unchanged.
+//
+// XLSX rows of the first sheet are read one at a time with excelize's row
+// iterator, so only the compressed workbook and the current row are held in
+// memory instead of every cell of the sheet. Records are padded or truncated
+// to the number of headers. Cells hold the text excelize displays for them:
+// unlike Parse, ParseStream does not normalize date serials, booleans, or
+// merged cells, and formula cells without a cached value are empty.
+//
+// Example:
+//
+//	f, _ := os.Open("huge.csv.gz")
+//	defer f.Close()
+//	err := fileparser.ParseStream(f, fileparser.CSVGZ, func(record, headers []string) error {
+//	    fmt.Println(record)
+//	    return nil
+//	})
+func ParseStream(reader io.Reader, fileType FileType, fn RowFunc) (err error) {
+	if reader == nil {
+		return errors.New("reader cannot be nil")
+	}
+	if fn == nil {
+		return errors.New("row function cannot be nil")
+	}
+
+	baseType := BaseFileType(fileType)
+	var delimiter rune
+	switch baseType {
+	case CSV:
+		delimiter = ','
+	case TSV:
+		delimiter = '\t'
+	case XLSX:
+		// Read row by row by streamXLSX below
+	default:
+		return withKind(fmt.Errorf("streaming is not supported for %s", fileType), ErrUnsupportedType)
+	}
+
+	decompressedReader, closeFunc, decompErr := createDecompressedReader(reader, fileType)
+	if decompErr != nil {
+		return fmt.Errorf("%w: %w", ErrDecompression, decompErr)
+	}
+	if closeFunc != nil {
+		defer func() {
+			if closeErr := closeFunc(); closeErr != nil && err == nil {
+				err = fmt.Errorf("failed to close decompressor: %w", closeErr)
+			}
+		}()
+	}
+
+	if baseType == XLSX {
+		return streamXLSX(decompressedReader, fn)
+	}
+	return streamDelimited(decompressedReader, delimiter, baseType.String(), fn)
+}
+
+// streamXLSX reads the rows of the first sheet of an XLSX workbook one at a
+// time and passes them to fn.
+func streamXLSX(reader io.Reader, fn RowFunc) (err error) {
+	f, err := openXLSX(reader, "")
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	sheets := f.GetSheetList()
+	if len(sheets) == 0 {
+		return withKind(errors.New("no sheets found in XLSX"), ErrEmptyData)
+	}
+	rows, err := f.Rows(sheets[0])
+	if err != nil {
+		return fmt.Errorf("failed to read sheet %s: %w", sheets[0], err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("failed to close sheet %s: %w", sheets[0], closeErr)
+		}
+	}()
+
+	if !rows.Next() {
+		if err := rows.Error(); err != nil {
+			return fmt.Errorf("failed to read sheet %s: %w", sheets[0], err)
+		}
+		return withKind(errors.New("empty XLSX sheet"), ErrEmptyData)
+	}
+	headers, err := rows.Columns()
+	if err != nil {
+		return fmt.Errorf("failed to read sheet %s: %w", sheets[0], err)
+	}
+	if len(headers) == 0 {
+		return withKind(errors.New("no headers found in XLSX"), ErrEmptyData)
+	}
+	if err := validateColumnNames(headers); err != nil {
+		return err
+	}
+
+	for rows.Next() {
+		record, err := rows.Columns()
+		if err != nil {
+			return fmt.Errorf("failed to read sheet %s: %w", sheets[0], err)
+		}
+		if err := fn(fitRecord(record, len(headers)), headers); err != nil {
+			return err
+		}
+	}
+	if err := rows.Error(); err != nil {
+		return fmt.Errorf("failed to read sheet %s: %w", sheets[0], err)
+	}
+	return nil
+}
+
+// streamDelimited reads CSV or TSV records one at a time and passes them to fn.
+func streamDelimited(reader io.Reader, delimiter rune, fileTypeName string, fn RowFunc) error {
+	csvReader := csv.NewReader(newBOMAwareReader(reader))
+	csvReader.Comma = delimiter
+
+	headers, err := csvReader.Read()
+	if errors.Is(err, io.EOF) {
+		return withKind(fmt.Errorf("empty %s data", fileTypeName), ErrEmptyData)
+	}
+	if err != nil {
+		return delimitedReadError(fileTypeName, err)
+	}
+
+	if err := validateColumnNames(headers); err != nil {
+		return err
+	}
+
+	for {
+		record, err := csvReader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return delimitedReadError(fileTypeName, err)
+		}
+		if err := fn(record, headers); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package fileparser
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestParseStream(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invokes callback for each CSV row", func(t *testing.T) {
+		t.Parallel()
+
+		input := "name,age\nAlice,30\nBob,25\nCharlie,35"
+
+		var got [][]string
+		var gotHeaders []string
+		err := ParseStream(strings.NewReader(input), CSV, func(record, headers []string) error {
+			got = append(got, record)
+			gotHeaders = headers
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"name", "age"}, gotHeaders)
+		assert.Equal(t, [][]string{{"Alice", "30"}, {"Bob", "25"}, {"Charlie", "35"}}, got)
+	})
+
+	t.Run("streams TSV", func(t *testing.T) {
+		t.Parallel()
+
+		input := "id\tname\n1\tLaptop\n2\tMouse"
+
+		count := 0
+		err := ParseStream(strings.NewReader(input), TSV, func(record, _ []string) error {
+			count++
+			assert.Len(t, record, 2)
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+	})
+
+	t.Run("streams compressed CSV", func(t *testing.T) {
+		t.Parallel()
+
+		f, err := os.Open(filepath.Join("testdata", "sample.csv.gz"))
+		require.NoError(t, err)
+		defer f.Close()
+
+		count := 0
+		err = ParseStream(f, CSVGZ, func(_, headers []string) error {
+			count++
+			assert.Equal(t, []string{"id", "name", "age", "email"}, headers)
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, count)
+	})
+
+	t.Run("stops and propagates callback error", func(t *testing.T) {
+		t.Parallel()
+
+		input := "v\n1\n2\n3\n4"
+		errStop := errors.New("stop")
+
+		count := 0
+		err := ParseStream(strings.NewReader(input), CSV, func(record, _ []string) error {
+			count++
+			if record[0] == "2" {
+				return errStop
+			}
+			return nil
+		})
+
+		require.ErrorIs(t, err, errStop)
+		assert.Equal(t, 2, count)
+	})
+
+	t.Run("header only input produces no callbacks", func(t *testing.T) {
+		t.Parallel()
+
+		called := false
+		err := ParseStream(strings.NewReader("a,b\n"), CSV, func(_, _ []string) error {
+			called = true
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.False(t, called)
+	})
+
+	t.Run("returns error for empty input", func(t *testing.T) {
+		t.Parallel()
+
+		err := ParseStream(strings.NewReader(""), CSV, func(_, _ []string) error { return nil })
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "empty CSV data")
+	})
+
+	t.Run("returns error for duplicate column names", func(t *testing.T) {
+		t.Parallel()
+
+		err := ParseStream(strings.NewReader("a,a\n1,2"), CSV, func(_, _ []string) error { return nil })
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "duplicate column name")
+	})
+
+	t.Run("returns error for unsupported type", func(t *testing.T) {
+		t.Parallel()
+
+		err := ParseStream(strings.NewReader("a:1"), LTSV, func(_, _ []string) error { return nil })
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "streaming is not supported")
+	})
+
+	t.Run("returns error for nil reader and nil callback", func(t *testing.T) {
+		t.Parallel()
+
+		require.Error(t, ParseStream(nil, CSV, func(_, _ []string) error { return nil }))
+		require.Error(t, ParseStream(strings.NewReader("a\n1"), CSV, nil))
+	})
+}
+
+func TestParseStream_XLSX(t *testing.T) {
+	t.Parallel()
+
+	f := excelize.NewFile()
+	defer f.Close()
+	require.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]any{"id", "name", "note"}))
+	require.NoError(t, f.SetSheetRow("Sheet1", "A2", &[]any{1, "Laptop", "new"}))
+	require.NoError(t, f.SetSheetRow("Sheet1", "A3", &[]any{2, "Mouse"}))
+	var buf bytes.Buffer
+	require.NoError(t, f.Write(&buf))
+	data := buf.Bytes()
+
+	t.Run("invokes callback for each row of the first sheet", func(t *testing.T) {
+		t.Parallel()
+
+		var got [][]string
+		var gotHeaders []string
+		err := ParseStream(bytes.NewReader(data), XLSX, func(record, headers []string) error {
+			got = append(got, record)
+			gotHeaders = headers
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"id", "name", "note"}, gotHeaders)
+		assert.Equal(t, [][]string{{"1", "Laptop", "new"}, {"2", "Mouse", ""}}, got)
+	})
+
+	t.Run("streams compressed XLSX", func(t *testing.T) {
+		t.Parallel()
+
+		var compressed bytes.Buffer
+		gw := gzip.NewWriter(&compressed)
+		_, err := gw.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, gw.Close())
+
+		count := 0
+		err = ParseStream(&compressed, XLSXGZ, func(_, _ []string) error {
+			count++
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+	})
+
+	t.Run("stops and propagates callback error", func(t *testing.T) {
+		t.Parallel()
+
+		errStop := errors.New("stop")
+		count := 0
+		err := ParseStream(bytes.NewReader(data), XLSX, func(_, _ []string) error {
+			count++
+			return errStop
+		})
+
+		require.ErrorIs(t, err, errStop)
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("returns error for empty sheet", func(t *testing.T) {
+		t.Parallel()
+
+		empty := excelize.NewFile()
+		defer empty.Close()
+		var emptyBuf bytes.Buffer
+		require.NoError(t, empty.Write(&emptyBuf))
+
+		err := ParseStream(&emptyBuf, XLSX, func(_, _ []string) error { return nil })
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "empty XLSX sheet")
+	})
+}
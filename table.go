@@ -0,0 +1,489 @@
+package fileparser
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Validate checks that the table is structurally consistent: ColumnTypes has
+// one entry per header, every record has exactly one value per header, and
+// no header name appears twice. When PrimaryKey is set, its columns must
+// exist and no two records may have the same values in them. It returns an
+// error describing the first problem found. Tables built by hand or modified
+// directly may violate these invariants, which code indexing Records by
+// header position relies on.
+func (t *TableData) Validate() error {
+	if err := validateColumnNames(t.Headers); err != nil {
+		return err
+	}
+	if len(t.ColumnTypes) != len(t.Headers) {
+		return fmt.Errorf("table has %d column types for %d headers", len(t.ColumnTypes), len(t.Headers))
+	}
+	for i, record := range t.Records {
+		if len(record) != len(t.Headers) {
+			return fmt.Errorf("record %d has %d fields, want %d", i+1, len(record), len(t.Headers))
+		}
+	}
+	return t.validatePrimaryKey()
+}
+
+// validatePrimaryKey checks that the PrimaryKey columns exist and that their
+// values are unique across records.
+func (t *TableData) validatePrimaryKey() error {
+	if len(t.PrimaryKey) == 0 {
+		return nil
+	}
+	if err := validateColumnNames(t.PrimaryKey); err != nil {
+		return fmt.Errorf("invalid primary key: %w", err)
+	}
+	indexes, err := t.columnIndexes(t.PrimaryKey)
+	if err != nil {
+		return fmt.Errorf("invalid primary key: %w", err)
+	}
+
+	seen := make(map[string]int, len(t.Records))
+	values := make([]string, len(indexes))
+	for i, record := range t.Records {
+		for j, idx := range indexes {
+			values[j] = cellAt(record, idx)
+		}
+		key := recordKey(values)
+		if first, ok := seen[key]; ok {
+			return fmt.Errorf("record %d has the same primary key as record %d: %s", i+1, first, strings.Join(values, ", "))
+		}
+		seen[key] = i + 1
+	}
+	return nil
+}
+
+// columnIndexes returns the positions of the named columns in Headers.
+// An error is returned if a name does not match a column.
+func (t *TableData) columnIndexes(columns []string) ([]int, error) {
+	indexes := make([]int, len(columns))
+	for i, column := range columns {
+		idx, ok := t.ColumnIndex(column)
+		if !ok {
+			return nil, fmt.Errorf("column %q not found", column)
+		}
+		indexes[i] = idx
+	}
+	return indexes, nil
+}
+
+// primaryKeyWithin returns a copy of the PrimaryKey of t if every key column
+// is in headers, and nil otherwise.
+func (t *TableData) primaryKeyWithin(headers []string) []string {
+	for _, column := range t.PrimaryKey {
+		if !slices.Contains(headers, column) {
+			return nil
+		}
+	}
+	return slices.Clone(t.PrimaryKey)
+}
+
+// ColumnIndex returns the position of the column named name in Headers,
+// and false if there is no such column.
+func (t *TableData) ColumnIndex(name string) (int, bool) {
+	idx := slices.Index(t.Headers, name)
+	return idx, idx >= 0
+}
+
+// Column returns the values of the column named name, one per record.
+// Records too short to hold the column contribute an empty string.
+// An error is returned if there is no such column.
+//
+// Example:
+//
+//	amounts, err := table.Column("amount")
+func (t *TableData) Column(name string) ([]string, error) {
+	idx, ok := t.ColumnIndex(name)
+	if !ok {
+		return nil, fmt.Errorf("column %q not found", name)
+	}
+
+	values := make([]string, len(t.Records))
+	for i, record := range t.Records {
+		if idx < len(record) {
+			values[i] = record[idx]
+		}
+	}
+	return values, nil
+}
+
+// SelectColumns returns a new table holding only the named columns, in the
+// requested order, along with their column types. The original table is not
+// modified. An error is returned if a name does not match a column or is
+// requested twice.
+//
+// Example:
+//
+//	subset, err := table.SelectColumns("id", "amount")
+func (t *TableData) SelectColumns(names ...string) (*TableData, error) {
+	if err := validateColumnNames(names); err != nil {
+		return nil, err
+	}
+
+	indexes := make([]int, len(names))
+	columnTypes := make([]ColumnType, len(names))
+	for i, name := range names {
+		idx, ok := t.ColumnIndex(name)
+		if !ok {
+			return nil, fmt.Errorf("column %q not found", name)
+		}
+		indexes[i] = idx
+		columnTypes[i] = TypeText
+		if idx < len(t.ColumnTypes) {
+			columnTypes[i] = t.ColumnTypes[idx]
+		}
+	}
+
+	records := make([][]string, len(t.Records))
+	for i, record := range t.Records {
+		selected := make([]string, len(indexes))
+		for j, idx := range indexes {
+			if idx < len(record) {
+				selected[j] = record[idx]
+			}
+		}
+		records[i] = selected
+	}
+
+	return &TableData{
+		Headers:     slices.Clone(names),
+		Records:     records,
+		ColumnTypes: columnTypes,
+		PrimaryKey:  t.primaryKeyWithin(names),
+	}, nil
+}
+
+// AppendRows appends the records of other to the table, e.g. to combine
+// daily exports that share a schema. The headers of both tables must match
+// exactly, with the same names in the same order. Where the column types
+// differ, the type is widened to one that holds both: TypeInteger and
+// TypeReal give TypeReal, any other combination gives TypeText.
+//
+// An error describing the first mismatch is returned if the headers differ;
+// the table is then left unchanged.
+//
+// Example:
+//
+//	combined, _ := fileparser.Parse(monday, fileparser.CSV)
+//	tuesdayTable, _ := fileparser.Parse(tuesday, fileparser.CSV)
+//	err := combined.AppendRows(tuesdayTable)
+func (t *TableData) AppendRows(other *TableData) error {
+	if other == nil {
+		return errors.New("table to append cannot be nil")
+	}
+	if len(t.Headers) != len(other.Headers) {
+		return fmt.Errorf("headers do not match: %d columns, but the appended table has %d",
+			len(t.Headers), len(other.Headers))
+	}
+	for i, header := range t.Headers {
+		if header != other.Headers[i] {
+			return fmt.Errorf("headers do not match: column %d is %q, but %q in the appended table",
+				i+1, header, other.Headers[i])
+		}
+	}
+
+	columnTypes := make([]ColumnType, len(t.Headers))
+	for i := range columnTypes {
+		columnTypes[i] = widenColumnType(columnTypeAt(t.ColumnTypes, i), columnTypeAt(other.ColumnTypes, i))
+	}
+
+	for _, record := range other.Records {
+		t.Records = append(t.Records, slices.Clone(record))
+	}
+	t.ColumnTypes = columnTypes
+
+	return nil
+}
+
+// Distinct returns a new table without exact duplicate records. The first
+// occurrence of each record is kept, so the order of the remaining records is
+// unchanged. Headers and column types are carried over; the original table is
+// not modified.
+//
+// Example:
+//
+//	combined.AppendRows(other)
+//	unique := combined.Distinct()
+func (t *TableData) Distinct() *TableData {
+	seen := make(map[string]bool, len(t.Records))
+	records := make([][]string, 0, len(t.Records))
+	for _, record := range t.Records {
+		key := recordKey(record)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		records = append(records, slices.Clone(record))
+	}
+
+	return &TableData{
+		Headers:     slices.Clone(t.Headers),
+		Records:     records,
+		ColumnTypes: slices.Clone(t.ColumnTypes),
+		PrimaryKey:  slices.Clone(t.PrimaryKey),
+	}
+}
+
+// DistinctBy is like Distinct, but treats records as duplicates when they
+// have the same values in the named columns, keeping the first such record
+// whole. Without columns, the PrimaryKey columns are used. An error is
+// returned if a name does not match a column.
+//
+// Example:
+//
+//	latest, err := table.DistinctBy("customer_id")
+func (t *TableData) DistinctBy(columns ...string) (*TableData, error) {
+	if len(columns) == 0 {
+		columns = t.PrimaryKey
+	}
+	indexes, err := t.columnIndexes(columns)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(t.Records))
+	records := make([][]string, 0, len(t.Records))
+	values := make([]string, len(indexes))
+	for _, record := range t.Records {
+		for i, idx := range indexes {
+			values[i] = cellAt(record, idx)
+		}
+		key := recordKey(values)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		records = append(records, slices.Clone(record))
+	}
+
+	return &TableData{
+		Headers:     slices.Clone(t.Headers),
+		Records:     records,
+		ColumnTypes: slices.Clone(t.ColumnTypes),
+		PrimaryKey:  slices.Clone(t.PrimaryKey),
+	}, nil
+}
+
+// recordKey encodes values into a string that differs for any two different
+// value lists, for use as a map key.
+func recordKey(values []string) string {
+	var key []byte
+	for _, value := range values {
+		key = strconv.AppendQuote(key, value)
+	}
+	return string(key)
+}
+
+// columnTypeAt returns columnTypes[i], or TypeText if it is missing.
+func columnTypeAt(columnTypes []ColumnType, i int) ColumnType {
+	if i < len(columnTypes) {
+		return columnTypes[i]
+	}
+	return TypeText
+}
+
+// widenColumnType returns a column type that holds values of both a and b.
+func widenColumnType(a, b ColumnType) ColumnType {
+	switch {
+	case a == b:
+		return a
+	case (a == TypeInteger || a == TypeReal) && (b == TypeInteger || b == TypeReal):
+		return TypeReal
+	default:
+		return TypeText
+	}
+}
+
+// AddColumn appends a new column named name to the table. The value of the
+// column for each record is computed by fn, which receives the record as a map
+// from header name to value. colType is recorded as the column's type.
+//
+// An error is returned if name collides with an existing column.
+//
+// Example:
+//
+//	err := table.AddColumn("net", func(row map[string]string) string {
+//	    credit, _ := strconv.Atoi(row["total_credit"])
+//	    debit, _ := strconv.Atoi(row["total_debit"])
+//	    return strconv.Itoa(credit - debit)
+//	}, fileparser.TypeInteger)
+func (t *TableData) AddColumn(name string, fn func(row map[string]string) string, colType ColumnType) error {
+	if fn == nil {
+		return errors.New("column function cannot be nil")
+	}
+
+	headers := append(slices.Clone(t.Headers), name)
+	if err := validateColumnNames(headers); err != nil {
+		return err
+	}
+
+	values := make([]string, len(t.Records))
+	for i, record := range t.Records {
+		row := make(map[string]string, len(t.Headers))
+		for j, header := range t.Headers {
+			if j < len(record) {
+				row[header] = record[j]
+			} else {
+				row[header] = ""
+			}
+		}
+		values[i] = fn(row)
+	}
+
+	for i, record := range t.Records {
+		// Pad short records so the new value lands in the new column
+		normalized := make([]string, len(t.Headers), len(headers))
+		copy(normalized, record)
+		t.Records[i] = append(normalized, values[i])
+	}
+	t.Headers = headers
+	t.ColumnTypes = append(t.ColumnTypes, colType)
+
+	return nil
+}
+
+// MapColumn replaces every value of the column named name with the result of
+// fn, in place, and infers the column's type again from the new values. A
+// record too short to hold the column is padded with empty strings, and fn
+// receives an empty string for it. An error is returned if there is no such
+// column.
+//
+// Example:
+//
+//	err := table.MapColumn("email", strings.ToLower)
+func (t *TableData) MapColumn(name string, fn func(string) string) error {
+	if fn == nil {
+		return errors.New("column function cannot be nil")
+	}
+	idx, ok := t.ColumnIndex(name)
+	if !ok {
+		return fmt.Errorf("column %q not found", name)
+	}
+
+	for i, record := range t.Records {
+		if idx >= len(record) {
+			padded := make([]string, len(t.Headers))
+			copy(padded, record)
+			record = padded
+			t.Records[i] = record
+		}
+		record[idx] = fn(record[idx])
+	}
+
+	for len(t.ColumnTypes) < len(t.Headers) {
+		t.ColumnTypes = append(t.ColumnTypes, TypeText)
+	}
+	t.ColumnTypes[idx] = inferColumnType(t.Records, idx)
+	return nil
+}
+
+// ToCSV returns the table as CSV text, starting with the header row. Fields
+// containing commas, quotes, or line breaks are quoted. It is meant for
+// debugging and logging; use Write to stream large tables.
+func (t *TableData) ToCSV() (string, error) {
+	return t.toDelimited(CSV)
+}
+
+// ToTSV returns the table as TSV text, starting with the header row.
+// Fields containing tabs, quotes, or line breaks are quoted.
+func (t *TableData) ToTSV() (string, error) {
+	return t.toDelimited(TSV)
+}
+
+// toDelimited returns the table as CSV or TSV text.
+func (t *TableData) toDelimited(fileType FileType) (string, error) {
+	var sb strings.Builder
+	if err := writeUncompressed(&sb, t, fileType, WriteOptions{}); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// Transpose returns a new table that pivots the rows of t into columns. The
+// values of the first column become the headers, after the name of the first
+// column, and every remaining column becomes a row whose first value is the
+// column's name. Column types are inferred again from the new rows. This
+// turns a one-record table such as the ACH file_header table, or a key/value
+// file, into one row per field. The original table is not modified.
+//
+// A key that repeats an earlier header gets a numeric suffix, e.g. the second
+// "port" becomes "port_2", so the headers stay unique. Records too short to
+// hold a column contribute an empty string.
+//
+// Example:
+//
+//	// key,value        key,host,port
+//	// host,example  => value,example,8080
+//	// port,8080
+//	fields := table.Transpose()
+func (t *TableData) Transpose() *TableData {
+	if len(t.Headers) == 0 {
+		return &TableData{Headers: []string{}, Records: [][]string{}, ColumnTypes: []ColumnType{}}
+	}
+
+	headers := make([]string, 0, len(t.Records)+1)
+	headers = append(headers, t.Headers[0])
+	seen := map[string]bool{t.Headers[0]: true}
+	for _, record := range t.Records {
+		key := cellAt(record, 0)
+		name := key
+		for n := 2; seen[name]; n++ {
+			name = fmt.Sprintf("%s_%d", key, n)
+		}
+		seen[name] = true
+		headers = append(headers, name)
+	}
+
+	records := make([][]string, 0, len(t.Headers)-1)
+	for col, header := range t.Headers[1:] {
+		record := make([]string, 0, len(headers))
+		record = append(record, header)
+		for _, row := range t.Records {
+			record = append(record, cellAt(row, col+1))
+		}
+		records = append(records, record)
+	}
+
+	return &TableData{
+		Headers:     headers,
+		Records:     records,
+		ColumnTypes: inferColumnTypes(headers, records),
+	}
+}
+
+// Head returns a table holding at most the first n records of t, with the
+// same headers and column types. Zero or a negative n returns a table without
+// records. The copy is shallow: the records share their values with t.
+//
+// Example:
+//
+//	preview := table.Head(10)
+func (t *TableData) Head(n int) *TableData {
+	n = min(max(n, 0), len(t.Records))
+	return t.withRecords(t.Records[:n])
+}
+
+// Tail returns a table holding at most the last n records of t, with the
+// same headers and column types. Zero or a negative n returns a table without
+// records. The copy is shallow: the records share their values with t.
+func (t *TableData) Tail(n int) *TableData {
+	n = min(max(n, 0), len(t.Records))
+	return t.withRecords(t.Records[len(t.Records)-n:])
+}
+
+// withRecords returns a table with the headers, column types, and primary
+// key of t and a copy of the records slice.
+func (t *TableData) withRecords(records [][]string) *TableData {
+	return &TableData{
+		Headers:     slices.Clone(t.Headers),
+		Records:     append(make([][]string, 0, len(records)), records...),
+		ColumnTypes: slices.Clone(t.ColumnTypes),
+		PrimaryKey:  slices.Clone(t.PrimaryKey),
+	}
+}
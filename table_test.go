@@ -0,0 +1,608 @@
+package fileparser
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTableData_AddColumn(t *testing.T) {
+	t.Parallel()
+
+	t.Run("appends a computed numeric column", func(t *testing.T) {
+		t.Parallel()
+
+		input := "batch,total_debit,total_credit\n1,100,250\n2,300,50"
+		table, err := Parse(strings.NewReader(input), CSV)
+		require.NoError(t, err)
+
+		err = table.AddColumn("net", func(row map[string]string) string {
+			credit, _ := strconv.Atoi(row["total_credit"])
+			debit, _ := strconv.Atoi(row["total_debit"])
+			return strconv.Itoa(credit - debit)
+		}, TypeInteger)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"batch", "total_debit", "total_credit", "net"}, table.Headers)
+		assert.Equal(t, []string{"1", "100", "250", "150"}, table.Records[0])
+		assert.Equal(t, []string{"2", "300", "50", "-250"}, table.Records[1])
+		require.Len(t, table.ColumnTypes, 4)
+		assert.Equal(t, TypeInteger, table.ColumnTypes[3])
+		assert.Equal(t, int64(-250), ParseValue(table.Records[1][3], table.ColumnTypes[3]))
+	})
+
+	t.Run("pads short records before appending", func(t *testing.T) {
+		t.Parallel()
+
+		table := &TableData{
+			Headers:     []string{"a", "b"},
+			Records:     [][]string{{"1"}},
+			ColumnTypes: []ColumnType{TypeInteger, TypeText},
+		}
+
+		err := table.AddColumn("c", func(row map[string]string) string {
+			return row["a"] + row["b"] + "x"
+		}, TypeText)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"1", "", "1x"}, table.Records[0])
+	})
+
+	t.Run("returns error on name collision", func(t *testing.T) {
+		t.Parallel()
+
+		table := &TableData{
+			Headers:     []string{"a"},
+			Records:     [][]string{{"1"}},
+			ColumnTypes: []ColumnType{TypeInteger},
+		}
+
+		err := table.AddColumn("a", func(map[string]string) string { return "" }, TypeText)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "duplicate column name")
+		assert.Equal(t, []string{"a"}, table.Headers)
+		assert.Equal(t, []string{"1"}, table.Records[0])
+	})
+
+	t.Run("returns error for nil function", func(t *testing.T) {
+		t.Parallel()
+
+		table := &TableData{Headers: []string{"a"}}
+
+		require.Error(t, table.AddColumn("b", nil, TypeText))
+	})
+}
+
+func TestTableData_ToCSV(t *testing.T) {
+	t.Parallel()
+
+	table := &TableData{
+		Headers: []string{"name", "note"},
+		Records: [][]string{
+			{"Alice", "likes tea, coffee"},
+			{"Bob", "line one\nline two"},
+			{"Carol", `says "hi"`},
+		},
+	}
+
+	t.Run("quotes fields that need it", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := table.ToCSV()
+
+		require.NoError(t, err)
+		assert.Equal(t, "name,note\n"+
+			"Alice,\"likes tea, coffee\"\n"+
+			"Bob,\"line one\nline two\"\n"+
+			"Carol,\"says \"\"hi\"\"\"\n", got)
+	})
+
+	t.Run("writes TSV", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := (&TableData{Headers: []string{"a", "b"}, Records: [][]string{{"1", "x,y"}}}).ToTSV()
+
+		require.NoError(t, err)
+		assert.Equal(t, "a\tb\n1\tx,y\n", got)
+	})
+
+	t.Run("round trips through Parse", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := table.ToCSV()
+		require.NoError(t, err)
+
+		parsed, err := Parse(strings.NewReader(got), CSV)
+		require.NoError(t, err)
+		assert.Equal(t, table.Records, parsed.Records)
+	})
+}
+
+func TestTableData_Column(t *testing.T) {
+	t.Parallel()
+
+	table := &TableData{
+		Headers: []string{"id", "name"},
+		Records: [][]string{{"1", "Alice"}, {"2"}, {"3", "Carol"}},
+	}
+
+	t.Run("returns the values of the column", func(t *testing.T) {
+		t.Parallel()
+
+		values, err := table.Column("name")
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Alice", "", "Carol"}, values)
+	})
+
+	t.Run("returns an error for an unknown column", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := table.Column("missing")
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `"missing"`)
+	})
+
+	t.Run("reports the column index", func(t *testing.T) {
+		t.Parallel()
+
+		idx, ok := table.ColumnIndex("name")
+		assert.True(t, ok)
+		assert.Equal(t, 1, idx)
+
+		_, ok = table.ColumnIndex("missing")
+		assert.False(t, ok)
+	})
+}
+
+func TestTableData_SelectColumns(t *testing.T) {
+	t.Parallel()
+
+	newTable := func() *TableData {
+		return &TableData{
+			Headers:     []string{"id", "name", "score"},
+			Records:     [][]string{{"1", "Alice", "9.5"}, {"2", "Bob"}},
+			ColumnTypes: []ColumnType{TypeInteger, TypeText, TypeReal},
+		}
+	}
+
+	t.Run("projects columns in the requested order", func(t *testing.T) {
+		t.Parallel()
+
+		table := newTable()
+
+		subset, err := table.SelectColumns("score", "id")
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"score", "id"}, subset.Headers)
+		assert.Equal(t, [][]string{{"9.5", "1"}, {"", "2"}}, subset.Records)
+		assert.Equal(t, []ColumnType{TypeReal, TypeInteger}, subset.ColumnTypes)
+		assert.Equal(t, newTable(), table, "the original table must not be modified")
+	})
+
+	t.Run("returns an error for an unknown column", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := newTable().SelectColumns("id", "missing")
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `"missing"`)
+	})
+
+	t.Run("returns an error for a repeated column", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := newTable().SelectColumns("id", "id")
+
+		require.Error(t, err)
+	})
+}
+
+func TestTableData_AppendRows(t *testing.T) {
+	t.Parallel()
+
+	t.Run("appends records and widens column types", func(t *testing.T) {
+		t.Parallel()
+
+		monday, err := Parse(strings.NewReader("id,amount,note\n1,10,a\n2,20,b"), CSV)
+		require.NoError(t, err)
+		tuesday, err := Parse(strings.NewReader("id,amount,note\n3,1.5,2024-01-02"), CSV)
+		require.NoError(t, err)
+
+		err = monday.AppendRows(tuesday)
+
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{{"1", "10", "a"}, {"2", "20", "b"}, {"3", "1.5", "2024-01-02"}}, monday.Records)
+		assert.Equal(t, []ColumnType{TypeInteger, TypeReal, TypeText}, monday.ColumnTypes)
+
+		tuesday.Records[0][0] = "changed"
+		assert.Equal(t, "3", monday.Records[2][0], "appended records must not alias the other table")
+	})
+
+	t.Run("returns an error when header names differ", func(t *testing.T) {
+		t.Parallel()
+
+		table := &TableData{Headers: []string{"id", "name"}, Records: [][]string{{"1", "a"}}}
+		other := &TableData{Headers: []string{"id", "title"}, Records: [][]string{{"2", "b"}}}
+
+		err := table.AppendRows(other)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `column 2 is "name", but "title"`)
+		assert.Len(t, table.Records, 1)
+	})
+
+	t.Run("returns an error when the column count differs", func(t *testing.T) {
+		t.Parallel()
+
+		table := &TableData{Headers: []string{"id"}}
+		other := &TableData{Headers: []string{"id", "name"}}
+
+		err := table.AppendRows(other)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "1 columns, but the appended table has 2")
+	})
+}
+
+func TestTableData_Validate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("accepts a consistent table", func(t *testing.T) {
+		t.Parallel()
+
+		table, err := Parse(strings.NewReader("id,name\n1,Alice\n2,Bob"), CSV)
+		require.NoError(t, err)
+
+		assert.NoError(t, table.Validate())
+	})
+
+	t.Run("reports structural problems", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name  string
+			table *TableData
+			want  string
+		}{
+			{
+				name: "duplicate headers",
+				table: &TableData{
+					Headers:     []string{"id", "id"},
+					ColumnTypes: []ColumnType{TypeInteger, TypeInteger},
+				},
+				want: "duplicate column name: id",
+			},
+			{
+				name: "missing column types",
+				table: &TableData{
+					Headers:     []string{"id", "name"},
+					ColumnTypes: []ColumnType{TypeInteger},
+				},
+				want: "1 column types for 2 headers",
+			},
+			{
+				name: "short record",
+				table: &TableData{
+					Headers:     []string{"id", "name"},
+					Records:     [][]string{{"1", "Alice"}, {"2"}},
+					ColumnTypes: []ColumnType{TypeInteger, TypeText},
+				},
+				want: "record 2 has 1 fields, want 2",
+			},
+			{
+				name: "duplicate primary key",
+				table: &TableData{
+					Headers:     []string{"id", "name"},
+					Records:     [][]string{{"1", "Alice"}, {"2", "Bob"}, {"1", "Carol"}},
+					ColumnTypes: []ColumnType{TypeInteger, TypeText},
+					PrimaryKey:  []string{"id"},
+				},
+				want: "record 3 has the same primary key as record 1: 1",
+			},
+			{
+				name: "unknown primary key column",
+				table: &TableData{
+					Headers:     []string{"id"},
+					ColumnTypes: []ColumnType{TypeInteger},
+					PrimaryKey:  []string{"code"},
+				},
+				want: `invalid primary key: column "code" not found`,
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				err := tt.table.Validate()
+
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.want)
+			})
+		}
+	})
+}
+
+func TestTableData_Distinct(t *testing.T) {
+	t.Parallel()
+
+	t.Run("removes exact duplicates and keeps first-seen order", func(t *testing.T) {
+		t.Parallel()
+
+		table, err := Parse(strings.NewReader("id,name\n2,Bob\n1,Alice\n2,Bob\n3,Carol\n1,Alice"), CSV)
+		require.NoError(t, err)
+
+		unique := table.Distinct()
+
+		assert.Equal(t, []string{"id", "name"}, unique.Headers)
+		assert.Equal(t, [][]string{{"2", "Bob"}, {"1", "Alice"}, {"3", "Carol"}}, unique.Records)
+		assert.Equal(t, []ColumnType{TypeInteger, TypeText}, unique.ColumnTypes)
+		assert.Len(t, table.Records, 5, "original table must not be modified")
+	})
+
+	t.Run("does not confuse values that concatenate alike", func(t *testing.T) {
+		t.Parallel()
+
+		table := &TableData{
+			Headers:     []string{"a", "b"},
+			Records:     [][]string{{"x,", "y"}, {"x", ",y"}},
+			ColumnTypes: []ColumnType{TypeText, TypeText},
+		}
+
+		assert.Len(t, table.Distinct().Records, 2)
+	})
+
+	t.Run("deduplicates on a subset of columns", func(t *testing.T) {
+		t.Parallel()
+
+		table := &TableData{
+			Headers:     []string{"customer", "order"},
+			Records:     [][]string{{"a", "1"}, {"b", "2"}, {"a", "3"}},
+			ColumnTypes: []ColumnType{TypeText, TypeInteger},
+		}
+
+		unique, err := table.DistinctBy("customer")
+
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{{"a", "1"}, {"b", "2"}}, unique.Records)
+		assert.Equal(t, []ColumnType{TypeText, TypeInteger}, unique.ColumnTypes)
+	})
+
+	t.Run("deduplicates on the primary key by default", func(t *testing.T) {
+		t.Parallel()
+
+		table := &TableData{
+			Headers:     []string{"region", "id", "total"},
+			Records:     [][]string{{"eu", "1", "10"}, {"us", "1", "20"}, {"eu", "1", "30"}},
+			ColumnTypes: []ColumnType{TypeText, TypeInteger, TypeInteger},
+			PrimaryKey:  []string{"region", "id"},
+		}
+
+		unique, err := table.DistinctBy()
+
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{{"eu", "1", "10"}, {"us", "1", "20"}}, unique.Records)
+		assert.Equal(t, []string{"region", "id"}, unique.PrimaryKey)
+		require.NoError(t, unique.Validate())
+	})
+
+	t.Run("returns an error for an unknown column", func(t *testing.T) {
+		t.Parallel()
+
+		table := &TableData{Headers: []string{"a"}}
+
+		_, err := table.DistinctBy("missing")
+
+		require.Error(t, err)
+	})
+}
+
+func TestTableData_Transpose(t *testing.T) {
+	t.Parallel()
+
+	t.Run("turns keys into headers and columns into rows", func(t *testing.T) {
+		t.Parallel()
+
+		table, err := Parse(strings.NewReader("key,value,default\nhost,example.com,localhost\nport,8080,80\n"), CSV)
+		require.NoError(t, err)
+
+		transposed := table.Transpose()
+
+		assert.Equal(t, []string{"key", "host", "port"}, transposed.Headers)
+		assert.Equal(t, [][]string{
+			{"value", "example.com", "8080"},
+			{"default", "localhost", "80"},
+		}, transposed.Records)
+		assert.Equal(t, []ColumnType{TypeText, TypeText, TypeInteger}, transposed.ColumnTypes)
+		require.NoError(t, transposed.Validate())
+		assert.Equal(t, []string{"key", "value", "default"}, table.Headers, "original table must not be modified")
+	})
+
+	t.Run("makes duplicate keys unique", func(t *testing.T) {
+		t.Parallel()
+
+		table := &TableData{
+			Headers:     []string{"key", "value"},
+			Records:     [][]string{{"port", "80"}, {"port", "443"}, {"port_2", "8080"}, {"key", "x"}},
+			ColumnTypes: []ColumnType{TypeText, TypeInteger},
+		}
+
+		transposed := table.Transpose()
+
+		assert.Equal(t, []string{"key", "port", "port_2", "port_2_2", "key_2"}, transposed.Headers)
+		require.NoError(t, transposed.Validate())
+	})
+
+	t.Run("transposes a single wide row", func(t *testing.T) {
+		t.Parallel()
+
+		table := &TableData{
+			Headers:     []string{"id", "name", "amount"},
+			Records:     [][]string{{"1", "Alice", "100"}},
+			ColumnTypes: []ColumnType{TypeInteger, TypeText, TypeInteger},
+		}
+
+		transposed := table.Transpose()
+
+		assert.Equal(t, []string{"id", "1"}, transposed.Headers)
+		assert.Equal(t, [][]string{{"name", "Alice"}, {"amount", "100"}}, transposed.Records)
+	})
+
+	t.Run("returns an empty table for a table without columns", func(t *testing.T) {
+		t.Parallel()
+
+		transposed := (&TableData{}).Transpose()
+
+		assert.Empty(t, transposed.Headers)
+		assert.Empty(t, transposed.Records)
+	})
+}
+
+func TestTableData_HeadTail(t *testing.T) {
+	t.Parallel()
+
+	table := &TableData{
+		Headers:     []string{"id"},
+		Records:     [][]string{{"1"}, {"2"}, {"3"}},
+		ColumnTypes: []ColumnType{TypeInteger},
+	}
+
+	t.Run("returns the first and last records", func(t *testing.T) {
+		t.Parallel()
+
+		head := table.Head(2)
+		tail := table.Tail(2)
+
+		assert.Equal(t, [][]string{{"1"}, {"2"}}, head.Records)
+		assert.Equal(t, [][]string{{"2"}, {"3"}}, tail.Records)
+		assert.Equal(t, table.Headers, head.Headers)
+		assert.Equal(t, table.ColumnTypes, tail.ColumnTypes)
+	})
+
+	t.Run("returns every record when n exceeds the table", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Len(t, table.Head(10).Records, 3)
+		assert.Len(t, table.Tail(10).Records, 3)
+	})
+
+	t.Run("returns no records for zero or negative n", func(t *testing.T) {
+		t.Parallel()
+
+		for _, n := range []int{0, -1} {
+			head := table.Head(n)
+			tail := table.Tail(n)
+
+			assert.NotNil(t, head.Records)
+			assert.Empty(t, head.Records)
+			assert.Empty(t, tail.Records)
+			assert.Equal(t, table.Headers, tail.Headers)
+		}
+	})
+
+	t.Run("does not share the records slice with the original", func(t *testing.T) {
+		t.Parallel()
+
+		head := table.Head(1)
+		head.Records = append(head.Records, []string{"4"})
+
+		assert.Equal(t, []string{"2"}, table.Records[1])
+	})
+}
+
+func TestTableData_MapColumn(t *testing.T) {
+	t.Parallel()
+
+	t.Run("transforms values in place and infers the type again", func(t *testing.T) {
+		t.Parallel()
+
+		table := &TableData{
+			Headers:     []string{"name", "amount"},
+			Records:     [][]string{{"alice", "$10"}, {"bob", "$25"}},
+			ColumnTypes: []ColumnType{TypeText, TypeText},
+		}
+
+		require.NoError(t, table.MapColumn("name", strings.ToUpper))
+		require.NoError(t, table.MapColumn("amount", func(v string) string {
+			return strings.TrimPrefix(v, "$")
+		}))
+
+		assert.Equal(t, [][]string{{"ALICE", "10"}, {"BOB", "25"}}, table.Records)
+		assert.Equal(t, []ColumnType{TypeText, TypeInteger}, table.ColumnTypes)
+	})
+
+	t.Run("pads short records", func(t *testing.T) {
+		t.Parallel()
+
+		table := &TableData{
+			Headers:     []string{"id", "note"},
+			Records:     [][]string{{"1"}, {"2", "x"}},
+			ColumnTypes: []ColumnType{TypeInteger, TypeText},
+		}
+
+		require.NoError(t, table.MapColumn("note", func(v string) string {
+			if v == "" {
+				return "-"
+			}
+			return v
+		}))
+
+		assert.Equal(t, [][]string{{"1", "-"}, {"2", "x"}}, table.Records)
+	})
+
+	t.Run("returns an error for an unknown column", func(t *testing.T) {
+		t.Parallel()
+
+		table := &TableData{Headers: []string{"id"}}
+
+		err := table.MapColumn("missing", strings.ToUpper)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `column "missing" not found`)
+	})
+
+	t.Run("returns an error for a nil function", func(t *testing.T) {
+		t.Parallel()
+
+		table := &TableData{Headers: []string{"id"}}
+
+		require.Error(t, table.MapColumn("id", nil))
+	})
+}
+
+func TestTableData_PrimaryKey(t *testing.T) {
+	t.Parallel()
+
+	table := &TableData{
+		Headers:     []string{"id", "name"},
+		Records:     [][]string{{"1", "Alice"}, {"2", "Bob"}},
+		ColumnTypes: []ColumnType{TypeInteger, TypeText},
+		PrimaryKey:  []string{"id"},
+	}
+
+	t.Run("is kept by operations that keep the key columns", func(t *testing.T) {
+		t.Parallel()
+
+		selected, err := table.SelectColumns("name", "id")
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"id"}, selected.PrimaryKey)
+		assert.Equal(t, []string{"id"}, table.Head(1).PrimaryKey)
+		assert.Equal(t, []string{"id"}, table.Distinct().PrimaryKey)
+	})
+
+	t.Run("is dropped when a key column is not selected", func(t *testing.T) {
+		t.Parallel()
+
+		selected, err := table.SelectColumns("name")
+
+		require.NoError(t, err)
+		assert.Nil(t, selected.PrimaryKey)
+	})
+}
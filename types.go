@@ -1,9 +1,16 @@
 package fileparser
 
 import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
 )
 
 // Type inference constants
@@ -14,70 +21,345 @@ const (
 	maxDatetimeLength      = 35
 )
 
+// InferenceConfig tunes column type inference.
+// The zero value infers every supported type.
+type InferenceConfig struct {
+	// AllowedTypes restricts inference to the listed column types. A column
+	// whose data does not fit any allowed type gets the first allowed type,
+	// so list TypeText first to fall back to text. For example, with
+	// {TypeText, TypeInteger} a column of floats becomes TypeText rather than
+	// TypeReal. Empty allows all types.
+	AllowedTypes []ColumnType
+
+	// SampleSize is the number of leading records examined per column.
+	// Zero uses the default of 1000 records, and a negative value examines
+	// every record. A smaller sample is faster but may miss values that do
+	// not fit the inferred type.
+	SampleSize int
+
+	// Confidence is the share of a column's non-empty values that must fit
+	// a type for the column to get that type, e.g. 0.95 for stricter typing.
+	// It must be greater than 0 and at most 1. Zero uses the default of 0.8.
+	Confidence float64
+
+	// MinDatetimeLen and MaxDatetimeLen bound the length in bytes of values
+	// considered as datetimes. Zero uses the defaults of 4 and 35. A value
+	// of another length is never TypeDatetime.
+	MinDatetimeLen int
+	MaxDatetimeLen int
+
+	// ParseLocalizedNumbers makes inference recognize numbers written with
+	// group separators and currency symbols, such as "1,234.56", "$1,234",
+	// or "-€ 12.50", as TypeInteger or TypeReal. Use InferenceConfig.ParseValue
+	// to convert such values. Group separators must separate groups of three
+	// digits in the integer part, so "1.234,56" is not misread with the
+	// default separators.
+	ParseLocalizedNumbers bool
+
+	// DecimalSep is the decimal separator of localized numbers. Zero means '.'.
+	DecimalSep rune
+
+	// GroupSep is the digit group separator of localized numbers. Zero means
+	// ','. Set DecimalSep to ',' and GroupSep to '.' for "1.234,56".
+	GroupSep rune
+
+	// ParsePercentages makes inference recognize numbers with a trailing
+	// '%', such as "45%" or "12.5%", as TypeReal. Use InferenceConfig.ParseValue
+	// to convert such values.
+	ParsePercentages bool
+
+	// PercentAsFraction makes InferenceConfig.ParseValue divide percentages by
+	// 100, returning 0.45 for "45%" instead of 45.
+	PercentAsFraction bool
+
+	// InferBooleans makes inference recognize "true" and "false", in any
+	// case, as TypeBoolean. Without it such columns are TypeText. XLSX and
+	// Parquet files always infer booleans, since their boolean cells and
+	// columns are read as "true" and "false".
+	InferBooleans bool
+}
+
+// confidence returns the share of values that must fit a type.
+func (c InferenceConfig) confidence() float64 {
+	if c.Confidence == 0 {
+		return minConfidenceThreshold
+	}
+	return c.Confidence
+}
+
+// datetimeLengths returns the bounds of the length of datetime values.
+func (c InferenceConfig) datetimeLengths() (int, int) {
+	minLen, maxLen := c.MinDatetimeLen, c.MaxDatetimeLen
+	if minLen == 0 {
+		minLen = minDatetimeLength
+	}
+	if maxLen == 0 {
+		maxLen = maxDatetimeLength
+	}
+	return minLen, maxLen
+}
+
+// validate reports whether the settings of c are consistent.
+func (c InferenceConfig) validate() error {
+	if c.Confidence < 0 || c.Confidence > 1 {
+		return fmt.Errorf("invalid inference confidence %v: must be greater than 0 and at most 1", c.Confidence)
+	}
+	if c.MinDatetimeLen < 0 || c.MaxDatetimeLen < 0 {
+		return errors.New("datetime length bounds cannot be negative")
+	}
+	if minLen, maxLen := c.datetimeLengths(); minLen > maxLen {
+		return fmt.Errorf("minimum datetime length %d exceeds maximum %d", minLen, maxLen)
+	}
+	if !c.ParseLocalizedNumbers {
+		return nil
+	}
+	decimalSep, groupSep := c.separators()
+	if decimalSep == groupSep {
+		return fmt.Errorf("decimal and group separators must differ, both are %q", decimalSep)
+	}
+	for _, r := range []rune{decimalSep, groupSep} {
+		if unicode.IsDigit(r) || r == '-' || r == '+' {
+			return fmt.Errorf("invalid number separator %q", r)
+		}
+	}
+	return nil
+}
+
+// sampleSize returns the number of the n records that inference examines.
+func (c InferenceConfig) sampleSize(n int) int {
+	switch {
+	case c.SampleSize < 0:
+		return n
+	case c.SampleSize == 0:
+		return min(n, maxSampleSize)
+	default:
+		return min(n, c.SampleSize)
+	}
+}
+
+// allows reports whether inference may produce colType.
+func (c InferenceConfig) allows(colType ColumnType) bool {
+	return len(c.AllowedTypes) == 0 || slices.Contains(c.AllowedTypes, colType)
+}
+
+// fallback returns the type used when no allowed type fits a column.
+func (c InferenceConfig) fallback() ColumnType {
+	if len(c.AllowedTypes) == 0 {
+		return TypeText
+	}
+	return c.AllowedTypes[0]
+}
+
 // inferColumnTypes infers the type of each column based on the data.
 func inferColumnTypes(headers []string, records [][]string) []ColumnType {
-	columnTypes := make([]ColumnType, len(headers))
+	return InferenceConfig{}.inferColumnTypes(headers, records)
+}
 
-	for i := range headers {
-		columnTypes[i] = inferColumnType(records, i)
+// InferFromSample infers a column type for each column of records, a sample
+// of data rows without the header row, using the default InferenceConfig.
+// It is meant for streaming: buffer the first rows passed to a ParseStream
+// callback and infer the types from them instead of loading the whole file.
+// The number of columns is that of the widest record.
+//
+// Example:
+//
+//	var sample [][]string
+//	err := fileparser.ParseStream(f, fileparser.CSV, func(record, headers []string) error {
+//	    if len(sample) < 1000 {
+//	        sample = append(sample, slices.Clone(record))
+//	    }
+//	    return nil
+//	})
+//	types := fileparser.InferFromSample(sample)
+func InferFromSample(records [][]string) []ColumnType {
+	return InferenceConfig{}.InferFromSample(records)
+}
+
+// InferFromSample infers a column type for each column of records like the
+// package-level InferFromSample, considering only the types allowed by c and
+// at most c.SampleSize records.
+func (c InferenceConfig) InferFromSample(records [][]string) []ColumnType {
+	width := 0
+	for _, record := range records[:c.sampleSize(len(records))] {
+		width = max(width, len(record))
 	}
 
+	columnTypes := make([]ColumnType, width)
+	for i := range columnTypes {
+		columnTypes[i] = c.inferColumnType(records, i)
+	}
 	return columnTypes
 }
 
 // inferColumnType infers the type of a single column.
 func inferColumnType(records [][]string, colIndex int) ColumnType {
-	if len(records) == 0 {
-		return TypeText
-	}
+	return InferenceConfig{}.inferColumnType(records, colIndex)
+}
 
-	// Collect non-empty values for this column
-	var values []string
-	sampleSize := min(len(records), maxSampleSize)
-	for i := range sampleSize {
-		if colIndex < len(records[i]) {
-			val := strings.TrimSpace(records[i][colIndex])
-			if val != "" {
-				values = append(values, val)
-			}
-		}
-	}
+// inferColumnTypes infers the type of each column based on the data,
+// considering only the types allowed by c.
+func (c InferenceConfig) inferColumnTypes(headers []string, records [][]string) []ColumnType {
+	columnTypes, _ := c.InferColumnTypesWithStats(headers, records)
+	return columnTypes
+}
 
-	if len(values) == 0 {
-		return TypeText
+// inferColumnType infers the type of a single column, considering only the
+// types allowed by c.
+func (c InferenceConfig) inferColumnType(records [][]string, colIndex int) ColumnType {
+	colType, _ := c.inferColumnTypeWithStats(records, colIndex)
+	return colType
+}
+
+// ColumnInferenceStats describes the values type inference examined in one
+// column, to explain why the column got its type. Only the sampled records
+// are counted (see InferenceConfig.SampleSize).
+type ColumnInferenceStats struct {
+	// Total is the number of records examined.
+	Total int
+	// NonEmpty is the number of examined values that are not blank.
+	// Blank values are ignored when the type is decided.
+	NonEmpty int
+	// IntegerCount, RealCount, DatetimeCount, and BooleanCount are the
+	// number of non-empty values classified as each type. Integers also
+	// count toward a real column.
+	IntegerCount  int
+	RealCount     int
+	DatetimeCount int
+	BooleanCount  int
+	// TextCount is the number of non-empty values that fit no other type.
+	TextCount int
+}
+
+// InferColumnTypesWithStats infers the type of each column like Parse does
+// and also returns, per column, the counts of the values it examined. A
+// column needs at least 80% of its non-empty values to fit a type, so the
+// stats show, for example, that a TEXT column has 30% unparseable values
+// and may deserve a ParseOptions.ColumnTypeOverrides entry.
+//
+// Example:
+//
+//	types, stats := fileparser.InferColumnTypesWithStats(table.Headers, table.Records)
+//	for i, s := range stats {
+//	    fmt.Printf("%s: %s (%d of %d empty)\n", table.Headers[i], types[i], s.Total-s.NonEmpty, s.Total)
+//	}
+func InferColumnTypesWithStats(headers []string, records [][]string) ([]ColumnType, []ColumnInferenceStats) {
+	return InferenceConfig{}.InferColumnTypesWithStats(headers, records)
+}
+
+// InferColumnTypesWithStats is like the package-level
+// InferColumnTypesWithStats, considering only the types allowed by c.
+func (c InferenceConfig) InferColumnTypesWithStats(headers []string, records [][]string) ([]ColumnType, []ColumnInferenceStats) {
+	columnTypes := make([]ColumnType, len(headers))
+	stats := make([]ColumnInferenceStats, len(headers))
+
+	for i := range headers {
+		columnTypes[i], stats[i] = c.inferColumnTypeWithStats(records, i)
 	}
 
-	// Count types
-	var intCount, floatCount, datetimeCount int
-	for _, val := range values {
-		switch classifyValue(val) {
+	return columnTypes, stats
+}
+
+// inferColumnTypeWithStats infers the type of a single column, considering
+// only the types allowed by c, and counts the values it examined.
+func (c InferenceConfig) inferColumnTypeWithStats(records [][]string, colIndex int) (ColumnType, ColumnInferenceStats) {
+	stats := ColumnInferenceStats{Total: c.sampleSize(len(records))}
+
+	// Count types of the non-empty values in this column
+	for i := range stats.Total {
+		if colIndex >= len(records[i]) {
+			continue
+		}
+		val := strings.TrimSpace(records[i][colIndex])
+		if val == "" {
+			continue
+		}
+		stats.NonEmpty++
+		switch c.classifyValue(val) {
 		case TypeInteger:
-			intCount++
+			stats.IntegerCount++
 		case TypeReal:
-			floatCount++
+			stats.RealCount++
 		case TypeDatetime:
-			datetimeCount++
+			stats.DatetimeCount++
+		case TypeBoolean:
+			stats.BooleanCount++
+		default:
+			stats.TextCount++
 		}
 	}
 
-	total := len(values)
+	if stats.NonEmpty == 0 {
+		return c.fallback(), stats
+	}
+
+	total := float64(stats.NonEmpty)
+	confidence := c.confidence()
 
 	// Determine type based on majority
-	if float64(intCount)/float64(total) >= minConfidenceThreshold {
-		return TypeInteger
+	if c.allows(TypeInteger) && float64(stats.IntegerCount)/total >= confidence {
+		return TypeInteger, stats
 	}
-	if float64(intCount+floatCount)/float64(total) >= minConfidenceThreshold {
-		return TypeReal
+	if c.allows(TypeReal) && float64(stats.IntegerCount+stats.RealCount)/total >= confidence {
+		return TypeReal, stats
 	}
-	if float64(datetimeCount)/float64(total) >= minConfidenceThreshold {
-		return TypeDatetime
+	if c.allows(TypeDatetime) && float64(stats.DatetimeCount)/total >= confidence {
+		return TypeDatetime, stats
+	}
+	if c.allows(TypeBoolean) && float64(stats.BooleanCount)/total >= confidence {
+		return TypeBoolean, stats
 	}
 
-	return TypeText
+	return c.fallback(), stats
+}
+
+// classifyValue determines the type of a single value, recognizing
+// percentages when c.ParsePercentages is set.
+func (c InferenceConfig) classifyValue(value string) ColumnType {
+	if c.ParsePercentages {
+		if number, ok := cutPercent(value); ok {
+			switch c.classifyLocalized(number) {
+			case TypeInteger, TypeReal:
+				return TypeReal
+			default:
+				return TypeText
+			}
+		}
+	}
+	return c.classifyLocalized(value)
+}
+
+// cutPercent removes a trailing '%' and surrounding white space from value
+// and reports whether it was present.
+func cutPercent(value string) (string, bool) {
+	number, ok := strings.CutSuffix(strings.TrimSpace(value), "%")
+	return strings.TrimSpace(number), ok
+}
+
+// classifyLocalized determines the type of a single value, recognizing
+// localized numbers when c.ParseLocalizedNumbers is set.
+func (c InferenceConfig) classifyLocalized(value string) ColumnType {
+	if !c.ParseLocalizedNumbers {
+		return c.classifyPlain(value)
+	}
+	if normalized, ok := c.normalizeNumber(value); ok {
+		return c.classifyPlain(normalized)
+	}
+	colType := c.classifyPlain(value)
+	if decimalSep, _ := c.separators(); decimalSep != '.' && (colType == TypeInteger || colType == TypeReal) {
+		// e.g. "1.5" when '.' groups digits
+		return TypeText
+	}
+	return colType
 }
 
 // classifyValue determines the type of a single value.
 func classifyValue(value string) ColumnType {
+	return InferenceConfig{}.classifyPlain(value)
+}
+
+// classifyPlain determines the type of a single value, recognizing
+// datetimes within the length bounds of c.
+func (c InferenceConfig) classifyPlain(value string) ColumnType {
 	if value == "" {
 		return TypeText
 	}
@@ -93,22 +375,64 @@ func classifyValue(value string) ColumnType {
 	}
 
 	// Check datetime
-	if isDatetime(value) {
+	minLen, maxLen := c.datetimeLengths()
+	if _, ok := parseDatetimeWithin(value, minLen, maxLen); ok {
 		return TypeDatetime
 	}
 
+	// Check boolean
+	if c.InferBooleans && isBoolean(value) {
+		return TypeBoolean
+	}
+
 	return TypeText
 }
 
-// isInteger checks if the string represents an integer.
+// isInteger checks if the string represents an integer, see parseInteger.
 func isInteger(s string) bool {
+	_, ok := parseInteger(s)
+	return ok
+}
+
+// scientificNotation matches decimal numbers with an exponent, e.g. "2E6",
+// capturing the integer and fractional digits and the exponent.
+var scientificNotation = regexp.MustCompile(`^[+-]?([0-9]+)(?:\.([0-9]+))?[eE]([+-]?[0-9]+)$`)
+
+// maxInt64Digits is the number of digits of the largest int64.
+const maxInt64Digits = 19
+
+// parseInteger parses s as a base 10 integer within the int64 range.
+// Scientific notation is accepted when the value is a whole number, so
+// "1e10", "2E6", and "1.5e3" (1500) are integers while "1.5e-1" and "1.5"
+// are not.
+func parseInteger(s string) (int64, bool) {
 	s = strings.TrimSpace(s)
 	if s == "" {
-		return false
+		return 0, false
 	}
 
-	_, err := strconv.ParseInt(s, 10, 64)
-	return err == nil
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return n, true
+	}
+	m := scientificNotation.FindStringSubmatch(s)
+	if m == nil {
+		return 0, false
+	}
+	// Reject values that cannot be an int64 before building an exact
+	// big.Rat, whose cost grows with the exponent, e.g. "1e1000000".
+	if f, err := strconv.ParseFloat(s, 64); err != nil || math.Abs(f) >= math.MaxInt64 {
+		return 0, false
+	}
+	exp, err := strconv.Atoi(m[3])
+	if err != nil || max(exp, -exp) > maxInt64Digits+len(m[1])+len(m[2]) {
+		return 0, false
+	}
+
+	r, ok := new(big.Rat).SetString(s)
+	if !ok || !r.IsInt() || !r.Num().IsInt64() {
+		return 0, false
+	}
+	return r.Num().Int64(), true
 }
 
 // isFloat checks if the string represents a floating-point number.
@@ -127,51 +451,96 @@ func isFloat(s string) bool {
 	return err == nil
 }
 
+// isBoolean checks if the string is "true" or "false", ignoring case.
+func isBoolean(s string) bool {
+	_, ok := parseBoolean(s)
+	return ok
+}
+
+// parseBoolean parses "true" or "false", ignoring case. It accepts exactly
+// the values isBoolean does, so ParseValue converts every value inferred as
+// TypeBoolean and nothing else.
+func parseBoolean(s string) (bool, bool) {
+	s = strings.TrimSpace(s)
+	switch {
+	case strings.EqualFold(s, "true"):
+		return true, true
+	case strings.EqualFold(s, "false"):
+		return false, true
+	default:
+		return false, false
+	}
+}
+
 // isDatetime checks if the string represents a datetime value.
 func isDatetime(s string) bool {
+	_, ok := parseDatetime(s)
+	return ok
+}
+
+// datetimeFormats lists the layouts recognized as TypeDatetime, in the order
+// they are tried.
+var datetimeFormats = []string{
+	time.RFC3339,
+	time.RFC3339Nano,
+	"2006-01-02",
+	"2006-01-02 15:04:05",
+	"2006/01/02",
+	"2006/01/02 15:04:05",
+	"01/02/2006",
+	"01-02-2006",
+	"02/01/2006",
+	"02-01-2006",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04:05Z",
+	"2006-01-02T15:04:05-07:00",
+	"Jan 2, 2006",
+	"January 2, 2006",
+	"02 Jan 2006",
+	// Common Log Format, used by Apache and by nginx for $time_local, with
+	// and without the brackets that access logs put around it
+	"[02/Jan/2006:15:04:05 -0700]",
+	"02/Jan/2006:15:04:05 -0700",
+}
+
+// parseDatetime parses s with the first matching layout in datetimeFormats.
+func parseDatetime(s string) (time.Time, bool) {
+	return parseDatetimeWithin(s, minDatetimeLength, maxDatetimeLength)
+}
+
+// parseDatetimeWithin is like parseDatetime, but only considers values whose
+// length is between minLen and maxLen.
+func parseDatetimeWithin(s string, minLen, maxLen int) (time.Time, bool) {
 	s = strings.TrimSpace(s)
-	if len(s) < minDatetimeLength || len(s) > maxDatetimeLength {
-		return false
+	if len(s) < minLen || len(s) > maxLen {
+		return time.Time{}, false
 	}
 
-	// Common datetime formats
-	formats := []string{
-		time.RFC3339,
-		time.RFC3339Nano,
-		"2006-01-02",
-		"2006-01-02 15:04:05",
-		"2006/01/02",
-		"2006/01/02 15:04:05",
-		"01/02/2006",
-		"01-02-2006",
-		"02/01/2006",
-		"02-01-2006",
-		"2006-01-02T15:04:05",
-		"2006-01-02T15:04:05Z",
-		"2006-01-02T15:04:05-07:00",
-		"Jan 2, 2006",
-		"January 2, 2006",
-		"02 Jan 2006",
-	}
-
-	for _, format := range formats {
-		if _, err := time.Parse(format, s); err == nil {
-			return true
+	for _, format := range datetimeFormats {
+		if t, err := time.Parse(format, s); err == nil {
+			return t, true
 		}
 	}
 
-	return false
+	return time.Time{}, false
 }
 
 // ParseValue converts a string value to the appropriate Go type based on ColumnType.
 // This function is useful for converting string records from TableData to typed values.
 //
 // Conversion rules:
-//   - TypeInteger: returns int64, or original string if parsing fails
+//   - TypeInteger: returns int64, or original string if parsing fails.
+//     Whole numbers in scientific notation such as "1e10" or "1.5e3" are
+//     converted too
 //   - TypeReal: returns float64, or original string if parsing fails
 //   - TypeDatetime: returns string (caller can parse with time.Parse if needed)
-//   - TypeText: returns string as-is
+//   - TypeBoolean: returns bool for "true" or "false" in any case, or the
+//     original string otherwise
+//   - TypeText: returns the string unchanged, so "007" stays "007"
 //   - Empty values return nil
+//
+// Localized numbers such as "$1,234.56" are returned as strings; use
+// InferenceConfig.ParseValue to convert them.
 func ParseValue(value string, colType ColumnType) any {
 	value = strings.TrimSpace(value)
 	if value == "" {
@@ -180,7 +549,7 @@ func ParseValue(value string, colType ColumnType) any {
 
 	switch colType {
 	case TypeInteger:
-		if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if i, ok := parseInteger(value); ok {
 			return i
 		}
 		return value
@@ -192,7 +561,45 @@ func ParseValue(value string, colType ColumnType) any {
 	case TypeDatetime:
 		// Return as string for now; caller can parse if needed
 		return value
+	case TypeBoolean:
+		if b, ok := parseBoolean(value); ok {
+			return b
+		}
+		return value
 	default:
 		return value
 	}
 }
+
+// ParseValue converts value like the package-level ParseValue. When
+// c.ParseLocalizedNumbers is set, integer and real values written with group
+// separators and currency symbols are converted too, e.g. "$1,234.56" to
+// 1234.56, and "1.234,56" to 1234.56 with DecimalSep ',' and GroupSep '.'.
+// When c.ParsePercentages is set, real percentages are converted to 45 for
+// "45%", or to 0.45 with c.PercentAsFraction.
+func (c InferenceConfig) ParseValue(value string, colType ColumnType) any {
+	if c.ParsePercentages && colType == TypeReal {
+		if number, ok := cutPercent(value); ok {
+			if f, ok := c.parseLocalized(number, TypeReal).(float64); ok {
+				if c.PercentAsFraction {
+					return f / 100
+				}
+				return f
+			}
+		}
+	}
+	return c.parseLocalized(value, colType)
+}
+
+// parseLocalized converts value like the package-level ParseValue, reading
+// localized numbers when c.ParseLocalizedNumbers is set.
+func (c InferenceConfig) parseLocalized(value string, colType ColumnType) any {
+	if c.ParseLocalizedNumbers && (colType == TypeInteger || colType == TypeReal) {
+		if normalized, ok := c.normalizeNumber(value); ok {
+			if v := ParseValue(normalized, colType); v != normalized {
+				return v
+			}
+		}
+	}
+	return ParseValue(value, colType)
+}
@@ -2,6 +2,7 @@ package fileparser
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -21,7 +22,17 @@ func Test_isInteger(t *testing.T) {
 		{"abc", false},
 		{"", false},
 		{"  42  ", true},
-		{"1e10", false},
+		{"1e10", true},
+		{"2E6", true},
+		{"-3e2", true},
+		{"1.5e3", true},   // 1500 is a whole number
+		{"1.5e-1", false}, // 0.15
+		{"1e19", false},   // beyond int64
+		{"1e1000000", false},
+		{"1e-1000000", false},
+		{"1000e-3", true},
+		{"0x1p3", false},
+		{"1/2", false},
 	}
 
 	for _, tc := range testCases {
@@ -77,6 +88,9 @@ func Test_isDatetime(t *testing.T) {
 		{"2024-01-15T10:30:00Z", true},
 		{"Jan 2, 2024", true},
 		{"January 2, 2024", true},
+		{"[10/Oct/2000:13:55:36 -0700]", true},
+		{"15/Jan/2024:10:00:00 +0900", true},
+		{"[15/Jan/2024:10:00:00]", false},
 		{"abc", false},
 		{"42", false},
 		{"", false},
@@ -129,6 +143,28 @@ func TestInferColumnTypes(t *testing.T) {
 		assert.Equal(t, TypeText, types[0])
 	})
 
+	t.Run("infers text for booleans by default", func(t *testing.T) {
+		t.Parallel()
+
+		headers := []string{"active"}
+		records := [][]string{{"true"}, {"FALSE"}, {"True"}, {"false"}}
+
+		types := inferColumnTypes(headers, records)
+
+		assert.Equal(t, TypeText, types[0])
+	})
+
+	t.Run("infers boolean type when enabled", func(t *testing.T) {
+		t.Parallel()
+
+		headers := []string{"active"}
+		records := [][]string{{"true"}, {"FALSE"}, {"True"}, {"false"}}
+
+		types := InferenceConfig{InferBooleans: true}.inferColumnTypes(headers, records)
+
+		assert.Equal(t, TypeBoolean, types[0])
+	})
+
 	t.Run("returns text for empty records", func(t *testing.T) {
 		t.Parallel()
 
@@ -144,6 +180,38 @@ func TestInferColumnTypes(t *testing.T) {
 func TestParseValue(t *testing.T) {
 	t.Parallel()
 
+	t.Run("converts scientific notation integers", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, int64(10000000000), ParseValue("1e10", TypeInteger))
+		assert.Equal(t, int64(1500), ParseValue("1.5e3", TypeInteger))
+	})
+
+	t.Run("infers whole scientific notation values as integers", func(t *testing.T) {
+		t.Parallel()
+
+		types := inferColumnTypes([]string{"count"}, [][]string{{"1e10"}, {"2E6"}, {"42"}})
+
+		assert.Equal(t, []ColumnType{TypeInteger}, types)
+	})
+
+	t.Run("rejects oversized exponents quickly", func(t *testing.T) {
+		t.Parallel()
+
+		records := make([][]string, 50)
+		for i := range records {
+			records[i] = []string{"1e1000000", "1e-1000000"}
+		}
+
+		start := time.Now()
+		types := inferColumnTypes([]string{"huge", "tiny"}, records)
+		elapsed := time.Since(start)
+
+		assert.Equal(t, []ColumnType{TypeText, TypeReal}, types)
+		assert.Less(t, elapsed, time.Second)
+		assert.Equal(t, "1e1000000", ParseValue("1e1000000", TypeInteger))
+	})
+
 	t.Run("parses integer", func(t *testing.T) {
 		t.Parallel()
 
@@ -160,6 +228,26 @@ func TestParseValue(t *testing.T) {
 		assert.Equal(t, 3.14, result)
 	})
 
+	t.Run("parses boolean", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, true, ParseValue("TRUE", TypeBoolean))
+		assert.Equal(t, false, ParseValue("false", TypeBoolean))
+	})
+
+	t.Run("converts exactly the booleans inference recognizes", func(t *testing.T) {
+		t.Parallel()
+
+		config := InferenceConfig{InferBooleans: true}
+		for _, value := range []string{"tRuE", "FALSE", "1", "t", "F", "yes"} {
+			_, converted := ParseValue(value, TypeBoolean).(bool)
+			assert.Equal(t, config.classifyValue(value) == TypeBoolean, converted, value)
+		}
+		assert.Equal(t, true, ParseValue("tRuE", TypeBoolean))
+		assert.Equal(t, "1", ParseValue("1", TypeBoolean))
+		assert.Equal(t, "t", ParseValue("t", TypeBoolean))
+	})
+
 	t.Run("returns string for text type", func(t *testing.T) {
 		t.Parallel()
 
@@ -184,3 +272,239 @@ func TestParseValue(t *testing.T) {
 		assert.Equal(t, "not-a-number", result)
 	})
 }
+
+func TestInferenceConfig_AllowedTypes(t *testing.T) {
+	t.Parallel()
+
+	headers := []string{"price", "count"}
+	records := [][]string{{"1.99", "1"}, {"2.50", "2"}, {"3.14", "3"}}
+
+	t.Run("falls back to text when the inferred type is not allowed", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := InferenceConfig{AllowedTypes: []ColumnType{TypeText, TypeInteger}}
+
+		types := cfg.inferColumnTypes(headers, records)
+
+		assert.Equal(t, []ColumnType{TypeText, TypeInteger}, types)
+	})
+
+	t.Run("considers the next allowed type", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := InferenceConfig{AllowedTypes: []ColumnType{TypeText, TypeReal}}
+
+		types := cfg.inferColumnTypes(headers, records)
+
+		assert.Equal(t, []ColumnType{TypeReal, TypeReal}, types)
+	})
+
+	t.Run("allows all types by default", func(t *testing.T) {
+		t.Parallel()
+
+		types := InferenceConfig{}.inferColumnTypes(headers, records)
+
+		assert.Equal(t, []ColumnType{TypeReal, TypeInteger}, types)
+	})
+}
+
+func TestInferenceConfig_SampleSize(t *testing.T) {
+	t.Parallel()
+
+	headers := []string{"value"}
+	records := [][]string{{"1"}, {"2"}, {"three"}}
+
+	t.Run("examines only the first SampleSize records", func(t *testing.T) {
+		t.Parallel()
+
+		types := InferenceConfig{SampleSize: 2}.inferColumnTypes(headers, records)
+
+		assert.Equal(t, []ColumnType{TypeInteger}, types)
+	})
+
+	t.Run("examines every record when negative", func(t *testing.T) {
+		t.Parallel()
+
+		types := InferenceConfig{SampleSize: -1}.inferColumnTypes(headers, records)
+
+		assert.Equal(t, []ColumnType{TypeText}, types)
+	})
+}
+
+func TestInferFromSample(t *testing.T) {
+	t.Parallel()
+
+	t.Run("infers a type per column of the widest record", func(t *testing.T) {
+		t.Parallel()
+
+		records := [][]string{
+			{"1", "1.5"},
+			{"2", "2.5", "2024-01-15"},
+		}
+
+		types := InferFromSample(records)
+
+		assert.Equal(t, []ColumnType{TypeInteger, TypeReal, TypeDatetime}, types)
+	})
+
+	t.Run("returns no types for an empty sample", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Empty(t, InferFromSample(nil))
+	})
+
+	t.Run("honors the inference config", func(t *testing.T) {
+		t.Parallel()
+
+		records := [][]string{{"1.5"}, {"2.5"}}
+		cfg := InferenceConfig{AllowedTypes: []ColumnType{TypeText, TypeInteger}}
+
+		assert.Equal(t, []ColumnType{TypeText}, cfg.InferFromSample(records))
+	})
+}
+
+func TestInferenceConfig_Percentages(t *testing.T) {
+	t.Parallel()
+
+	headers := []string{"share"}
+
+	t.Run("infers whole-number percentages as real", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := InferenceConfig{ParsePercentages: true}
+		records := [][]string{{"45%"}, {"100%"}, {"0%"}}
+
+		assert.Equal(t, []ColumnType{TypeReal}, cfg.inferColumnTypes(headers, records))
+		assert.Equal(t, 45.0, cfg.ParseValue("45%", TypeReal))
+	})
+
+	t.Run("infers decimal percentages as real", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := InferenceConfig{ParsePercentages: true}
+		records := [][]string{{"12.5%"}, {"-3.25 %"}}
+
+		assert.Equal(t, []ColumnType{TypeReal}, cfg.inferColumnTypes(headers, records))
+		assert.Equal(t, -3.25, cfg.ParseValue("-3.25 %", TypeReal))
+	})
+
+	t.Run("divides by 100 with PercentAsFraction", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := InferenceConfig{ParsePercentages: true, PercentAsFraction: true}
+
+		assert.Equal(t, 0.45, cfg.ParseValue("45%", TypeReal))
+		assert.Equal(t, 0.125, cfg.ParseValue("12.5%", TypeReal))
+		assert.Equal(t, 0.5, cfg.ParseValue("0.5", TypeReal))
+	})
+
+	t.Run("keeps percentages as text without the option", func(t *testing.T) {
+		t.Parallel()
+
+		records := [][]string{{"45%"}, {"12.5%"}}
+
+		assert.Equal(t, []ColumnType{TypeText}, InferenceConfig{}.inferColumnTypes(headers, records))
+		assert.Equal(t, "45%", InferenceConfig{}.ParseValue("45%", TypeReal))
+	})
+
+	t.Run("does not treat other text with a percent sign as a number", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := InferenceConfig{ParsePercentages: true}
+		records := [][]string{{"abc%"}, {"%"}}
+
+		assert.Equal(t, []ColumnType{TypeText}, cfg.inferColumnTypes(headers, records))
+	})
+}
+
+func TestInferColumnTypesWithStats(t *testing.T) {
+	t.Parallel()
+
+	t.Run("counts the values of each column", func(t *testing.T) {
+		t.Parallel()
+
+		headers := []string{"amount", "note"}
+		records := [][]string{
+			{"1", "a"},
+			{"2.5", ""},
+			{"", "2024-01-15"},
+			{"n/a", "true"},
+			{"3"},
+		}
+
+		types, stats := InferColumnTypesWithStats(headers, records)
+
+		assert.Equal(t, []ColumnType{TypeText, TypeText}, types)
+		assert.Equal(t, ColumnInferenceStats{Total: 5, NonEmpty: 4, IntegerCount: 2, RealCount: 1, TextCount: 1}, stats[0])
+		assert.Equal(t, ColumnInferenceStats{Total: 5, NonEmpty: 3, DatetimeCount: 1, TextCount: 2}, stats[1])
+	})
+
+	t.Run("agrees with the types Parse infers", func(t *testing.T) {
+		t.Parallel()
+
+		headers := []string{"id", "price"}
+		records := [][]string{{"1", "1.5"}, {"2", ""}, {"3", "2.5"}}
+
+		types, stats := InferColumnTypesWithStats(headers, records)
+
+		assert.Equal(t, inferColumnTypes(headers, records), types)
+		assert.Equal(t, 2, stats[1].NonEmpty)
+	})
+
+	t.Run("counts only the sampled records", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := InferenceConfig{SampleSize: 2}
+
+		_, stats := cfg.InferColumnTypesWithStats([]string{"id"}, [][]string{{"1"}, {"2"}, {"3"}})
+
+		assert.Equal(t, 2, stats[0].Total)
+	})
+}
+
+func TestInferenceConfig_Thresholds(t *testing.T) {
+	t.Parallel()
+
+	headers := []string{"value"}
+	// 9 of 10 values are integers
+	records := [][]string{{"1"}, {"2"}, {"3"}, {"4"}, {"5"}, {"6"}, {"7"}, {"8"}, {"9"}, {"n/a"}}
+
+	t.Run("uses 0.8 confidence by default", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, []ColumnType{TypeInteger}, InferenceConfig{}.inferColumnTypes(headers, records))
+	})
+
+	t.Run("requires the configured confidence", func(t *testing.T) {
+		t.Parallel()
+
+		strict := InferenceConfig{Confidence: 0.95}
+
+		assert.Equal(t, []ColumnType{TypeText}, strict.inferColumnTypes(headers, records))
+	})
+
+	t.Run("bounds the length of datetime values", func(t *testing.T) {
+		t.Parallel()
+
+		dates := [][]string{{"2024-01-15"}, {"2024-02-20"}}
+
+		assert.Equal(t, []ColumnType{TypeDatetime}, InferenceConfig{}.inferColumnTypes(headers, dates))
+		assert.Equal(t, []ColumnType{TypeText}, InferenceConfig{MaxDatetimeLen: 8}.inferColumnTypes(headers, dates))
+		assert.Equal(t, []ColumnType{TypeText}, InferenceConfig{MinDatetimeLen: 11, MaxDatetimeLen: 40}.inferColumnTypes(headers, dates))
+	})
+
+	t.Run("validates the settings", func(t *testing.T) {
+		t.Parallel()
+
+		for _, cfg := range []InferenceConfig{
+			{Confidence: -0.1},
+			{Confidence: 1.5},
+			{MinDatetimeLen: -1},
+			{MinDatetimeLen: 40},
+			{MinDatetimeLen: 10, MaxDatetimeLen: 5},
+		} {
+			assert.Error(t, cfg.validate(), "%+v", cfg)
+		}
+		assert.NoError(t, InferenceConfig{Confidence: 1, MinDatetimeLen: 8, MaxDatetimeLen: 20}.validate())
+	})
+}
@@ -0,0 +1,311 @@
+package fileparser
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// WriteOptions configures Write and WriteFile.
+type WriteOptions struct {
+	// StoreOriginalName stores the name of the uncompressed file in the gzip
+	// header when WriteFile writes a .gz file, e.g. "data.csv" for
+	// "out/data.csv.gz". Tools such as gunzip -N and CompressionMetadata can
+	// then recover it. Write has no file name and ignores this option.
+	StoreOriginalName bool
+
+	// CompressionLevel is the compression level of gzip or zstd output, such
+	// as CSVGZ or CSVZSTD: between 1 and 9 for gzip and between 1 and 22 for
+	// zstd. Higher levels produce smaller files more slowly. Zero uses the
+	// default level. A level outside the range, or any nonzero level for
+	// uncompressed output, is an error. Parquet pages use
+	// ParquetCompressionLevel instead.
+	CompressionLevel int
+
+	// Delimiter overrides the field delimiter of CSV and TSV output, e.g. ';'
+	// to write semicolon-separated values from a table parsed as comma CSV.
+	// It must be a valid rune other than a quote, carriage return, or line
+	// feed. Zero uses the delimiter of the file type. LTSV ignores it.
+	Delimiter rune
+
+	// ParquetCodec is the compression codec of Parquet output. The zero
+	// value is Snappy. Other formats ignore it.
+	ParquetCodec ParquetCodec
+
+	// ParquetCompressionLevel is the compression level of Parquet output,
+	// between 1 and 9 for gzip and between 1 and 22 for zstd. Higher levels
+	// produce smaller files more slowly. Zero uses the default level of the
+	// codec. A level outside the range of the codec, or any nonzero level
+	// for Snappy or uncompressed output, is an error.
+	ParquetCompressionLevel int
+}
+
+// Write writes table to w in the format given by fileType.
+// CSV, TSV, and LTSV are supported, optionally gzip- or zstd-compressed at
+// opts.CompressionLevel, as is
+// uncompressed Parquet, whose pages are compressed as opts.ParquetCodec says.
+//
+// Example:
+//
+//	var buf bytes.Buffer
+//	err := fileparser.Write(&buf, table, fileparser.CSV, fileparser.WriteOptions{})
+func Write(w io.Writer, table *TableData, fileType FileType, opts WriteOptions) error {
+	return writeTable(w, table, fileType, opts, "")
+}
+
+// WriteTo writes the table to w as CSV, starting with the header row, and
+// returns the number of bytes written. It implements io.WriterTo, so a table
+// can be streamed into an HTTP response or pipeline. Use As to write another
+// format.
+//
+// Example:
+//
+//	w.Header().Set("Content-Type", "text/csv")
+//	_, err := table.WriteTo(w)
+func (t *TableData) WriteTo(w io.Writer) (int64, error) {
+	return t.As(CSV).WriteTo(w)
+}
+
+// As returns an io.WriterTo that writes the table in the format given by
+// fileType, as Write does.
+//
+// Example:
+//
+//	_, err := table.As(fileparser.TSV).WriteTo(os.Stdout)
+func (t *TableData) As(fileType FileType) io.WriterTo {
+	return tableWriterTo{table: t, fileType: fileType}
+}
+
+// tableWriterTo writes a table in a fixed format.
+type tableWriterTo struct {
+	table    *TableData
+	fileType FileType
+}
+
+// WriteTo implements io.WriterTo.
+func (tw tableWriterTo) WriteTo(w io.Writer) (int64, error) {
+	if w == nil {
+		return 0, errors.New("writer cannot be nil")
+	}
+	cw := &countingWriter{w: w}
+	err := writeTable(cw, tw.table, tw.fileType, WriteOptions{}, "")
+	return cw.n, err
+}
+
+// countingWriter counts the bytes written to the underlying writer.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+// Write implements io.Writer.
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
+
+// WriteFile writes table to the file at path, creating or truncating it.
+// The format and compression are detected from the path extension as in
+// DetectFileType, so "out.tsv.gz" writes gzip-compressed TSV.
+//
+// Example:
+//
+//	err := fileparser.WriteFile("out/data.csv.gz", table, fileparser.WriteOptions{
+//	    StoreOriginalName: true,
+//	})
+func WriteFile(path string, table *TableData, opts WriteOptions) (err error) {
+	fileType := DetectFileType(path)
+	if err := checkWritable(table, fileType, opts); err != nil {
+		return err
+	}
+
+	// The name of the uncompressed file, e.g. "data.csv" for "out/data.csv.gz"
+	base := filepath.Base(path)
+	originalName := strings.TrimSuffix(base, filepath.Ext(base))
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer func() {
+		if closeErr := f.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("failed to close file: %w", closeErr)
+		}
+	}()
+
+	return writeTable(f, table, fileType, opts, originalName)
+}
+
+// checkWritable reports whether table can be written as fileType with opts.
+func checkWritable(table *TableData, fileType FileType, opts WriteOptions) error {
+	if table == nil {
+		return errors.New("table cannot be nil")
+	}
+	if opts.Delimiter != 0 && !validDelimiter(opts.Delimiter) {
+		return fmt.Errorf("invalid delimiter %q", opts.Delimiter)
+	}
+	switch fileType {
+	case CSV, TSV, LTSV:
+		if opts.CompressionLevel != 0 {
+			return fmt.Errorf("compression level is not supported for uncompressed %s", fileType)
+		}
+		return nil
+	case CSVGZ, TSVGZ, LTSVGZ:
+		if level := opts.CompressionLevel; level != 0 && (level < minGzipLevel || level > maxGzipLevel) {
+			return fmt.Errorf("invalid gzip compression level %d: must be between %d and %d", level, minGzipLevel, maxGzipLevel)
+		}
+		return nil
+	case CSVZSTD, TSVZSTD, LTSVZSTD:
+		if level := opts.CompressionLevel; level != 0 && (level < minZstdLevel || level > maxZstdLevel) {
+			return fmt.Errorf("invalid zstd compression level %d: must be between %d and %d", level, minZstdLevel, maxZstdLevel)
+		}
+		return nil
+	case Parquet:
+		if opts.CompressionLevel != 0 {
+			return errors.New("compression level is not supported for Parquet; use ParquetCompressionLevel")
+		}
+		_, err := parquetWriterProperties(opts)
+		return err
+	default:
+		return fmt.Errorf("writing %s is not supported", fileType)
+	}
+}
+
+// writeTable writes table to w as fileType. With opts.StoreOriginalName,
+// originalName is stored in the gzip header when fileType is gzip-compressed.
+func writeTable(w io.Writer, table *TableData, fileType FileType, opts WriteOptions, originalName string) error {
+	if w == nil {
+		return errors.New("writer cannot be nil")
+	}
+	if err := checkWritable(table, fileType, opts); err != nil {
+		return err
+	}
+
+	if !IsCompressed(fileType) {
+		return writeUncompressed(w, table, fileType, opts)
+	}
+
+	cw, name, err := newCompressedWriter(w, fileType, opts, originalName)
+	if err != nil {
+		return err
+	}
+	if err := writeUncompressed(cw, table, BaseFileType(fileType), opts); err != nil {
+		_ = cw.Close()
+		return err
+	}
+	// Close flushes the encoder; without it the output would be truncated
+	if err := cw.Close(); err != nil {
+		return fmt.Errorf("failed to write %s data: %w", name, err)
+	}
+	return nil
+}
+
+// newCompressedWriter returns a writer that compresses to w in the
+// compression format of fileType at opts.CompressionLevel, along with the
+// name of the format. The writer must be closed to flush the output.
+func newCompressedWriter(w io.Writer, fileType FileType, opts WriteOptions, originalName string) (io.WriteCloser, string, error) {
+	switch fileType {
+	case CSVZSTD, TSVZSTD, LTSVZSTD:
+		encOpts := []zstd.EOption{}
+		if opts.CompressionLevel != 0 {
+			encOpts = append(encOpts, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(opts.CompressionLevel)))
+		}
+		zw, err := zstd.NewWriter(w, encOpts...)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to create zstd writer: %w", err)
+		}
+		return zw, "zstd", nil
+	default:
+		level := gzip.DefaultCompression
+		if opts.CompressionLevel != 0 {
+			level = opts.CompressionLevel
+		}
+		gzWriter, err := gzip.NewWriterLevel(w, level)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to create gzip writer: %w", err)
+		}
+		if opts.StoreOriginalName {
+			gzWriter.Name = originalName
+		}
+		return gzWriter, "gzip", nil
+	}
+}
+
+// validDelimiter reports whether r can separate fields in CSV or TSV output.
+func validDelimiter(r rune) bool {
+	return r != '"' && r != '\r' && r != '\n' && utf8.ValidRune(r) && r != utf8.RuneError
+}
+
+// writeUncompressed writes table to w as CSV, TSV, LTSV, or Parquet.
+func writeUncompressed(w io.Writer, table *TableData, fileType FileType, opts WriteOptions) error {
+	switch fileType {
+	case CSV, TSV:
+		csvWriter := csv.NewWriter(w)
+		if fileType == TSV {
+			csvWriter.Comma = '\t'
+		}
+		if opts.Delimiter != 0 {
+			csvWriter.Comma = opts.Delimiter
+		}
+		if err := csvWriter.Write(table.Headers); err != nil {
+			return fmt.Errorf("failed to write %s: %w", fileType, err)
+		}
+		for _, record := range table.Records {
+			if err := csvWriter.Write(record); err != nil {
+				return fmt.Errorf("failed to write %s: %w", fileType, err)
+			}
+		}
+		csvWriter.Flush()
+		if err := csvWriter.Error(); err != nil {
+			return fmt.Errorf("failed to write %s: %w", fileType, err)
+		}
+		return nil
+
+	case LTSV:
+		return writeLTSV(w, table)
+
+	case Parquet:
+		return writeParquet(w, table, opts)
+
+	default:
+		return fmt.Errorf("writing %s is not supported", fileType)
+	}
+}
+
+// writeLTSV writes each record as a line of label:value pairs separated by tabs.
+// LTSV has no escaping, so values containing tabs or line breaks are rejected.
+func writeLTSV(w io.Writer, table *TableData) error {
+	bw := bufio.NewWriter(w)
+	for i, record := range table.Records {
+		for j, header := range table.Headers {
+			value := ""
+			if j < len(record) {
+				value = record[j]
+			}
+			if strings.ContainsAny(value, "\t\r\n") {
+				return fmt.Errorf("record %d: LTSV value of %q contains a tab or line break", i+1, header)
+			}
+			if j > 0 {
+				bw.WriteByte('\t') //nolint:errcheck // reported by Flush
+			}
+			bw.WriteString(header) //nolint:errcheck // reported by Flush
+			bw.WriteByte(':')      //nolint:errcheck // reported by Flush
+			bw.WriteString(value)  //nolint:errcheck // reported by Flush
+		}
+		bw.WriteByte('\n') //nolint:errcheck // reported by Flush
+	}
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("failed to write LTSV: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,345 @@
+package fileparser
+
+import (
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+
+	table := &TableData{
+		Headers: []string{"id", "note"},
+		Records: [][]string{{"1", "hello, world"}, {"2", "plain"}},
+	}
+
+	t.Run("writes CSV with quoting", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		err := Write(&buf, table, CSV, WriteOptions{})
+
+		require.NoError(t, err)
+		assert.Equal(t, "id,note\n1,\"hello, world\"\n2,plain\n", buf.String())
+	})
+
+	t.Run("writes TSV", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		err := Write(&buf, table, TSV, WriteOptions{})
+
+		require.NoError(t, err)
+		assert.Equal(t, "id\tnote\n1\thello, world\n2\tplain\n", buf.String())
+	})
+
+	t.Run("writes a parsed CSV with a semicolon delimiter", func(t *testing.T) {
+		t.Parallel()
+
+		parsed, err := Parse(strings.NewReader("id,note\n1,a;b\n2,plain"), CSV)
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		err = Write(&buf, parsed, CSV, WriteOptions{Delimiter: ';'})
+
+		require.NoError(t, err)
+		assert.Equal(t, "id;note\n1;\"a;b\"\n2;plain\n", buf.String())
+	})
+
+	t.Run("rejects an invalid delimiter", func(t *testing.T) {
+		t.Parallel()
+
+		err := Write(&bytes.Buffer{}, table, CSV, WriteOptions{Delimiter: '"'})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid delimiter")
+	})
+
+	t.Run("writes LTSV", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		err := Write(&buf, table, LTSV, WriteOptions{})
+
+		require.NoError(t, err)
+		assert.Equal(t, "id:1\tnote:hello, world\nid:2\tnote:plain\n", buf.String())
+	})
+
+	t.Run("rejects LTSV values with tabs", func(t *testing.T) {
+		t.Parallel()
+
+		bad := &TableData{Headers: []string{"a"}, Records: [][]string{{"x\ty"}}}
+		err := Write(&bytes.Buffer{}, bad, LTSV, WriteOptions{})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "contains a tab or line break")
+	})
+
+	t.Run("round-trips gzip-compressed CSV", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		require.NoError(t, Write(&buf, table, CSVGZ, WriteOptions{}))
+
+		result, err := Parse(&buf, CSVGZ)
+
+		require.NoError(t, err)
+		assert.Equal(t, table.Headers, result.Headers)
+		assert.Equal(t, table.Records, result.Records)
+	})
+
+	t.Run("round-trips zstd-compressed CSV", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		require.NoError(t, Write(&buf, table, CSVZSTD, WriteOptions{}))
+
+		result, err := Parse(&buf, CSVZSTD)
+
+		require.NoError(t, err)
+		assert.Equal(t, table.Headers, result.Headers)
+		assert.Equal(t, table.Records, result.Records)
+	})
+
+	t.Run("writes every compression level", func(t *testing.T) {
+		t.Parallel()
+
+		for _, tc := range []struct {
+			fileType FileType
+			level    int
+		}{
+			{CSVGZ, 1},
+			{TSVGZ, 9},
+			{LTSVGZ, 5},
+			{CSVZSTD, 1},
+			{TSVZSTD, 19},
+			{LTSVZSTD, 22},
+		} {
+			var buf bytes.Buffer
+			require.NoError(t, Write(&buf, table, tc.fileType, WriteOptions{CompressionLevel: tc.level}), "%s level %d", tc.fileType, tc.level)
+
+			result, err := Parse(&buf, tc.fileType)
+
+			require.NoError(t, err)
+			assert.Equal(t, table.Records, result.Records, "%s level %d", tc.fileType, tc.level)
+		}
+	})
+
+	t.Run("rejects invalid compression levels", func(t *testing.T) {
+		t.Parallel()
+
+		testCases := []struct {
+			fileType FileType
+			level    int
+			message  string
+		}{
+			{CSVGZ, 10, "invalid gzip compression level 10"},
+			{CSVZSTD, 23, "invalid zstd compression level 23"},
+			{TSVZSTD, -1, "invalid zstd compression level -1"},
+			{CSV, 3, "compression level is not supported for uncompressed CSV"},
+			{Parquet, 3, "use ParquetCompressionLevel"},
+		}
+
+		for _, tc := range testCases {
+			var buf bytes.Buffer
+			err := Write(&buf, table, tc.fileType, WriteOptions{CompressionLevel: tc.level})
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.message)
+			assert.Zero(t, buf.Len())
+		}
+	})
+
+	t.Run("returns error for unsupported file type", func(t *testing.T) {
+		t.Parallel()
+
+		err := Write(&bytes.Buffer{}, table, XLSX, WriteOptions{})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "writing XLSX is not supported")
+	})
+
+	t.Run("returns error for nil table", func(t *testing.T) {
+		t.Parallel()
+
+		err := Write(&bytes.Buffer{}, nil, CSV, WriteOptions{})
+
+		require.Error(t, err)
+	})
+}
+
+func TestWriteFile(t *testing.T) {
+	t.Parallel()
+
+	table := &TableData{
+		Headers: []string{"id", "name"},
+		Records: [][]string{{"1", "Alice"}},
+	}
+
+	t.Run("stores the original name in the gzip header", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "export.csv.gz")
+		require.NoError(t, WriteFile(path, table, WriteOptions{StoreOriginalName: true}))
+
+		f, err := os.Open(path)
+		require.NoError(t, err)
+		defer f.Close()
+		gzReader, err := gzip.NewReader(f)
+		require.NoError(t, err)
+		assert.Equal(t, "export.csv", gzReader.Name)
+	})
+
+	t.Run("original name round-trips through CompressionMetadata", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "report.tsv.gz")
+		require.NoError(t, WriteFile(path, table, WriteOptions{StoreOriginalName: true}))
+
+		f, err := os.Open(path)
+		require.NoError(t, err)
+		defer f.Close()
+		info, err := CompressionMetadata(f)
+
+		require.NoError(t, err)
+		assert.Equal(t, "gzip", info.Format)
+		assert.Equal(t, "report.tsv", info.OriginalName)
+	})
+
+	t.Run("leaves the name empty by default", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "export.csv.gz")
+		require.NoError(t, WriteFile(path, table, WriteOptions{}))
+
+		f, err := os.Open(path)
+		require.NoError(t, err)
+		defer f.Close()
+		info, err := CompressionMetadata(f)
+
+		require.NoError(t, err)
+		assert.Empty(t, info.OriginalName)
+	})
+
+	t.Run("writes uncompressed files", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "export.csv")
+		require.NoError(t, WriteFile(path, table, WriteOptions{StoreOriginalName: true}))
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "id,name\n1,Alice\n", string(data))
+	})
+
+	t.Run("does not create a file for unsupported types", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "export.xlsx")
+		err := WriteFile(path, table, WriteOptions{})
+
+		require.Error(t, err)
+		assert.NoFileExists(t, path)
+	})
+}
+
+func TestCompressionMetadata(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		file   string
+		format string
+	}{
+		{name: "gzip", file: "sample.csv.gz", format: "gzip"},
+		{name: "zlib", file: "sample.csv.z", format: "zlib"},
+		{name: "snappy", file: "sample.csv.snappy", format: "snappy"},
+		{name: "s2", file: "sample.csv.s2", format: "s2"},
+		{name: "lz4", file: "sample.csv.lz4", format: "lz4"},
+		{name: "zstd", file: "users.csv.zst", format: "zstd"},
+		{name: "bzip2", file: "products.tsv.bz2", format: "bzip2"},
+		{name: "xz", file: "logs.ltsv.xz", format: "xz"},
+		{name: "uncompressed", file: "sample.csv", format: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			f, err := os.Open(filepath.Join("testdata", tt.file))
+			require.NoError(t, err)
+			defer f.Close()
+
+			info, err := CompressionMetadata(f)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.format, info.Format)
+		})
+	}
+
+	t.Run("empty input is uncompressed", func(t *testing.T) {
+		t.Parallel()
+
+		info, err := CompressionMetadata(strings.NewReader(""))
+
+		require.NoError(t, err)
+		assert.Equal(t, CompressionInfo{}, info)
+	})
+
+	t.Run("returns error for nil reader", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := CompressionMetadata(nil)
+
+		require.Error(t, err)
+	})
+}
+
+func TestTableData_WriteTo(t *testing.T) {
+	t.Parallel()
+
+	table := &TableData{
+		Headers:     []string{"id", "name"},
+		Records:     [][]string{{"1", "Alice"}, {"2", "Bob, Jr."}},
+		ColumnTypes: []ColumnType{TypeInteger, TypeText},
+	}
+
+	t.Run("writes CSV and reports the bytes written", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		n, err := table.WriteTo(&buf)
+
+		require.NoError(t, err)
+		assert.Equal(t, "id,name\n1,Alice\n2,\"Bob, Jr.\"\n", buf.String())
+		assert.Equal(t, int64(buf.Len()), n)
+	})
+
+	t.Run("writes the format selected with As", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		n, err := table.As(LTSV).WriteTo(&buf)
+
+		require.NoError(t, err)
+		assert.Equal(t, "id:1\tname:Alice\nid:2\tname:Bob, Jr.\n", buf.String())
+		assert.Equal(t, int64(buf.Len()), n)
+	})
+
+	t.Run("rejects unsupported formats", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		n, err := table.As(XLSX).WriteTo(&buf)
+
+		require.Error(t, err)
+		assert.Zero(t, n)
+	})
+}
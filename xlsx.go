@@ -1,69 +1,283 @@
 package fileparser
 
 import (
+	"archive/zip"
 	"bytes"
 	"errors"
 	"fmt"
 	"io"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/xuri/excelize/v2"
 )
 
 // parseXLSX parses Excel XLSX data.
-func parseXLSX(reader io.Reader) (*TableData, error) {
-	// Read all data into memory (excelize requires this)
-	data, err := io.ReadAll(reader)
+func parseXLSX(reader io.Reader, opts ParseOptions) (*TableData, error) {
+	f, err := openXLSX(reader, opts.Password)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read XLSX data: %w", err)
+		return nil, err
 	}
+	defer f.Close()
 
-	f, err := excelize.OpenReader(bytes.NewReader(data))
+	sheetName, err := selectSheet(f.GetSheetList(), opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open XLSX: %w", err)
+		return nil, err
+	}
+
+	rows, err := readXLSXSheet(f, sheetName, opts.MaxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		return nil, withKind(errors.New("empty XLSX sheet"), ErrEmptyData)
+	}
+
+	return xlsxRowsToTable(f.File, sheetName, rows, opts)
+}
+
+// ParseXLSXAllSheets parses every sheet of an XLSX workbook and returns one
+// TableData per sheet, keyed by sheet name. The first row of each sheet is
+// treated as the header.
+//
+// Empty sheets are not skipped: they map to a TableData with nil Headers and
+// nil Records so callers can still detect that the sheet exists.
+//
+// Example:
+//
+//	f, _ := os.Open("workbook.xlsx")
+//	defer f.Close()
+//	sheets, err := fileparser.ParseXLSXAllSheets(f)
+//	report := sheets["Report"]
+func ParseXLSXAllSheets(r io.Reader) (map[string]*TableData, error) {
+	if r == nil {
+		return nil, errors.New("reader cannot be nil")
+	}
+
+	f, err := openXLSX(r, "")
+	if err != nil {
+		return nil, err
 	}
 	defer f.Close()
 
-	// Get the first sheet
 	sheets := f.GetSheetList()
 	if len(sheets) == 0 {
-		return nil, errors.New("no sheets found in XLSX file")
+		return nil, withKind(errors.New("no sheets found in XLSX file"), ErrEmptyData)
+	}
+
+	tables := make(map[string]*TableData, len(sheets))
+	for _, sheetName := range sheets {
+		rows, err := readXLSXSheet(f, sheetName, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(rows) == 0 || len(rows[0]) == 0 {
+			tables[sheetName] = &TableData{}
+			continue
+		}
+
+		table, err := xlsxRowsToTable(f.File, sheetName, rows, ParseOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("sheet %s: %w", sheetName, err)
+		}
+		tables[sheetName] = table
 	}
 
-	sheetName := sheets[0]
-	rows, err := f.GetRows(sheetName)
+	return tables, nil
+}
+
+// SheetInfo describes a sheet of an XLSX workbook.
+type SheetInfo struct {
+	// Name is the name of the sheet.
+	Name string
+	// RowCount is the number of the last used row, counting the header row,
+	// or 0 for an empty sheet.
+	RowCount int
+	// ColCount is the number of the last used column, or 0 for an empty sheet.
+	ColCount int
+}
+
+// XLSXSheets lists the sheets of an XLSX workbook in workbook order with
+// their sizes, e.g. to let a user pick the sheet to parse with
+// ParseOptions.SheetName. The sizes come from the dimension recorded in each
+// sheet, so cell values are not read. Sheets without a recorded dimension,
+// such as those written by some libraries, are scanned row by row instead.
+//
+// Example:
+//
+//	f, _ := os.Open("workbook.xlsx")
+//	defer f.Close()
+//	sheets, err := fileparser.XLSXSheets(f)
+//	for _, sheet := range sheets {
+//	    fmt.Printf("%s: %d rows, %d columns\n", sheet.Name, sheet.RowCount, sheet.ColCount)
+//	}
+func XLSXSheets(r io.Reader) ([]SheetInfo, error) {
+	if r == nil {
+		return nil, errors.New("reader cannot be nil")
+	}
+
+	f, err := openXLSX(r, "")
 	if err != nil {
-		return nil, fmt.Errorf("failed to read sheet %s: %w", sheetName, err)
+		return nil, err
 	}
+	defer f.Close()
 
-	if len(rows) == 0 {
-		return nil, errors.New("empty XLSX sheet")
+	sheets := f.GetSheetList()
+	infos := make([]SheetInfo, 0, len(sheets))
+	for _, sheetName := range sheets {
+		info, err := xlsxSheetInfo(f.File, sheetName)
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// xlsxSheetInfo returns the size of the sheet sheetName, taken from its
+// dimension when it records a cell range.
+func xlsxSheetInfo(f *excelize.File, sheetName string) (info SheetInfo, err error) {
+	info.Name = sheetName
+
+	ref, err := f.GetSheetDimension(sheetName)
+	if err != nil {
+		return info, fmt.Errorf("failed to read sheet %s: %w", sheetName, err)
+	}
+	// A single cell such as "A1" is also written for sheets whose dimension
+	// was never updated, so only a range is trusted
+	if _, last, ok := strings.Cut(ref, ":"); ok {
+		if col, row, err := excelize.CellNameToCoordinates(last); err == nil {
+			info.RowCount, info.ColCount = row, col
+			return info, nil
+		}
+	}
+
+	rows, err := f.Rows(sheetName)
+	if err != nil {
+		return info, fmt.Errorf("failed to read sheet %s: %w", sheetName, err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("failed to close sheet %s: %w", sheetName, closeErr)
+		}
+	}()
+
+	for row := 1; rows.Next(); row++ {
+		columns, err := rows.Columns()
+		if err != nil {
+			return info, fmt.Errorf("failed to read sheet %s: %w", sheetName, err)
+		}
+		if len(columns) > 0 {
+			info.RowCount = row
+			info.ColCount = max(info.ColCount, len(columns))
+		}
+	}
+	if err := rows.Error(); err != nil {
+		return info, fmt.Errorf("failed to read sheet %s: %w", sheetName, err)
+	}
+	return info, nil
+}
+
+// xlsxMagic is the ZIP local file header signature that starts every XLSX file.
+var xlsxMagic = []byte("PK\x03\x04")
+
+// encryptedXLSXMagic is the signature of the OLE compound file that wraps an
+// encrypted XLSX workbook, and encryptedPackageName is the UTF-16LE name of
+// the stream holding the encrypted workbook, which tells it apart from a
+// legacy XLS file in the same container.
+var (
+	encryptedXLSXMagic   = []byte("\xD0\xCF\x11\xE0\xA1\xB1\x1A\xE1")
+	encryptedPackageName = []byte("E\x00n\x00c\x00r\x00y\x00p\x00t\x00e\x00d\x00P\x00a\x00c\x00k\x00a\x00g\x00e\x00")
+)
+
+// isEncryptedXLSX reports whether data is a password-protected XLSX workbook.
+func isEncryptedXLSX(data []byte) bool {
+	return bytes.HasPrefix(data, encryptedXLSXMagic) && bytes.Contains(data, encryptedPackageName)
+}
+
+// openXLSX reads all XLSX data from reader and opens it as a workbook,
+// decrypting it with password if it is encrypted.
+func openXLSX(reader io.Reader, password string) (*xlsxWorkbook, error) {
+	// Read all data into memory (excelize requires this)
+	data, err := readAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read XLSX data: %w", err)
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("failed to open XLSX: %w", withKind(errors.New("empty XLSX file"), ErrEmptyData))
+	}
+	if err := checkBinaryInput(data, xlsxMagic, "XLSX"); err != nil {
+		return nil, fmt.Errorf("failed to open XLSX: %w", err)
+	}
+
+	encrypted := isEncryptedXLSX(data)
+	if encrypted {
+		if password == "" {
+			return nil, fmt.Errorf("failed to open XLSX: %w: a password is required", ErrEncrypted)
+		}
+		// A wrong password fails here or yields data that is not a ZIP file
+		if data, err = excelize.Decrypt(data, &excelize.Options{Password: password}); err != nil {
+			return nil, fmt.Errorf("failed to open XLSX: %w: wrong password", ErrEncrypted)
+		}
 	}
 
+	pkg, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		if encrypted {
+			return nil, fmt.Errorf("failed to open XLSX: %w: wrong password", ErrEncrypted)
+		}
+		return nil, fmt.Errorf("failed to open XLSX: %w", err)
+	}
+	f, err := excelize.OpenReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open XLSX: %w", err)
+	}
+	return &xlsxWorkbook{File: f, pkg: pkg}, nil
+}
+
+// xlsxRowsToTable converts the rows of a sheet, as read by readXLSXSheet,
+// into TableData. rows must not be empty; its first row is the header.
+func xlsxRowsToTable(f *excelize.File, sheetName string, rows [][]string, opts ParseOptions) (*TableData, error) {
 	headers := rows[0]
 	if len(headers) == 0 {
-		return nil, errors.New("no headers found in XLSX")
+		return nil, withKind(errors.New("no headers found in XLSX"), ErrEmptyData)
 	}
+	// Boolean cells were read as "true" and "false"
+	opts.Inference.InferBooleans = true
 
-	if err := validateColumnNames(headers); err != nil {
-		return nil, err
+	if opts.MaxRows > 0 && len(rows)-1 > opts.MaxRows {
+		rows = rows[:opts.MaxRows+1]
 	}
 
 	// Normalize records to match header length
 	records := make([][]string, 0, len(rows)-1)
 	for i := 1; i < len(rows); i++ {
-		row := rows[i]
 		// Pad or truncate to match header length
-		normalizedRow := make([]string, len(headers))
-		for j := range headers {
-			if j < len(row) {
-				normalizedRow[j] = row[j]
-			}
+		records = append(records, fitRecord(rows[i], len(headers)))
+	}
+
+	headers, records, hasHeader := resolveHeader(opts.HeaderDetection, opts.Inference, headers, records, opts.MaxRows)
+	if err := validateColumnNames(headers); err != nil {
+		return nil, err
+	}
+
+	if opts.XLSX.ExtractHyperlinks {
+		var err error
+		firstDataRow := 1
+		if hasHeader {
+			firstDataRow = 2
+		}
+		headers, records, err = appendHyperlinkColumns(f, sheetName, headers, records, firstDataRow)
+		if err != nil {
+			return nil, err
 		}
-		records = append(records, normalizedRow)
 	}
 
 	// Infer column types
-	columnTypes := inferColumnTypes(headers, records)
+	columnTypes := opts.inferColumnTypes(headers, records)
 
 	return &TableData{
 		Headers:     headers,
@@ -71,3 +285,180 @@ func parseXLSX(reader io.Reader) (*TableData, error) {
 		ColumnTypes: columnTypes,
 	}, nil
 }
+
+// appendHyperlinkColumns appends a "<column>_url" column holding the hyperlink
+// target for every column that has at least one hyperlinked data cell.
+// Records start at the one-based sheet row firstDataRow.
+func appendHyperlinkColumns(f *excelize.File, sheetName string, headers []string, records [][]string, firstDataRow int) ([]string, [][]string, error) {
+	var linkHeaders []string
+	var linkColumns [][]string
+	for j, header := range headers {
+		var targets []string
+		for i := range records {
+			cell, err := excelize.CoordinatesToCellName(j+1, i+firstDataRow)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to resolve cell name: %w", err)
+			}
+			hasLink, target, err := f.GetCellHyperLink(sheetName, cell)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to read hyperlink of cell %s: %w", cell, err)
+			}
+			if hasLink {
+				if targets == nil {
+					targets = make([]string, len(records))
+				}
+				targets[i] = target
+			}
+		}
+		if targets != nil {
+			linkHeaders = append(linkHeaders, header+"_url")
+			linkColumns = append(linkColumns, targets)
+		}
+	}
+
+	if len(linkHeaders) == 0 {
+		return headers, records, nil
+	}
+
+	newHeaders := append(append([]string{}, headers...), linkHeaders...)
+	if err := validateColumnNames(newHeaders); err != nil {
+		return nil, nil, err
+	}
+
+	for i := range records {
+		for _, targets := range linkColumns {
+			records[i] = append(records[i], targets[i])
+		}
+	}
+
+	return newHeaders, records, nil
+}
+
+// expandMergedCells copies the top-left value of every merged range into the
+// other cells of the range, so merged cells do not leave empty values that
+// misalign columns. mergeCells holds the merged ranges, e.g. "A1:B2". Rows
+// are extended as needed.
+//
+// In the header row, cells filled from a horizontally merged range get a
+// numeric suffix ("Sales", "Sales_2", "Sales_3") to keep column names unique.
+func expandMergedCells(rows [][]string, mergeCells []string) ([][]string, error) {
+	for _, mergeCell := range mergeCells {
+		start, end, ok := strings.Cut(mergeCell, ":")
+		if !ok {
+			end = start
+		}
+		startCol, startRow, err := excelize.CellNameToCoordinates(start)
+		if err != nil {
+			return nil, err
+		}
+		endCol, endRow, err := excelize.CellNameToCoordinates(end)
+		if err != nil {
+			return nil, err
+		}
+
+		value := ""
+		if startRow-1 < len(rows) && startCol-1 < len(rows[startRow-1]) {
+			value = rows[startRow-1][startCol-1]
+		}
+		if value == "" {
+			continue
+		}
+
+		for len(rows) < endRow {
+			rows = append(rows, nil)
+		}
+		for i := startRow - 1; i < endRow; i++ {
+			for len(rows[i]) < endCol {
+				rows[i] = append(rows[i], "")
+			}
+			for j := startCol - 1; j < endCol; j++ {
+				switch {
+				case i == startRow-1 && j == startCol-1:
+					// Top-left cell already holds the value
+				case i == 0 && j > startCol-1:
+					rows[i][j] = value + "_" + strconv.Itoa(j-startCol+2)
+				default:
+					rows[i][j] = value
+				}
+			}
+		}
+	}
+
+	return rows, nil
+}
+
+// Excel date and time layouts used when converting date serial numbers.
+const (
+	xlsxDateLayout     = "2006-01-02"
+	xlsxDateTimeLayout = "2006-01-02 15:04:05"
+)
+
+// builtinDateNumFmts lists the built-in number format IDs that display a date.
+var builtinDateNumFmts = map[int]bool{
+	14: true, // m/d/yyyy
+	15: true, // d-mmm-yy
+	16: true, // d-mmm
+	17: true, // mmm-yy
+	22: true, // m/d/yyyy h:mm
+}
+
+// isDateStyle reports whether the cell style displays its value as a date.
+func isDateStyle(f *excelize.File, styleID int) bool {
+	style, err := f.GetStyle(styleID)
+	if err != nil || style == nil {
+		return false
+	}
+	if style.CustomNumFmt != nil {
+		return isDateFormatCode(*style.CustomNumFmt)
+	}
+	return builtinDateNumFmts[style.NumFmt]
+}
+
+// isDateFormatCode reports whether a custom number format code contains a
+// year or day token. Quoted literals, escaped characters, and bracketed
+// sections such as colors and locales are ignored. Month tokens alone are not
+// enough because "m" also means minutes.
+func isDateFormatCode(code string) bool {
+	inQuote := false
+	inBracket := false
+	for i := 0; i < len(code); i++ {
+		c := code[i]
+		switch {
+		case inQuote:
+			inQuote = c != '"'
+		case inBracket:
+			inBracket = c != ']'
+		case c == '"':
+			inQuote = true
+		case c == '[':
+			inBracket = true
+		case c == '\\' || c == '_' || c == '*':
+			i++ // skip the escaped or padding character
+		case c == 'y' || c == 'Y' || c == 'd' || c == 'D':
+			return true
+		}
+	}
+	return false
+}
+
+// selectSheet returns the name of the sheet chosen by opts.SheetName or
+// opts.SheetIndex, defaulting to the first sheet of the workbook.
+func selectSheet(sheets []string, opts ParseOptions) (string, error) {
+	if len(sheets) == 0 {
+		return "", withKind(errors.New("no sheets found in XLSX file"), ErrEmptyData)
+	}
+
+	if opts.SheetName != "" {
+		if slices.Contains(sheets, opts.SheetName) {
+			return opts.SheetName, nil
+		}
+		return "", fmt.Errorf("sheet %q not found in XLSX file (available sheets: %s)",
+			opts.SheetName, strings.Join(sheets, ", "))
+	}
+
+	if opts.SheetIndex < 0 || opts.SheetIndex >= len(sheets) {
+		return "", fmt.Errorf("sheet index %d out of range in XLSX file (available sheets: %s)",
+			opts.SheetIndex, strings.Join(sheets, ", "))
+	}
+	return sheets[opts.SheetIndex], nil
+}
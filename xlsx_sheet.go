@@ -0,0 +1,475 @@
+package fileparser
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// xlsxWorkbook is an open XLSX workbook along with its ZIP package, from
+// which worksheets are read as a stream.
+type xlsxWorkbook struct {
+	*excelize.File
+	pkg *zip.Reader
+}
+
+// xlsxRelationships is a package relationships part, such as
+// "_rels/.rels" or "xl/_rels/workbook.xml.rels".
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Type   string `xml:"Type,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+// xlsxWorkbookSheets lists the sheets of the workbook part. ID is the r:id
+// attribute that refers to the worksheet in the workbook relationships.
+type xlsxWorkbookSheets struct {
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+		ID   string `xml:"id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+// defaultWorkbookPath is the path of the workbook part when the package
+// relationships do not name one.
+const defaultWorkbookPath = "xl/workbook.xml"
+
+// readPart decodes the XML part at name of the package into v.
+func (wb *xlsxWorkbook) readPart(name string, v any) error {
+	part, err := wb.pkg.Open(name)
+	if err != nil {
+		return err
+	}
+	defer part.Close()
+	return xml.NewDecoder(part).Decode(v)
+}
+
+// worksheetPath returns the path of the worksheet part of sheetName.
+func (wb *xlsxWorkbook) worksheetPath(sheetName string) (string, error) {
+	workbookPath := defaultWorkbookPath
+	var rootRels xlsxRelationships
+	if err := wb.readPart("_rels/.rels", &rootRels); err == nil {
+		for _, rel := range rootRels.Relationships {
+			if strings.HasSuffix(rel.Type, "/officeDocument") {
+				workbookPath = strings.TrimPrefix(rel.Target, "/")
+			}
+		}
+	}
+
+	var workbook xlsxWorkbookSheets
+	if err := wb.readPart(workbookPath, &workbook); err != nil {
+		return "", fmt.Errorf("failed to read workbook: %w", err)
+	}
+	dir, file := path.Split(workbookPath)
+	var rels xlsxRelationships
+	if err := wb.readPart(dir+"_rels/"+file+".rels", &rels); err != nil {
+		return "", fmt.Errorf("failed to read workbook relationships: %w", err)
+	}
+
+	for _, sheet := range workbook.Sheets {
+		if sheet.Name != sheetName {
+			continue
+		}
+		for _, rel := range rels.Relationships {
+			if rel.ID != sheet.ID {
+				continue
+			}
+			if strings.HasPrefix(rel.Target, "/") {
+				return strings.TrimPrefix(rel.Target, "/"), nil
+			}
+			return path.Join(dir, rel.Target), nil
+		}
+	}
+	return "", fmt.Errorf("worksheet of sheet %s not found", sheetName)
+}
+
+// readXLSXSheet returns the rows of the sheet sheetName as GetRows does, in a
+// single pass over the sheet. Alongside the rows iterator of excelize, which
+// gives the formatted value of every cell, the worksheet XML is scanned for
+// what the iterator does not expose: date-formatted serial numbers are
+// converted to ISO 8601 strings, boolean cells to "true" and "false",
+// formulas without a cached result are calculated, and merged ranges are
+// expanded.
+//
+// With maxRows > 0, reading stops after the header row and maxRows more
+// rows, so the rest of the sheet is never parsed.
+func readXLSXSheet(wb *xlsxWorkbook, sheetName string, maxRows int) (_ [][]string, err error) {
+	partPath, err := wb.worksheetPath(sheetName)
+	if err != nil {
+		return nil, err
+	}
+	part, err := wb.pkg.Open(partPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sheet %s: %w", sheetName, err)
+	}
+	defer part.Close()
+	scanner := newXLSXSheetScanner(part)
+
+	rows, err := wb.Rows(sheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sheet %s: %w", sheetName, err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("failed to close sheet %s: %w", sheetName, closeErr)
+		}
+	}()
+
+	resolver, err := newXLSXCellResolver(wb, sheetName)
+	if err != nil {
+		return nil, err
+	}
+
+	// As in GetRows, rows without values are kept only when a later row
+	// has values, so the result ends with the last row that has values
+	var results [][]string
+	scanned := xlsxScannedRow{}
+	for rowNum := 1; rows.Next(); rowNum++ {
+		row, err := rows.Columns()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read sheet %s: %w", sheetName, err)
+		}
+		for scanned.num < rowNum {
+			if scanned, err = scanner.next(); err != nil {
+				return nil, fmt.Errorf("failed to read sheet %s: %w", sheetName, err)
+			}
+			if scanned.num == 0 {
+				break
+			}
+		}
+		if scanned.num == rowNum {
+			row = resolver.resolve(rowNum, row, scanned.cells)
+		}
+
+		if len(row) > 0 {
+			for len(results) < rowNum-1 {
+				results = append(results, nil)
+			}
+			results = append(results, row)
+		}
+		if maxRows > 0 && rowNum > maxRows {
+			break
+		}
+	}
+	if err := rows.Error(); err != nil {
+		return nil, fmt.Errorf("failed to read sheet %s: %w", sheetName, err)
+	}
+
+	mergeCells, err := scanner.mergeCells()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read merged cells of sheet %s: %w", sheetName, err)
+	}
+	return expandMergedCells(results, mergeCells)
+}
+
+// xlsxCellResolver rewrites formatted cell values using the cell details
+// found by xlsxSheetScanner.
+type xlsxCellResolver struct {
+	file       *excelize.File
+	sheetName  string
+	date1904   bool
+	dateStyles map[int]bool
+}
+
+// newXLSXCellResolver returns a resolver for the cells of the sheet
+// sheetName of wb.
+func newXLSXCellResolver(wb *xlsxWorkbook, sheetName string) (*xlsxCellResolver, error) {
+	props, err := wb.GetWorkbookProps()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workbook properties: %w", err)
+	}
+	return &xlsxCellResolver{
+		file:       wb.File,
+		sheetName:  sheetName,
+		date1904:   props.Date1904 != nil && *props.Date1904,
+		dateStyles: make(map[int]bool),
+	}, nil
+}
+
+// resolve rewrites row, the formatted values of the row numbered rowNum,
+// using cells, the details of the cells of the same row. Whether a style
+// displays dates is looked up once per style.
+func (r *xlsxCellResolver) resolve(rowNum int, row []string, cells []xlsxCell) []string {
+	for _, cell := range cells {
+		j := cell.col - 1
+		if j < 0 {
+			continue
+		}
+		if cell.formula && (j >= len(row) || row[j] == "") {
+			if value := r.formulaValue(rowNum, cell); value != "" {
+				for len(row) <= j {
+					row = append(row, "")
+				}
+				row[j] = value
+			}
+			continue
+		}
+		if j >= len(row) {
+			continue
+		}
+
+		switch cell.typ {
+		case "b":
+			// GetRows returns "TRUE" or "FALSE"; text cells that merely
+			// read "TRUE" are left as they are
+			if row[j] == "TRUE" || row[j] == "FALSE" {
+				row[j] = strings.ToLower(row[j])
+			}
+		case "", "n":
+			if value, ok := r.dateValue(cell); ok {
+				row[j] = value
+			}
+		}
+	}
+	return row
+}
+
+// formulaValue returns the calculated value of a formula cell. The rows
+// iterator returns the result cached in the workbook, but files written by
+// some libraries store formulas without a cached result. It returns "" if
+// the formula cannot be calculated.
+func (r *xlsxCellResolver) formulaValue(rowNum int, cell xlsxCell) string {
+	name, err := excelize.CoordinatesToCellName(cell.col, rowNum)
+	if err != nil {
+		return ""
+	}
+	value, err := r.file.CalcCellValue(r.sheetName, name)
+	if err != nil {
+		return ""
+	}
+	return value
+}
+
+// dateValue returns the ISO 8601 form of a numeric cell whose style displays
+// a date. Excel stores dates as serial numbers and only the number format
+// marks them as dates, so the formatted value depends on the format (e.g.
+// "01-15-24"). Whole serials become "2006-01-02" and serials with a time
+// part become "2006-01-02 15:04:05".
+func (r *xlsxCellResolver) dateValue(cell xlsxCell) (string, bool) {
+	if cell.style == 0 {
+		return "", false
+	}
+	isDate, ok := r.dateStyles[cell.style]
+	if !ok {
+		isDate = isDateStyle(r.file, cell.style)
+		r.dateStyles[cell.style] = isDate
+	}
+	if !isDate {
+		return "", false
+	}
+
+	serial, err := strconv.ParseFloat(cell.value, 64)
+	if err != nil {
+		return "", false
+	}
+	t, err := excelize.ExcelDateToTime(serial, r.date1904)
+	if err != nil {
+		return "", false
+	}
+	if serial == math.Trunc(serial) {
+		return t.Format(xlsxDateLayout), true
+	}
+	return t.Format(xlsxDateTimeLayout), true
+}
+
+// xlsxCell holds the details of a worksheet cell that the rows iterator of
+// excelize does not expose.
+type xlsxCell struct {
+	// col is the 1-based column of the cell.
+	col int
+	// style is the cell style ID, 0 for the default style.
+	style int
+	// typ is the cell type attribute, e.g. "b" for booleans, "s" for
+	// shared strings, or empty for numbers.
+	typ string
+	// formula reports whether the cell has a formula.
+	formula bool
+	// value is the stored value, e.g. the serial number of a date.
+	value string
+}
+
+// xlsxScannedRow is a row read by xlsxSheetScanner. num is the 1-based row
+// number, or 0 after the last row.
+type xlsxScannedRow struct {
+	num   int
+	cells []xlsxCell
+}
+
+// xlsxSheetScanner reads the rows of a worksheet XML part one at a time.
+type xlsxSheetScanner struct {
+	reader  *bufio.Reader
+	decoder *xml.Decoder
+	row     int
+	done    bool
+}
+
+// newXLSXSheetScanner returns a scanner over the worksheet XML in r.
+func newXLSXSheetScanner(r io.Reader) *xlsxSheetScanner {
+	// The decoder reads byte by byte from a bufio.Reader without buffering
+	// of its own, so mergeCells can continue from where it stopped
+	reader := bufio.NewReader(r)
+	return &xlsxSheetScanner{reader: reader, decoder: xml.NewDecoder(reader)}
+}
+
+// next returns the next row of the sheet. Rows are numbered as excelize does:
+// by their r attribute, or following the previous row.
+func (s *xlsxSheetScanner) next() (xlsxScannedRow, error) {
+	for !s.done {
+		tok, err := s.decoder.RawToken()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return xlsxScannedRow{}, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Local != "row" {
+				continue
+			}
+			s.row++
+			if n, err := strconv.Atoi(xmlAttr(t, "r")); err == nil && n != 0 {
+				s.row = n
+			}
+			cells, err := s.cells()
+			if err != nil {
+				return xlsxScannedRow{}, err
+			}
+			return xlsxScannedRow{num: s.row, cells: cells}, nil
+		case xml.EndElement:
+			if t.Name.Local == "sheetData" {
+				s.done = true
+			}
+		}
+	}
+	s.done = true
+	return xlsxScannedRow{}, nil
+}
+
+// cells reads the cells of the current row up to its end tag.
+func (s *xlsxSheetScanner) cells() ([]xlsxCell, error) {
+	var cells []xlsxCell
+	col := 0
+	var cell *xlsxCell
+	inValue := false
+	for {
+		tok, err := s.decoder.RawToken()
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "c":
+				col++
+				if ref := xmlAttr(t, "r"); ref != "" {
+					if col, _, err = excelize.CellNameToCoordinates(ref); err != nil {
+						return nil, err
+					}
+				}
+				style, _ := strconv.Atoi(xmlAttr(t, "s"))
+				cells = append(cells, xlsxCell{col: col, style: style, typ: xmlAttr(t, "t")})
+				cell = &cells[len(cells)-1]
+			case "f":
+				if cell != nil {
+					cell.formula = true
+				}
+			case "v":
+				inValue = cell != nil
+			}
+		case xml.CharData:
+			if inValue {
+				cell.value += string(t)
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "v":
+				inValue = false
+			case "c":
+				cell = nil
+			case "row":
+				return cells, nil
+			}
+		}
+	}
+}
+
+// mergeCells returns the merged ranges of the sheet, e.g. "A1:B2". They follow
+// the rows in the worksheet XML, so rows not read by next are skipped by
+// searching for the mergeCells element instead of being decoded.
+func (s *xlsxSheetScanner) mergeCells() ([]string, error) {
+	for {
+		if _, err := s.reader.ReadSlice('<'); err != nil {
+			if errors.Is(err, bufio.ErrBufferFull) {
+				continue
+			}
+			if errors.Is(err, io.EOF) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		if xmlLocalName(s.reader) == "mergeCells" {
+			break
+		}
+	}
+
+	var refs []string
+	decoder := xml.NewDecoder(io.MultiReader(strings.NewReader("<"), s.reader))
+	for {
+		tok, err := decoder.RawToken()
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Local == "mergeCell" {
+				refs = append(refs, xmlAttr(t, "ref"))
+			}
+		case xml.EndElement:
+			if t.Name.Local == "mergeCells" {
+				return refs, nil
+			}
+		}
+	}
+}
+
+// maxXMLNameLength bounds the element names checked by xmlLocalName.
+const maxXMLNameLength = 64
+
+// xmlLocalName returns the local name of the element whose tag starts at the
+// next byte of r, just after '<', without consuming it.
+func xmlLocalName(r *bufio.Reader) string {
+	// Peek returns the bytes available even when there are fewer
+	tag, _ := r.Peek(maxXMLNameLength)
+	if i := bytes.IndexAny(tag, " \t\r\n/>"); i >= 0 {
+		tag = tag[:i]
+	}
+	if i := bytes.LastIndexByte(tag, ':'); i >= 0 {
+		tag = tag[i+1:]
+	}
+	return string(tag)
+}
+
+// xmlAttr returns the value of the attribute name of element, or "".
+func xmlAttr(element xml.StartElement, name string) string {
+	for _, attr := range element.Attr {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,169 @@
+package fileparser
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+// rewriteXLSXPart returns the XLSX package data with the part name rewritten
+// by fn.
+func rewriteXLSXPart(t *testing.T, data []byte, name string, fn func(string) string) []byte {
+	t.Helper()
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, file := range zr.File {
+		rc, err := file.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		require.NoError(t, rc.Close())
+
+		if file.Name == name {
+			content = []byte(fn(string(content)))
+		}
+		w, err := zw.Create(file.Name)
+		require.NoError(t, err)
+		_, err = w.Write(content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func TestParseXLSX_MaxRowsStopsReading(t *testing.T) {
+	t.Parallel()
+
+	f := excelize.NewFile()
+	defer f.Close()
+	require.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]any{"team", "score"}))
+	require.NoError(t, f.SetSheetRow("Sheet1", "A2", &[]any{"Red", 1}))
+	require.NoError(t, f.SetSheetRow("Sheet1", "B3", &[]any{2}))
+	require.NoError(t, f.MergeCell("Sheet1", "A2", "A3"))
+	for row := 4; row <= 100; row++ {
+		cell, err := excelize.CoordinatesToCellName(1, row)
+		require.NoError(t, err)
+		require.NoError(t, f.SetSheetRow("Sheet1", cell, &[]any{"Blue", row}))
+	}
+	var buf bytes.Buffer
+	require.NoError(t, f.Write(&buf))
+
+	// Break a cell reference past the limit: reading that row fails, so
+	// parsing succeeds only if rows past the limit are never read
+	data := rewriteXLSXPart(t, buf.Bytes(), "xl/worksheets/sheet1.xml", func(sheet string) string {
+		require.Contains(t, sheet, `<c r="A50"`)
+		return strings.Replace(sheet, `<c r="A50"`, `<c r="A0"`, 1)
+	})
+
+	t.Run("full parse reads the broken row", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := parseXLSX(bytes.NewReader(data), ParseOptions{})
+
+		require.Error(t, err)
+	})
+
+	t.Run("stops before rows past the limit", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := parseXLSX(bytes.NewReader(data), ParseOptions{MaxRows: 3})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"team", "score"}, result.Headers)
+		assert.Equal(t, [][]string{{"Red", "1"}, {"Red", "2"}, {"Blue", "4"}}, result.Records)
+	})
+}
+
+// replaceSheetData returns the XLSX package data with the cells of its first
+// worksheet replaced by sheetData, the XML content of the sheetData element.
+func replaceSheetData(t *testing.T, data []byte, sheetData string) []byte {
+	t.Helper()
+
+	pattern := regexp.MustCompile(`<sheetData>.*</sheetData>|<sheetData/>`)
+	return rewriteXLSXPart(t, data, "xl/worksheets/sheet1.xml", func(sheet string) string {
+		require.Regexp(t, pattern, sheet)
+		return pattern.ReplaceAllLiteralString(sheet, "<sheetData>"+sheetData+"</sheetData>")
+	})
+}
+
+func TestReadXLSXSheet_AlignsScannedCells(t *testing.T) {
+	t.Parallel()
+
+	// The workbook provides the shared strings and a date style (s="1") for
+	// the hand-written cells
+	f := excelize.NewFile()
+	defer f.Close()
+	require.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]any{"name", "when", "note", "a", "plain", "45306"}))
+	dateStyle, err := f.NewStyle(&excelize.Style{NumFmt: 14})
+	require.NoError(t, err)
+	require.Equal(t, 1, dateStyle)
+	require.NoError(t, f.SetCellStyle("Sheet1", "G1", "G1", dateStyle))
+	var buf bytes.Buffer
+	require.NoError(t, f.Write(&buf))
+
+	data := replaceSheetData(t, buf.Bytes(),
+		`<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c><c r="C1" t="s"><v>2</v></c></row>`+
+			// row 2 is missing; a shared string with a date style stays text
+			`<row r="3"><c r="A3" t="s"><v>3</v></c><c r="B3" s="1"><v>45306</v></c><c r="C3" s="1" t="s"><v>5</v></c></row>`+
+			// a row and cells without r attributes follow the previous ones
+			`<row><c s="1" t="inlineStr"><is><t>45307</t></is></c><c s="1"><v>45306.5</v></c><c r="C4" t="b"><v>1</v></c></row>`+
+			// row 5 is missing and the row starts at column B
+			`<row r="6"><c r="B6" s="1"><v>45307</v></c><c r="C6" t="s"><v>4</v></c></row>`)
+
+	wb, err := openXLSX(bytes.NewReader(data), "")
+	require.NoError(t, err)
+	defer wb.Close()
+
+	rows, err := readXLSXSheet(wb, "Sheet1", 0)
+
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"name", "when", "note"},
+		nil,
+		{"a", "2024-01-15", "45306"},
+		{"45307", "2024-01-15 12:00:00", "true"},
+		nil,
+		{"", "2024-01-16", "plain"},
+	}, rows)
+}
+
+func TestReadXLSXSheet_CalculatesOnlyFormulasWithoutResult(t *testing.T) {
+	t.Parallel()
+
+	f := excelize.NewFile()
+	defer f.Close()
+	require.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]any{"n", "cached", "missing", "empty"}))
+	var buf bytes.Buffer
+	require.NoError(t, f.Write(&buf))
+
+	// Cached results are deliberately wrong, so a cell keeps its cached
+	// result only if it was not calculated
+	data := replaceSheetData(t, buf.Bytes(),
+		`<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c><c r="C1" t="s"><v>2</v></c><c r="D1" t="s"><v>3</v></c></row>`+
+			`<row r="2"><c r="A2"><v>2</v></c><c r="B2"><f>A2*10</f><v>99</v></c><c r="C2"><f>A2*10</f></c><c r="D2"><f>A2+1</f><v></v></c></row>`+
+			`<row r="3"><c r="A3" t="inlineStr"><is><t>A2*10</t></is></c><c r="B3" t="str"><f>"x"&amp;A2</f><v>stale</v></c><c r="C3"><f>NOSUCHFUNCTION(A2)</f></c><c r="D3"><v>5</v></c></row>`)
+
+	wb, err := openXLSX(bytes.NewReader(data), "")
+	require.NoError(t, err)
+	defer wb.Close()
+
+	rows, err := readXLSXSheet(wb, "Sheet1", 0)
+
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"n", "cached", "missing", "empty"},
+		{"2", "99", "20", "3"},
+		{"A2*10", "stale", "", "5"},
+	}, rows)
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
 )
 
 func TestParseXLSX(t *testing.T) {
@@ -26,7 +27,7 @@ func TestParseXLSX(t *testing.T) {
 		require.NoError(t, err)
 		defer f.Close()
 
-		result, err := parseXLSX(f)
+		result, err := parseXLSX(f, ParseOptions{})
 
 		require.NoError(t, err)
 		assert.Greater(t, len(result.Headers), 0)
@@ -38,7 +39,7 @@ func TestParseXLSX(t *testing.T) {
 
 		reader := bytes.NewReader([]byte{})
 
-		_, err := parseXLSX(reader)
+		_, err := parseXLSX(reader, ParseOptions{})
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to open XLSX")
@@ -49,7 +50,7 @@ func TestParseXLSX(t *testing.T) {
 
 		reader := strings.NewReader("not an xlsx file")
 
-		_, err := parseXLSX(reader)
+		_, err := parseXLSX(reader, ParseOptions{})
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to open XLSX")
@@ -65,7 +66,7 @@ func TestParseXLSX_ErrorCases(t *testing.T) {
 		// We primarily test the error path through invalid data
 		reader := bytes.NewReader([]byte{0x50, 0x4B, 0x03, 0x04}) // ZIP magic bytes but not valid XLSX
 
-		_, err := parseXLSX(reader)
+		_, err := parseXLSX(reader, ParseOptions{})
 
 		// Should fail during XLSX parsing
 		assert.Error(t, err)
@@ -95,3 +96,399 @@ func TestParse_XLSX_FromTestdata(t *testing.T) {
 		assert.Equal(t, len(result.Headers), len(result.ColumnTypes))
 	})
 }
+
+func TestParseXLSX_ExtractHyperlinks(t *testing.T) {
+	t.Parallel()
+
+	f := excelize.NewFile()
+	defer f.Close()
+	require.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]any{"id", "report"}))
+	require.NoError(t, f.SetSheetRow("Sheet1", "A2", &[]any{1, "Q1 report"}))
+	require.NoError(t, f.SetSheetRow("Sheet1", "A3", &[]any{2, "Q2 report"}))
+	require.NoError(t, f.SetCellHyperLink("Sheet1", "B2", "https://example.com/q1", "External"))
+	var buf bytes.Buffer
+	require.NoError(t, f.Write(&buf))
+	data := buf.Bytes()
+
+	t.Run("adds url column for hyperlinked cells", func(t *testing.T) {
+		t.Parallel()
+
+		opts := ParseOptions{XLSX: XLSXOptions{ExtractHyperlinks: true}}
+		result, err := parseXLSX(bytes.NewReader(data), opts)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"id", "report", "report_url"}, result.Headers)
+		assert.Equal(t, []string{"1", "Q1 report", "https://example.com/q1"}, result.Records[0])
+		assert.Equal(t, []string{"2", "Q2 report", ""}, result.Records[1])
+		assert.Len(t, result.ColumnTypes, 3)
+	})
+
+	t.Run("ignores hyperlinks by default", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := parseXLSX(bytes.NewReader(data), ParseOptions{})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"id", "report"}, result.Headers)
+	})
+}
+
+func TestParseXLSX_SelectSheet(t *testing.T) {
+	t.Parallel()
+
+	f := excelize.NewFile()
+	defer f.Close()
+	require.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]any{"cover"}))
+	_, err := f.NewSheet("Report")
+	require.NoError(t, err)
+	require.NoError(t, f.SetSheetRow("Report", "A1", &[]any{"id", "amount"}))
+	require.NoError(t, f.SetSheetRow("Report", "A2", &[]any{1, 100}))
+	var buf bytes.Buffer
+	require.NoError(t, f.Write(&buf))
+	data := buf.Bytes()
+
+	t.Run("reads first sheet by default", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := parseXLSX(bytes.NewReader(data), ParseOptions{})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"cover"}, result.Headers)
+	})
+
+	t.Run("selects sheet by name", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := parseXLSX(bytes.NewReader(data), ParseOptions{SheetName: "Report"})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"id", "amount"}, result.Headers)
+		assert.Equal(t, [][]string{{"1", "100"}}, result.Records)
+	})
+
+	t.Run("selects sheet by index", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := parseXLSX(bytes.NewReader(data), ParseOptions{SheetIndex: 1})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"id", "amount"}, result.Headers)
+	})
+
+	t.Run("name takes precedence over index", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := parseXLSX(bytes.NewReader(data), ParseOptions{SheetName: "Sheet1", SheetIndex: 1})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"cover"}, result.Headers)
+	})
+
+	t.Run("missing sheet name lists available sheets", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := parseXLSX(bytes.NewReader(data), ParseOptions{SheetName: "Summary"})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `sheet "Summary" not found`)
+		assert.Contains(t, err.Error(), "Sheet1, Report")
+	})
+
+	t.Run("out of range index lists available sheets", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := parseXLSX(bytes.NewReader(data), ParseOptions{SheetIndex: 5})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "sheet index 5 out of range")
+		assert.Contains(t, err.Error(), "Sheet1, Report")
+	})
+}
+
+func TestParseXLSXAllSheets(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns a table per sheet", func(t *testing.T) {
+		t.Parallel()
+
+		f := excelize.NewFile()
+		defer f.Close()
+		require.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]any{"id", "name"}))
+		require.NoError(t, f.SetSheetRow("Sheet1", "A2", &[]any{1, "Alice"}))
+		_, err := f.NewSheet("Report")
+		require.NoError(t, err)
+		require.NoError(t, f.SetSheetRow("Report", "A1", &[]any{"amount"}))
+		require.NoError(t, f.SetSheetRow("Report", "A2", &[]any{1.5}))
+		_, err = f.NewSheet("Empty")
+		require.NoError(t, err)
+		var buf bytes.Buffer
+		require.NoError(t, f.Write(&buf))
+
+		tables, err := ParseXLSXAllSheets(&buf)
+
+		require.NoError(t, err)
+		require.Len(t, tables, 3)
+
+		assert.Equal(t, []string{"id", "name"}, tables["Sheet1"].Headers)
+		assert.Equal(t, [][]string{{"1", "Alice"}}, tables["Sheet1"].Records)
+		assert.Equal(t, []ColumnType{TypeInteger, TypeText}, tables["Sheet1"].ColumnTypes)
+
+		assert.Equal(t, []string{"amount"}, tables["Report"].Headers)
+		assert.Equal(t, []ColumnType{TypeReal}, tables["Report"].ColumnTypes)
+
+		require.Contains(t, tables, "Empty")
+		require.NotNil(t, tables["Empty"])
+		assert.Nil(t, tables["Empty"].Headers)
+		assert.Nil(t, tables["Empty"].Records)
+	})
+
+	t.Run("returns error for invalid sheet headers", func(t *testing.T) {
+		t.Parallel()
+
+		f := excelize.NewFile()
+		defer f.Close()
+		require.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]any{"id", "id"}))
+		var buf bytes.Buffer
+		require.NoError(t, f.Write(&buf))
+
+		_, err := ParseXLSXAllSheets(&buf)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Sheet1")
+	})
+
+	t.Run("returns error for nil reader", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParseXLSXAllSheets(nil)
+		require.Error(t, err)
+	})
+
+	t.Run("returns error for invalid data", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParseXLSXAllSheets(bytes.NewReader([]byte("not a workbook")))
+		require.Error(t, err)
+	})
+}
+
+func TestParseXLSX_DateSerials(t *testing.T) {
+	t.Parallel()
+
+	f := excelize.NewFile()
+	defer f.Close()
+	require.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]any{"builtin", "custom", "datetime", "number", "time"}))
+	require.NoError(t, f.SetSheetRow("Sheet1", "A2", &[]any{45306, 45306, 45306.5, 45306, 0.25}))
+	require.NoError(t, f.SetSheetRow("Sheet1", "A3", &[]any{45307, 45307, 45307.75, 45307, 0.5}))
+
+	builtinStyle, err := f.NewStyle(&excelize.Style{NumFmt: 14})
+	require.NoError(t, err)
+	customFmt := "dd/mm/yyyy"
+	customStyle, err := f.NewStyle(&excelize.Style{CustomNumFmt: &customFmt})
+	require.NoError(t, err)
+	dateTimeFmt := "m/d/yyyy h:mm"
+	dateTimeStyle, err := f.NewStyle(&excelize.Style{CustomNumFmt: &dateTimeFmt})
+	require.NoError(t, err)
+	timeFmt := "[h]:mm"
+	timeStyle, err := f.NewStyle(&excelize.Style{CustomNumFmt: &timeFmt})
+	require.NoError(t, err)
+	require.NoError(t, f.SetCellStyle("Sheet1", "A2", "A3", builtinStyle))
+	require.NoError(t, f.SetCellStyle("Sheet1", "B2", "B3", customStyle))
+	require.NoError(t, f.SetCellStyle("Sheet1", "C2", "C3", dateTimeStyle))
+	require.NoError(t, f.SetCellStyle("Sheet1", "E2", "E3", timeStyle))
+
+	var buf bytes.Buffer
+	require.NoError(t, f.Write(&buf))
+
+	result, err := parseXLSX(&buf, ParseOptions{})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2024-01-15", "2024-01-15", "2024-01-15 12:00:00", "45306", "6:00"}, result.Records[0])
+	assert.Equal(t, []string{"2024-01-16", "2024-01-16", "2024-01-16 18:00:00", "45307", "12:00"}, result.Records[1])
+	assert.Equal(t, TypeDatetime, result.ColumnTypes[0])
+	assert.Equal(t, TypeDatetime, result.ColumnTypes[1])
+	assert.Equal(t, TypeDatetime, result.ColumnTypes[2])
+	assert.Equal(t, TypeInteger, result.ColumnTypes[3])
+}
+
+func TestParseXLSX_BoolCells(t *testing.T) {
+	t.Parallel()
+
+	f := excelize.NewFile()
+	defer f.Close()
+	require.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]any{"active", "label"}))
+	require.NoError(t, f.SetSheetRow("Sheet1", "A2", &[]any{true, "TRUE"}))
+	require.NoError(t, f.SetSheetRow("Sheet1", "A3", &[]any{false, "maybe"}))
+
+	var buf bytes.Buffer
+	require.NoError(t, f.Write(&buf))
+
+	result, err := parseXLSX(&buf, ParseOptions{})
+
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"true", "TRUE"}, {"false", "maybe"}}, result.Records)
+	assert.Equal(t, []ColumnType{TypeBoolean, TypeText}, result.ColumnTypes)
+	assert.Equal(t, true, ParseValue(result.Records[0][0], result.ColumnTypes[0]))
+	assert.Equal(t, false, ParseValue(result.Records[1][0], result.ColumnTypes[0]))
+}
+
+func TestIsDateFormatCode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		code string
+		want bool
+	}{
+		{code: "yyyy-mm-dd", want: true},
+		{code: "d-mmm", want: true},
+		{code: "[$-409]mmmm d, yyyy", want: true},
+		{code: "h:mm:ss", want: false},
+		{code: "[Red]0.00", want: false},
+		{code: `0.00 "days"`, want: false},
+		{code: `#,##0\d`, want: false},
+		{code: "General", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, isDateFormatCode(tt.code))
+		})
+	}
+}
+
+func TestParseXLSX_MergedCellsAndFormulas(t *testing.T) {
+	t.Parallel()
+
+	f := excelize.NewFile()
+	defer f.Close()
+	require.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]any{"team", "score", nil, "total"}))
+	require.NoError(t, f.MergeCell("Sheet1", "B1", "C1"))
+	require.NoError(t, f.SetSheetRow("Sheet1", "A2", &[]any{"Red", 1, 2}))
+	require.NoError(t, f.SetSheetRow("Sheet1", "A3", &[]any{nil, 3, 4}))
+	require.NoError(t, f.MergeCell("Sheet1", "A2", "A3"))
+	require.NoError(t, f.SetSheetRow("Sheet1", "A4", &[]any{"Blue", 5, 6}))
+	for _, row := range []string{"2", "3", "4"} {
+		require.NoError(t, f.SetCellFormula("Sheet1", "D"+row, "=B"+row+"+C"+row))
+	}
+	var buf bytes.Buffer
+	require.NoError(t, f.Write(&buf))
+
+	result, err := parseXLSX(&buf, ParseOptions{})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"team", "score", "score_2", "total"}, result.Headers)
+	assert.Equal(t, [][]string{
+		{"Red", "1", "2", "3"},
+		{"Red", "3", "4", "7"},
+		{"Blue", "5", "6", "11"},
+	}, result.Records)
+	assert.Equal(t, []ColumnType{TypeText, TypeInteger, TypeInteger, TypeInteger}, result.ColumnTypes)
+}
+
+func TestParseXLSX_TextInput(t *testing.T) {
+	t.Parallel()
+
+	_, err := parseXLSX(bytes.NewReader([]byte("a:1\tb:2\n")), ParseOptions{})
+
+	require.ErrorIs(t, err, ErrNotBinaryFormat)
+	assert.Contains(t, err.Error(), "does not look like XLSX")
+	assert.Contains(t, err.Error(), "it may be LTSV or TSV")
+}
+
+func TestParseXLSX_Password(t *testing.T) {
+	t.Parallel()
+
+	f := excelize.NewFile()
+	defer f.Close()
+	require.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]any{"id", "name"}))
+	require.NoError(t, f.SetSheetRow("Sheet1", "A2", &[]any{1, "Laptop"}))
+	var buf bytes.Buffer
+	require.NoError(t, f.Write(&buf, excelize.Options{Password: "secret"}))
+	data := buf.Bytes()
+
+	t.Run("decrypts with the password", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := ParseWithOptions(bytes.NewReader(data), XLSX, ParseOptions{Password: "secret"})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"id", "name"}, result.Headers)
+		assert.Equal(t, [][]string{{"1", "Laptop"}}, result.Records)
+	})
+
+	t.Run("returns ErrEncrypted without a password", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Parse(bytes.NewReader(data), XLSX)
+
+		require.ErrorIs(t, err, ErrEncrypted)
+		assert.Contains(t, err.Error(), "a password is required")
+	})
+
+	t.Run("returns ErrEncrypted for a wrong password", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParseWithOptions(bytes.NewReader(data), XLSX, ParseOptions{Password: "wrong"})
+
+		require.ErrorIs(t, err, ErrEncrypted)
+		assert.Contains(t, err.Error(), "wrong password")
+	})
+
+	t.Run("ignores the password for an unencrypted workbook", func(t *testing.T) {
+		t.Parallel()
+
+		plain := excelize.NewFile()
+		defer plain.Close()
+		require.NoError(t, plain.SetSheetRow("Sheet1", "A1", &[]any{"id"}))
+		var plainBuf bytes.Buffer
+		require.NoError(t, plain.Write(&plainBuf))
+
+		result, err := ParseWithOptions(&plainBuf, XLSX, ParseOptions{Password: "secret"})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"id"}, result.Headers)
+	})
+}
+
+func TestXLSXSheets(t *testing.T) {
+	t.Parallel()
+
+	f := excelize.NewFile()
+	defer f.Close()
+	require.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]any{"id", "name", "price"}))
+	require.NoError(t, f.SetSheetRow("Sheet1", "A2", &[]any{1, "Laptop", 999}))
+	require.NoError(t, f.SetSheetDimension("Sheet1", "A1:C2"))
+	_, err := f.NewSheet("Sparse")
+	require.NoError(t, err)
+	require.NoError(t, f.SetSheetRow("Sparse", "B2", &[]any{"a", "b", "c"}))
+	require.NoError(t, f.SetSheetRow("Sparse", "B5", &[]any{"d"}))
+	_, err = f.NewSheet("Empty")
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	require.NoError(t, f.Write(&buf))
+	data := buf.Bytes()
+
+	t.Run("lists sheets with their sizes", func(t *testing.T) {
+		t.Parallel()
+
+		sheets, err := XLSXSheets(bytes.NewReader(data))
+
+		require.NoError(t, err)
+		assert.Equal(t, []SheetInfo{
+			{Name: "Sheet1", RowCount: 2, ColCount: 3},
+			{Name: "Sparse", RowCount: 5, ColCount: 4},
+			{Name: "Empty", RowCount: 0, ColCount: 0},
+		}, sheets)
+	})
+
+	t.Run("returns error for nil reader and empty data", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := XLSXSheets(nil)
+		require.Error(t, err)
+
+		_, err = XLSXSheets(bytes.NewReader(nil))
+		require.ErrorIs(t, err, ErrEmptyData)
+	})
+}
@@ -0,0 +1,122 @@
+package fileparser
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// parseXML parses XML data holding repeated record elements, such as
+// <rows><row><id>1</id><name>Alice</name></row>...</rows>.
+//
+// Each child element of a record becomes a column named after the element,
+// holding its text content with surrounding whitespace removed. Attributes of
+// the record element become columns named "@" followed by the attribute name.
+// Columns are ordered by first appearance across records; when a record
+// repeats a child element, the last value is kept.
+func parseXML(reader io.Reader, opts ParseOptions) (*TableData, error) {
+	reader = newBOMAwareReader(reader)
+	charsetReader := func(charset string, input io.Reader) (io.Reader, error) {
+		return newDecodingReader(input, charset)
+	}
+	if opts.Encoding != "" {
+		var err error
+		reader, err = newDecodingReader(reader, opts.Encoding)
+		if err != nil {
+			return nil, err
+		}
+		// The input is now UTF-8, whatever encoding it declares
+		charsetReader = func(_ string, input io.Reader) (io.Reader, error) {
+			return input, nil
+		}
+	}
+	decoder := xml.NewDecoder(reader)
+	decoder.CharsetReader = charsetReader
+
+	var (
+		headers     []string
+		headerSeen  = make(map[string]bool)
+		recordMaps  []map[string]string
+		current     map[string]string
+		depth       int
+		recordDepth int
+		field       string
+		text        strings.Builder
+	)
+	setField := func(key, value string) {
+		if !headerSeen[key] {
+			headerSeen[key] = true
+			headers = append(headers, key)
+		}
+		current[key] = value
+	}
+
+	for !opts.reachedMaxRows(len(recordMaps)) {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read XML: %w", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			depth++
+			switch {
+			case current == nil && isXMLRecord(t, depth, opts.XMLRecordElement):
+				current = make(map[string]string)
+				recordDepth = depth
+				for _, attr := range t.Attr {
+					setField("@"+attr.Name.Local, attr.Value)
+				}
+			case current != nil && depth == recordDepth+1:
+				field = t.Name.Local
+				text.Reset()
+			}
+		case xml.EndElement:
+			switch {
+			case current != nil && depth == recordDepth+1:
+				setField(field, strings.TrimSpace(text.String()))
+			case current != nil && depth == recordDepth:
+				recordMaps = append(recordMaps, current)
+				current = nil
+			}
+			depth--
+		case xml.CharData:
+			if current != nil && depth > recordDepth {
+				text.Write(t)
+			}
+		}
+	}
+
+	if len(recordMaps) == 0 || len(headers) == 0 {
+		if opts.XMLRecordElement != "" {
+			return nil, withKind(fmt.Errorf("no XML records found in <%s> elements", opts.XMLRecordElement), ErrEmptyData)
+		}
+		return nil, withKind(errors.New("no XML records found"), ErrEmptyData)
+	}
+
+	records := recordsFromMaps(headers, recordMaps)
+
+	// Infer column types
+	columnTypes := opts.inferColumnTypes(headers, records)
+
+	return &TableData{
+		Headers:     headers,
+		Records:     records,
+		ColumnTypes: columnTypes,
+	}, nil
+}
+
+// isXMLRecord reports whether the element start at the given nesting depth
+// begins a record. Without a record element name, the children of the root
+// element are records.
+func isXMLRecord(start xml.StartElement, depth int, recordElement string) bool {
+	if recordElement == "" {
+		return depth == 2
+	}
+	return start.Name.Local == recordElement
+}
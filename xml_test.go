@@ -0,0 +1,113 @@
+package fileparser
+
+import (
+	"bytes"
+	"compress/gzip"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseXML(t *testing.T) {
+	t.Parallel()
+
+	t.Run("parses child elements and attributes of record elements", func(t *testing.T) {
+		t.Parallel()
+
+		input := `<?xml version="1.0" encoding="UTF-8"?>
+<export>
+  <meta><generated>2024-01-15</generated></meta>
+  <rows>
+    <row id="1">
+      <name>Alice</name>
+      <score>85.5</score>
+    </row>
+    <row id="2">
+      <name> Bob &amp; Co </name>
+      <city>Tokyo</city>
+      <score>92</score>
+    </row>
+  </rows>
+</export>`
+
+		result, err := ParseWithOptions(strings.NewReader(input), XML, ParseOptions{XMLRecordElement: "row"})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"@id", "name", "score", "city"}, result.Headers)
+		assert.Equal(t, [][]string{
+			{"1", "Alice", "85.5", ""},
+			{"2", "Bob & Co", "92", "Tokyo"},
+		}, result.Records)
+		assert.Equal(t, []ColumnType{TypeInteger, TypeText, TypeReal, TypeText}, result.ColumnTypes)
+	})
+
+	t.Run("treats children of the root as records by default", func(t *testing.T) {
+		t.Parallel()
+
+		input := "<rows><row><a>1</a><b>2</b></row><row><a>3</a><b><![CDATA[x<y]]></b></row></rows>"
+
+		result, err := Parse(strings.NewReader(input), XML)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a", "b"}, result.Headers)
+		assert.Equal(t, [][]string{{"1", "2"}, {"3", "x<y"}}, result.Records)
+	})
+
+	t.Run("stops after MaxRows records", func(t *testing.T) {
+		t.Parallel()
+
+		input := "<rows><row><a>1</a></row><row><a>2</a></row><row><a>3</a></row>"
+
+		result, err := ParseWithOptions(strings.NewReader(input), XML, ParseOptions{MaxRows: 2})
+
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{{"1"}, {"2"}}, result.Records)
+	})
+
+	t.Run("decompresses gzip input", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		_, err := gz.Write([]byte("<rows><row><a>1</a></row></rows>"))
+		require.NoError(t, err)
+		require.NoError(t, gz.Close())
+
+		result, err := Parse(&buf, XMLGZ)
+
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{{"1"}}, result.Records)
+	})
+
+	t.Run("decodes the declared encoding", func(t *testing.T) {
+		t.Parallel()
+
+		input := "<?xml version=\"1.0\" encoding=\"windows-1252\"?><rows><row><name>caf\xe9</name></row></rows>"
+
+		result, err := Parse(strings.NewReader(input), XML)
+
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{{"café"}}, result.Records)
+	})
+
+	t.Run("returns an error when no record element matches", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParseWithOptions(strings.NewReader("<rows><row><a>1</a></row></rows>"), XML, ParseOptions{
+			XMLRecordElement: "item",
+		})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "<item>")
+	})
+
+	t.Run("returns an error for malformed XML", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Parse(strings.NewReader("<rows><row><a>1</b></row></rows>"), XML)
+
+		require.Error(t, err)
+	})
+}
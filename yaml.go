@@ -0,0 +1,188 @@
+package fileparser
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// parseYAML parses YAML data holding a sequence of mappings, such as a
+// fixture list of "- {id: 1, name: Alice}" items.
+//
+// Each mapping is a record and its keys are the columns, ordered by first
+// appearance across records. Scalar values are kept as written, and null
+// values become empty strings. Nested mappings and sequences are flattened to
+// compact JSON, e.g. [1,2,3] or {"a":1}, as Parquet nested columns are. A
+// stream of several documents is read as one table.
+func parseYAML(reader io.Reader, opts ParseOptions) (*TableData, error) {
+	reader = newBOMAwareReader(reader)
+	if opts.Encoding != "" {
+		var err error
+		reader, err = newDecodingReader(reader, opts.Encoding)
+		if err != nil {
+			return nil, err
+		}
+	}
+	decoder := yaml.NewDecoder(reader)
+
+	var (
+		headers    []string
+		headerSeen = make(map[string]bool)
+		recordMaps []map[string]string
+	)
+	for !opts.reachedMaxRows(len(recordMaps)) {
+		var doc yaml.Node
+		err := decoder.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read YAML: %w", err)
+		}
+
+		root := resolveYAMLAlias(&doc)
+		if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+			root = resolveYAMLAlias(root.Content[0])
+		}
+		if root.Kind == yaml.ScalarNode && root.Tag == "!!null" {
+			continue // empty document
+		}
+		if root.Kind != yaml.SequenceNode {
+			return nil, fmt.Errorf("YAML document at line %d is not a sequence of mappings", root.Line)
+		}
+
+		for _, item := range root.Content {
+			if opts.reachedMaxRows(len(recordMaps)) {
+				break
+			}
+			item = resolveYAMLAlias(item)
+			if item.Kind != yaml.MappingNode {
+				return nil, fmt.Errorf("YAML item at line %d is not a mapping", item.Line)
+			}
+
+			recordMap := make(map[string]string, len(item.Content)/2)
+			for i := 0; i+1 < len(item.Content); i += 2 {
+				key := item.Content[i].Value
+				value, err := yamlCellValue(item.Content[i+1])
+				if err != nil {
+					return nil, fmt.Errorf("YAML key %q at line %d: %w", key, item.Content[i].Line, err)
+				}
+				recordMap[key] = value
+				if !headerSeen[key] {
+					headerSeen[key] = true
+					headers = append(headers, key)
+				}
+			}
+			recordMaps = append(recordMaps, recordMap)
+		}
+	}
+
+	if len(recordMaps) == 0 || len(headers) == 0 {
+		return nil, withKind(errors.New("no YAML records found"), ErrEmptyData)
+	}
+
+	records := recordsFromMaps(headers, recordMaps)
+
+	// Infer column types
+	columnTypes := opts.inferColumnTypes(headers, records)
+
+	return &TableData{
+		Headers:     headers,
+		Records:     records,
+		ColumnTypes: columnTypes,
+	}, nil
+}
+
+// resolveYAMLAlias returns the node an alias refers to, or node itself.
+func resolveYAMLAlias(node *yaml.Node) *yaml.Node {
+	for node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	return node
+}
+
+// yamlCellValue returns the cell value for a mapping value: scalars as
+// written, null as an empty string, and collections as compact JSON.
+func yamlCellValue(node *yaml.Node) (string, error) {
+	node = resolveYAMLAlias(node)
+	if node.Kind == yaml.ScalarNode {
+		if node.Tag == "!!null" {
+			return "", nil
+		}
+		return node.Value, nil
+	}
+
+	var sb strings.Builder
+	if err := writeYAMLJSON(&sb, node); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// writeYAMLJSON writes node as compact JSON, keeping the key order of
+// mappings. Numbers, booleans, and null are written as JSON literals; all
+// other scalars are written as strings.
+func writeYAMLJSON(sb *strings.Builder, node *yaml.Node) error {
+	node = resolveYAMLAlias(node)
+	switch node.Kind {
+	case yaml.SequenceNode:
+		sb.WriteByte('[')
+		for i, child := range node.Content {
+			if i > 0 {
+				sb.WriteByte(',')
+			}
+			if err := writeYAMLJSON(sb, child); err != nil {
+				return err
+			}
+		}
+		sb.WriteByte(']')
+	case yaml.MappingNode:
+		sb.WriteByte('{')
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if i > 0 {
+				sb.WriteByte(',')
+			}
+			sb.WriteString(quoteJSON(node.Content[i].Value))
+			sb.WriteByte(':')
+			if err := writeYAMLJSON(sb, node.Content[i+1]); err != nil {
+				return err
+			}
+		}
+		sb.WriteByte('}')
+	case yaml.ScalarNode:
+		return writeYAMLScalarJSON(sb, node)
+	default:
+		return fmt.Errorf("unsupported YAML node at line %d", node.Line)
+	}
+	return nil
+}
+
+// writeYAMLScalarJSON writes a scalar node as a JSON literal.
+func writeYAMLScalarJSON(sb *strings.Builder, node *yaml.Node) error {
+	switch node.Tag {
+	case "!!null":
+		sb.WriteString("null")
+		return nil
+	case "!!bool", "!!int", "!!float":
+		var value any
+		if err := node.Decode(&value); err != nil {
+			return fmt.Errorf("failed to decode YAML value %q: %w", node.Value, err)
+		}
+		if f, ok := value.(float64); ok && (math.IsInf(f, 0) || math.IsNaN(f)) {
+			break // not representable in JSON
+		}
+		literal, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("failed to encode YAML value %q: %w", node.Value, err)
+		}
+		sb.Write(literal)
+		return nil
+	}
+	sb.WriteString(quoteJSON(node.Value))
+	return nil
+}
@@ -0,0 +1,98 @@
+package fileparser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseYAML(t *testing.T) {
+	t.Parallel()
+
+	t.Run("parses a sequence of mappings", func(t *testing.T) {
+		t.Parallel()
+
+		input := `- {id: 1, name: Alice, score: 85.5}
+- id: 2
+  name: "Bob"
+  city: Tokyo
+  score: 92
+- id: 3
+  name: ~
+  score: 78.25
+`
+
+		result, err := Parse(strings.NewReader(input), YAML)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"id", "name", "score", "city"}, result.Headers)
+		assert.Equal(t, [][]string{
+			{"1", "Alice", "85.5", ""},
+			{"2", "Bob", "92", "Tokyo"},
+			{"3", "", "78.25", ""},
+		}, result.Records)
+		assert.Equal(t, []ColumnType{TypeInteger, TypeText, TypeReal, TypeText}, result.ColumnTypes)
+	})
+
+	t.Run("flattens nested values to compact JSON", func(t *testing.T) {
+		t.Parallel()
+
+		input := `- id: 1
+  tags: [a, "b c"]
+  meta: {z: 1, a: true, n: null, f: 1.5}
+  defaults: &d {x: "1"}
+- id: 2
+  tags: []
+  meta: {}
+  defaults: *d
+`
+
+		result, err := Parse(strings.NewReader(input), YAML)
+
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{
+			{"1", `["a","b c"]`, `{"z":1,"a":true,"n":null,"f":1.5}`, `{"x":"1"}`},
+			{"2", `[]`, `{}`, `{"x":"1"}`},
+		}, result.Records)
+	})
+
+	t.Run("reads multiple documents and respects MaxRows", func(t *testing.T) {
+		t.Parallel()
+
+		input := "- {a: 1}\n- {a: 2}\n---\n- {a: 3}\n"
+
+		all, err := Parse(strings.NewReader(input), YAML)
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{{"1"}, {"2"}, {"3"}}, all.Records)
+
+		limited, err := ParseWithOptions(strings.NewReader(input), YAML, ParseOptions{MaxRows: 2})
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{{"1"}, {"2"}}, limited.Records)
+	})
+
+	t.Run("returns errors for other document shapes", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name  string
+			input string
+		}{
+			{name: "mapping document", input: "a: 1\nb: 2\n"},
+			{name: "sequence of scalars", input: "- 1\n- 2\n"},
+			{name: "empty input", input: ""},
+			{name: "invalid YAML", input: "- {a: 1\n"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				_, err := Parse(strings.NewReader(tt.input), YAML)
+
+				require.Error(t, err)
+			})
+		}
+	})
+}